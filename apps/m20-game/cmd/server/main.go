@@ -5,10 +5,13 @@ package main
 
 import (
 	"context"
+	"crypto/subtle"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -34,8 +37,34 @@ func main() {
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
 	slog.SetDefault(logger)
 
-	cfg := config.Load()
-	slog.Info("m20-game starting", "port", cfg.Port, "db", cfg.DBPath, "ollama", cfg.OllamaURL)
+	cfg, err := config.Load(os.Args[1:])
+	if err != nil {
+		slog.Error("config load failed", "error", err)
+		os.Exit(1)
+	}
+	// LevelVar lets a config reload change verbosity without rebuilding the logger.
+	var logLevel slog.LevelVar
+	level, _ := config.ParseLogLevel(cfg.LogLevel) // validated by Load
+	logLevel.Set(level)
+	slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: &logLevel})))
+	slog.Info("m20-game starting", "port", cfg.Port, "db", cfg.DBPath, "ai_backend", cfg.AIBackend, "model", cfg.NarratorModel(), "config_file", cfg.File)
+
+	// Game content: the embedded default pack plus any override directories.
+	pack, err := resources.ReadPack(cfg.ContentDirs)
+	if err != nil {
+		slog.Error("failed to load content pack", "error", err)
+		os.Exit(1)
+	}
+	if err := pack.Validate(character.StatNames()); err != nil {
+		slog.Error("content pack failed validation (run cmd/contentcheck for details)", "error", err)
+		os.Exit(1)
+	}
+	if err := pack.Resolve(); err != nil {
+		slog.Error("failed to resolve content pack", "error", err)
+		os.Exit(1)
+	}
+	resources.Use(pack)
+	slog.Info("content pack loaded", "version", pack.Info.Version, "sources", len(pack.Info.Sources))
 
 	// Ensure DB directory exists
 	if err := os.MkdirAll(filepath.Dir(cfg.DBPath), 0755); err != nil {
@@ -50,7 +79,65 @@ func main() {
 	}
 	defer store.Close()
 
-	aiClient := ai.NewClient(cfg.OllamaURL)
+	encounters, err := game.NewEncounterStore(store.DB())
+	if err != nil {
+		slog.Error("failed to open encounter store", "error", err)
+		os.Exit(1)
+	}
+
+	worlds, err := game.NewWorldStore(store.DB())
+	if err != nil {
+		slog.Error("failed to open world store", "error", err)
+		os.Exit(1)
+	}
+
+	riddles, err := game.NewRiddleStore(store.DB())
+	if err != nil {
+		slog.Error("failed to open riddle store", "error", err)
+		os.Exit(1)
+	}
+
+	parties, err := character.NewPartyStore(store.DB())
+	if err != nil {
+		slog.Error("failed to open party store", "error", err)
+		os.Exit(1)
+	}
+
+	journal, err := character.NewJournalStore(store.DB())
+	if err != nil {
+		slog.Error("failed to open journal store", "error", err)
+		os.Exit(1)
+	}
+
+	// Narration: prompt templates (embedded, plus prompt_dir overrides) sent
+	// to the configured backend.
+	prompts, err := ai.LoadPrompts(cfg.PromptDir)
+	if err != nil {
+		slog.Error("failed to load prompt templates", "error", err)
+		os.Exit(1)
+	}
+	aiClient := ai.NewClient(newNarrator(cfg), prompts, cfg.NarratorModel(), cfg.NarrationStyle)
+
+	// Narration pool: lines generated ahead of time in the background so
+	// building entry and combat don't wait on the model.
+	if cfg.NarrationPool > 0 {
+		poolStore, err := ai.NewPoolStore(store.DB())
+		if err != nil {
+			slog.Error("failed to open narration pool store", "error", err)
+			os.Exit(1)
+		}
+		aiClient.UsePool(ai.NewPool(poolStore, cfg.NarrationPool))
+		go aiClient.RunPool(context.Background(), cfg.NarrationTimeout, narrationWarmJobs())
+	}
+
+	// Hot reload: SIGHUP or config file change applies the safe subset live.
+	reloader := config.NewReloader(cfg, os.Args[1:], func(next *config.Config) {
+		level, _ := config.ParseLogLevel(next.LogLevel)
+		logLevel.Set(level)
+		aiClient.SetModel(next.NarratorModel())
+		aiClient.SetStyle(next.NarrationStyle)
+	})
+	go reloader.Watch(context.Background(), 5*time.Second)
 
 	mux := http.NewServeMux()
 
@@ -58,20 +145,64 @@ func main() {
 	mux.Handle("/metrics", promhttp.Handler())
 
 	// All game routes run through SRE middleware
-	api := buildAPIHandler(store, aiClient)
+	api := buildAPIHandler(reloader, store, parties, journal, encounters, worlds, riddles, aiClient)
 	mux.Handle("/", sreMiddleware(api))
 
-	addr := ":" + cfg.Port
-	slog.Info("server ready", "addr", addr)
-	if err := http.ListenAndServe(addr, mux); err != nil {
+	srv := &http.Server{
+		Addr:         ":" + cfg.Port,
+		Handler:      mux,
+		ReadTimeout:  cfg.ReadTimeout,
+		WriteTimeout: cfg.WriteTimeout,
+	}
+	slog.Info("server ready", "addr", srv.Addr)
+	if err := srv.ListenAndServe(); err != nil {
 		slog.Error("server failed", "error", err)
 		os.Exit(1)
 	}
 }
 
+// newNarrator picks the AI backend named by cfg.AIBackend (validated by
+// config) and puts it behind the circuit breaker and in-flight limit.
+func newNarrator(cfg *config.Config) ai.Narrator {
+	var backend ai.Narrator
+	switch cfg.AIBackend {
+	case ai.BackendOpenAI:
+		backend = ai.NewOpenAI(cfg.OpenAIURL, cfg.OpenAIAPIKey, cfg.OllamaTimeout)
+	case ai.BackendFake:
+		backend = ai.NewFake()
+	default:
+		backend = ai.NewOllama(cfg.OllamaURL, cfg.OllamaTimeout)
+	}
+	return ai.NewGuard(backend, ai.GuardOptions{
+		MaxInFlight: cfg.AIMaxInFlight,
+		QueueSize:   cfg.AIQueueSize,
+		Failures:    cfg.AIBreakerFailures,
+		Cooldown:    cfg.AIBreakerCooldown,
+	})
+}
+
+// narrationWarmJobs lists the pooled lines worth generating before anyone
+// asks: every building group's entrance and its leader's opening line.
+// Combat lines are pooled as they come up.
+func narrationWarmJobs() []ai.PoolJob {
+	var jobs []ai.PoolJob
+	leaders := map[string]bool{}
+	for _, b := range resources.Buildings() {
+		for _, g := range resources.GroupsForBuilding(b.Name) {
+			jobs = append(jobs, ai.PoolJob{Type: "entrance", Data: ai.PromptData{Building: b.Name, Group: g.Name}})
+			if n := len(g.Monsters); n > 0 && !leaders[g.Monsters[n-1].Name] {
+				leaders[g.Monsters[n-1].Name] = true
+				jobs = append(jobs, ai.PoolJob{Type: "dialogue", Data: ai.PromptData{Monster: g.Monsters[n-1].Name}})
+			}
+		}
+	}
+	return jobs
+}
+
 // buildAPIHandler wires all routes and returns the main handler.
-func buildAPIHandler(store *character.Store, aiClient *ai.Client) http.Handler {
+func buildAPIHandler(cfgs *config.Reloader, store *character.Store, parties *character.PartyStore, journal *character.JournalStore, encounters *game.EncounterStore, worlds *game.WorldStore, riddles *game.RiddleStore, aiClient *ai.Client) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cfg := cfgs.Current() // snapshot per request; may change on reload
 		path := r.URL.Path
 		method := r.Method
 
@@ -80,6 +211,10 @@ func buildAPIHandler(store *character.Store, aiClient *ai.Client) http.Handler {
 		case path == "/health" && method == http.MethodGet:
 			writeJSON(w, http.StatusOK, map[string]string{"status": "up"})
 
+		// ── Effective config (secrets redacted) ─────────────────────────────
+		case path == "/config" && method == http.MethodGet:
+			writeJSON(w, http.StatusOK, cfg.Redacted())
+
 		// ── Tile generation ─────────────────────────────────────────────────
 		case path == "/api/tile" && method == http.MethodGet:
 			handleTile(w, r)
@@ -90,36 +225,66 @@ func buildAPIHandler(store *character.Store, aiClient *ai.Client) http.Handler {
 
 		// ── Scavenging ───────────────────────────────────────────────────────
 		case path == "/api/scavenge" && method == http.MethodGet:
-			handleScavenge(w, r)
+			handleScavenge(w, r, store)
 
 		// ── Items / crafting ─────────────────────────────────────────────────
 		case path == "/api/items" && method == http.MethodGet:
 			handleItems(w, r)
 
 		case path == "/api/craft" && method == http.MethodPost:
-			handleCraft(w, r)
+			handleCraft(w, r, store)
 
 		// ── Combat ───────────────────────────────────────────────────────────
 		case path == "/api/combat/roll" && method == http.MethodPost:
-			handleCombatRoll(w, r)
+			handleCombatRoll(w, r, store)
 
 		case path == "/api/combat/encounter" && method == http.MethodPost:
-			handleCombatEncounter(w, r, aiClient)
+			handleCombatEncounter(w, r, store, journal, aiClient, cfg.NarrationTimeout, cfg.NarrationMemory)
+
+		// ── Encounters (server-authoritative combat) ─────────────────────────
+		case path == "/api/encounter" && method == http.MethodPost:
+			handleStartEncounter(w, r, store, journal, parties, encounters, worlds, cfg.XPSplit, cfg.AdminToken)
+
+		case strings.HasPrefix(path, "/api/encounter/"):
+			handleEncounterByID(w, r, path, store, journal, encounters, worlds, aiClient, cfg.NarrationTimeout, cfg.NarrationMemory)
+
+		// ── Worlds (persistent maps, server-side fog of war) ─────────────────
+		case path == "/api/world" && method == http.MethodPost:
+			handleCreateWorld(w, r, store, worlds, cfg.AdminToken)
+
+		case strings.HasPrefix(path, "/api/world/"):
+			handleWorldByID(w, r, path, store, journal, parties, encounters, worlds, cfg.XPSplit)
 
 		// ── Building entry ────────────────────────────────────────────────────
 		case path == "/api/building/enter" && method == http.MethodPost:
-			handleBuildingEnter(w, r, aiClient)
+			handleBuildingEnter(w, r, store, journal, aiClient, cfg.NarrationTimeout, cfg.NarrationMemory, cfg.AdminToken)
 
 		// ── AI / Sphinx ───────────────────────────────────────────────────────
 		case path == "/api/ai/riddle" && method == http.MethodGet:
-			handleRiddle(w, r, aiClient)
+			handleRiddle(w, r, aiClient, cfg.RiddleTimeout)
+
+		case path == "/api/narration/stream" && method == http.MethodGet:
+			handleNarrationStream(w, r, journal, aiClient, cfg.NarrationTimeout, cfg.NarrationMemory)
+
+		case path == "/api/riddle" && method == http.MethodPost:
+			handleStartRiddle(w, r, store, encounters, riddles, aiClient, cfg.RiddleTimeout, cfg.AdminToken)
+
+		case strings.HasPrefix(path, "/api/riddle/"):
+			handleRiddleByID(w, r, path, store, journal, encounters, worlds, riddles)
 
 		// ── Character ────────────────────────────────────────────────────────
 		case path == "/api/character" && method == http.MethodPost:
 			handleCreateCharacter(w, r, store)
 
+		// ── Parties ──────────────────────────────────────────────────────────
+		case path == "/api/party" && method == http.MethodPost:
+			handleCreateParty(w, r, store, parties)
+
+		case strings.HasPrefix(path, "/api/party/"):
+			handlePartyByID(w, r, path, store, parties, worlds, cfg.AdminToken)
+
 		case strings.HasPrefix(path, "/api/character/"):
-			handleCharacterByID(w, r, path, store)
+			handleCharacterByID(w, r, path, store, journal, encounters, worlds, cfg.NarrationMemory, cfg.AdminToken)
 
 		// ── Admin page ───────────────────────────────────────────────────────
 		case path == "/admin" && method == http.MethodGet:
@@ -142,216 +307,1439 @@ func buildAPIHandler(store *character.Store, aiClient *ai.Client) http.Handler {
 // ── Handlers ──────────────────────────────────────────────────────────────────
 
 func handleTile(w http.ResponseWriter, r *http.Request) {
+	seed, ok := requestSeed(w, r)
+	if !ok {
+		return
+	}
 	id := r.URL.Query().Get("id")
 	if id == "" {
 		id = uuid.New().String()[:8]
 	}
-	writeJSON(w, http.StatusOK, game.GenerateTile(id))
+	writeJSON(w, http.StatusOK, struct {
+		game.Tile
+		Seed int64 `json:"seed"`
+	}{game.GenerateTile(game.NewRNG(seed), id), seed})
 }
 
 func handleLand(w http.ResponseWriter, r *http.Request) {
+	seed, ok := requestSeed(w, r)
+	if !ok {
+		return
+	}
 	var req struct {
 		TileCount int `json:"tileCount"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.TileCount == 0 {
 		req.TileCount = 9 // 3x3 default map
 	}
-	writeJSON(w, http.StatusOK, game.GenerateLand(req.TileCount))
+	writeJSON(w, http.StatusOK, game.GenerateLand(seed, req.TileCount))
 }
 
-func handleScavenge(w http.ResponseWriter, r *http.Request) {
+// handleScavenge runs a scavenge check.
+// GET /api/scavenge?level=N&class=...  or  ?character_id=... (effective scouting + class)
+func handleScavenge(w http.ResponseWriter, r *http.Request, store *character.Store) {
+	seed, ok := requestSeed(w, r)
+	if !ok {
+		return
+	}
 	level, _ := strconv.Atoi(r.URL.Query().Get("level"))
 	if level < 1 {
 		level = 3 // default scouting level
 	}
-	writeJSON(w, http.StatusOK, game.Scavenge(level))
+	class := r.URL.Query().Get("class")
+	c, ok := loadOptionalCharacter(w, r, store, r.URL.Query().Get("character_id"))
+	if !ok {
+		return
+	}
+	if c != nil {
+		level, class = c.EffectiveStats().Scouting, c.Class
+	}
+	writeJSON(w, http.StatusOK, seededScavenge{game.Scavenge(game.NewRNG(seed), level, game.ClassModifiers(class)), seed})
 }
 
+// handleItems returns the game data clients need from the active content
+// pack, with the pack's version and source files under "content".
 func handleItems(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"content":        resources.Current().Info,
 		"supplies":       resources.Supplies(),
 		"craftable":      resources.CraftableItems(),
 		"classes":        resources.Classes(),
-		"equip_bonuses":  resources.EquipBonuses,
-		"special_groups": resources.SpecialGroups,
+		"equip_bonuses":  resources.EquipBonuses(),
+		"special_groups": resources.SpecialGroups(),
+		"item_effects":   resources.ItemEffects(),
+		"statuses":       resources.StatusConditions(),
+		"vehicles":       resources.Vehicles(),
 	})
 }
 
-func handleCraft(w http.ResponseWriter, r *http.Request) {
+func handleCraft(w http.ResponseWriter, r *http.Request, store *character.Store) {
 	var req struct {
-		Materials    []string `json:"materials"`
-		CraftingLevel int    `json:"crafting_level"`
+		Materials      []string `json:"materials"`
+		CraftingLevel  int      `json:"crafting_level"`
+		CharacterClass string   `json:"character_class"` // optional: applies crafting discounts
+		CharacterID    string   `json:"character_id"`    // optional: effective crafting, class, and inventory
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		writeError(w, http.StatusBadRequest, "INVALID_BODY", "invalid JSON body", "send {materials: [...], crafting_level: N}")
 		return
 	}
+	c, ok := loadOptionalCharacter(w, r, store, req.CharacterID)
+	if !ok {
+		return
+	}
+	if c != nil {
+		req.CraftingLevel, req.CharacterClass = c.EffectiveStats().Crafting, c.Class
+		if len(req.Materials) == 0 {
+			req.Materials = c.Inventory
+		}
+	}
 	if len(req.Materials) == 0 {
 		writeError(w, http.StatusBadRequest, "NO_MATERIALS", "no materials provided", "include at least one material name")
 		return
 	}
-	craftable := resources.CanCraft(req.Materials, req.CraftingLevel)
+	craftable := game.CraftableWith(req.Materials, req.CraftingLevel, game.ClassModifiers(req.CharacterClass))
 	writeJSON(w, http.StatusOK, map[string]interface{}{
 		"craftable": craftable,
 		"count":     len(craftable),
 	})
 }
 
-func handleCombatRoll(w http.ResponseWriter, r *http.Request) {
-	var req game.CombatRollRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+// handleCombatRoll rolls a D20 check.
+// POST /api/combat/roll  {"stat": N, "bonus": N, "target": N}
+// With "character_id", stat and class come from the character's effective stats.
+func handleCombatRoll(w http.ResponseWriter, r *http.Request, store *character.Store) {
+	seed, ok := requestSeed(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		game.CombatRollRequest
+		CharacterID string `json:"character_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
 		writeError(w, http.StatusBadRequest, "INVALID_BODY", "invalid JSON body", "send {stat: N, bonus: N}")
 		return
 	}
+	req := body.CombatRollRequest
+	c, ok := loadOptionalCharacter(w, r, store, body.CharacterID)
+	if !ok {
+		return
+	}
+	if c != nil {
+		req.Class = c.Class
+		req.StatValue = game.AttackStat(c.Class, c.EffectiveStats())
+	}
 	critThreshold := 20
 	if ct, _ := strconv.Atoi(r.URL.Query().Get("crit_threshold")); ct > 0 {
 		critThreshold = ct
 	}
-	writeJSON(w, http.StatusOK, game.Roll(req, critThreshold))
+	writeJSON(w, http.StatusOK, struct {
+		game.CombatRollResult
+		Seed int64 `json:"seed"`
+	}{game.Roll(game.NewRNG(seed), req, critThreshold), seed})
 }
 
-func handleRiddle(w http.ResponseWriter, r *http.Request, aiClient *ai.Client) {
-	ctx, cancel := context.WithTimeout(r.Context(), 25*time.Second)
+// handleRiddle returns a riddle to read, without its answer. To play one,
+// start a session with POST /api/riddle.
+func handleRiddle(w http.ResponseWriter, r *http.Request, aiClient *ai.Client, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
 	defer cancel()
 	result := aiClient.GenerateRiddle(ctx)
 	writeJSON(w, http.StatusOK, result)
 }
 
+// handleStartRiddle opens a riddle session; the answer stays on the server.
+// POST /api/riddle  {"character_id": "...", "encounter_id": "..."}
+// With encounter_id the riddle settles that fight's riddle-keeper (the
+// Sphinx): solving defeats it, failing costs the solver HP. The insight roll
+// (scouting + class riddle bonus vs the keeper's Defense) can earn a hint.
+// Without it the riddle is practice: nothing is won or lost. A character has
+// at most one pending riddle per encounter (or standalone); starting again
+// returns that one with 200 instead of rolling a new riddle.
+func handleStartRiddle(w http.ResponseWriter, r *http.Request, store *character.Store, encounters *game.EncounterStore, riddles *game.RiddleStore, aiClient *ai.Client, timeout time.Duration, adminToken string) {
+	seed, ok := actionSeed(w, r, adminToken)
+	if !ok {
+		return
+	}
+	var req struct {
+		CharacterID string `json:"character_id"`
+		EncounterID string `json:"encounter_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.CharacterID == "" {
+		writeError(w, http.StatusBadRequest, "INVALID_BODY", "character_id required", `send {"character_id": "...", "encounter_id": "..."}`)
+		return
+	}
+	c, ok := loadOptionalCharacter(w, r, store, req.CharacterID)
+	if !ok {
+		return
+	}
+	if c.HP <= 0 {
+		writeError(w, http.StatusBadRequest, "CHARACTER_DOWN", c.Name+" is down", "heal before facing the Sphinx")
+		return
+	}
+
+	defense := resources.MonsterByName("Sphinx").Defense
+	if req.EncounterID != "" {
+		e, err := encounters.Load(r.Context(), req.EncounterID)
+		if err != nil {
+			slog.Error("load encounter failed", "id", req.EncounterID, "error", err)
+			writeError(w, http.StatusInternalServerError, "STORE_ERROR", "failed to load encounter", "check server logs")
+			return
+		}
+		if e == nil {
+			writeError(w, http.StatusNotFound, "ENCOUNTER_NOT_FOUND", "no encounter with that ID", "start one with POST /api/encounter")
+			return
+		}
+		keeper := e.RiddleKeeper()
+		if e.Status != game.EncounterActive || keeper == nil {
+			writeError(w, http.StatusConflict, "NO_RIDDLE_KEEPER", game.ErrNoRiddleKeeper.Error(), `fight the "Sphinx Chamber" group`)
+			return
+		}
+		if cb := e.Fighter(c.ID); cb == nil || !cb.Alive() {
+			writeError(w, http.StatusConflict, "NOT_FIGHTING", game.ErrNotFighting.Error(), "only a fighter still standing can answer")
+			return
+		}
+		defense = keeper.Defense
+	}
+
+	pending, err := riddles.Pending(r.Context(), c.ID, req.EncounterID)
+	if err != nil {
+		slog.Error("find pending riddle failed", "character", c.ID, "error", err)
+		writeError(w, http.StatusInternalServerError, "STORE_ERROR", "failed to load riddle", "check server logs")
+		return
+	}
+	if pending != nil {
+		writeJSON(w, http.StatusOK, pending)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+	gen := aiClient.GenerateRiddle(ctx)
+
+	session := game.NewRiddleSession(uuid.New().String(), c.ID, gen.Riddle, gen.Answer, seed,
+		c.EffectiveStats().Scouting, defense, game.ClassModifiers(c.Class))
+	session.EncounterID = req.EncounterID
+	session.Fallback = gen.Fallback
+	session, created, err := riddles.Create(r.Context(), session)
+	if err != nil {
+		slog.Error("save riddle failed", "error", err)
+		writeError(w, http.StatusInternalServerError, "STORE_ERROR", "failed to save riddle", "check server logs")
+		return
+	}
+	if !created {
+		writeJSON(w, http.StatusOK, session) // a concurrent start won
+		return
+	}
+	writeJSON(w, http.StatusCreated, session)
+}
+
+// handleRiddleByID routes /api/riddle/:id sub-resources.
+//
+// Routes:
+//   GET  /api/riddle/:id        — session state (the answer only once it is over)
+//   POST /api/riddle/:id/guess  — {"guess": "..."}
+func handleRiddleByID(w http.ResponseWriter, r *http.Request, path string, store *character.Store, journal *character.JournalStore, encounters *game.EncounterStore, worlds *game.WorldStore, riddles *game.RiddleStore) {
+	parts := strings.SplitN(strings.TrimPrefix(path, "/api/riddle/"), "/", 2)
+	id := parts[0]
+	sub := ""
+	if len(parts) == 2 {
+		sub = parts[1]
+	}
+	if id == "" {
+		writeError(w, http.StatusBadRequest, "MISSING_ID", "riddle ID required", "use /api/riddle/:id")
+		return
+	}
+
+	switch {
+	case sub == "" && r.Method == http.MethodGet:
+		session, err := riddles.Load(r.Context(), id)
+		if err != nil {
+			slog.Error("load riddle failed", "id", id, "error", err)
+			writeError(w, http.StatusInternalServerError, "STORE_ERROR", "failed to load riddle", "check server logs")
+			return
+		}
+		if session == nil {
+			writeError(w, http.StatusNotFound, "RIDDLE_NOT_FOUND", "no riddle with that ID", "start one with POST /api/riddle")
+			return
+		}
+		writeJSON(w, http.StatusOK, session)
+
+	case sub == "guess" && r.Method == http.MethodPost:
+		handleRiddleGuess(w, r, id, store, journal, encounters, worlds, riddles)
+
+	default:
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "route not found", "check path and method")
+	}
+}
+
+// handleRiddleGuess checks a guess. When a riddle started from an encounter
+// ends, the outcome lands on that fight: the keeper is defeated (its XP is
+// paid out with the rest of the encounter) or the solver is hit. A standalone
+// riddle is practice and changes nothing on the character.
+func handleRiddleGuess(w http.ResponseWriter, r *http.Request, id string, store *character.Store, journal *character.JournalStore, encounters *game.EncounterStore, worlds *game.WorldStore, riddles *game.RiddleStore) {
+	var req struct {
+		Guess string `json:"guess"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || strings.TrimSpace(req.Guess) == "" {
+		writeError(w, http.StatusBadRequest, "INVALID_BODY", "guess required", `send {"guess": "..."}`)
+		return
+	}
+	var correct bool
+	session, err := riddles.Update(r.Context(), id, func(s *game.RiddleSession) error {
+		var err error
+		correct, err = s.Guess(req.Guess)
+		return err
+	})
+	switch {
+	case errors.Is(err, game.ErrRiddleOver):
+		writeError(w, http.StatusConflict, "RIDDLE_OVER", err.Error(), "start a new riddle")
+		return
+	case err != nil:
+		slog.Error("riddle guess failed", "id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "STORE_ERROR", "failed to update riddle", "check server logs")
+		return
+	case session == nil:
+		writeError(w, http.StatusNotFound, "RIDDLE_NOT_FOUND", "no riddle with that ID", "start one with POST /api/riddle")
+		return
+	}
+
+	resp := map[string]interface{}{
+		"riddle":  session,
+		"correct": correct,
+	}
+	if session.Status == game.RiddlePending {
+		writeJSON(w, http.StatusOK, resp)
+		return
+	}
+	solved := session.Status == game.RiddleSolved
+
+	if session.EncounterID != "" {
+		var events []game.EncounterEvent
+		e, err := encounters.Update(r.Context(), session.EncounterID, func(e *game.Encounter) error {
+			var err error
+			events, err = e.ResolveRiddle(session.CharacterID, solved)
+			return err
+		})
+		switch {
+		case errors.Is(err, game.ErrEncounterOver), errors.Is(err, game.ErrNoRiddleKeeper), errors.Is(err, game.ErrNotFighting):
+			// The fight moved on without the riddle; nothing left to settle.
+			slog.Warn("riddle outcome not applied", "riddle", session.ID, "encounter", session.EncounterID, "reason", err)
+		case err != nil:
+			slog.Error("resolve riddle failed", "riddle", session.ID, "encounter", session.EncounterID, "error", err)
+			writeError(w, http.StatusInternalServerError, "STORE_ERROR", "failed to update encounter", "check server logs")
+			return
+		case e != nil:
+			if events, err = settleEncounter(r.Context(), store, journal, encounters, worlds, e, events); err != nil {
+				slog.Error("settle encounter failed", "encounter", e.ID, "error", err)
+			}
+			resp["encounter"], resp["events"] = e, events
+		}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
 // handleBuildingEnter enters a building and returns its monster group + Ollama flavor text.
 // POST /api/building/enter  {"building": "Hospital", "character_class": "Brawler"}
-func handleBuildingEnter(w http.ResponseWriter, r *http.Request, aiClient *ai.Client) {
+// With "stream": true, flavor_text is left empty and narration_stream gives
+// the URL to stream it from (see handleNarrationStream). With "character_id",
+// the visit goes in that character's journal and the narration can recall
+// their recent history.
+func handleBuildingEnter(w http.ResponseWriter, r *http.Request, store *character.Store, journal *character.JournalStore, aiClient *ai.Client, timeout time.Duration, memory int, adminToken string) {
 	var req struct {
 		Building       string `json:"building"`
 		CharacterClass string `json:"character_class"`
+		CharacterID    string `json:"character_id"`
+		Stream         bool   `json:"stream"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		writeError(w, http.StatusBadRequest, "INVALID_BODY", "invalid JSON body", "send {building: \"...\", character_class: \"...\"}")
 		return
 	}
+	c, ok := loadOptionalCharacter(w, r, store, req.CharacterID)
+	if !ok {
+		return
+	}
+	seed, ok := seedFor(w, r, c != nil, adminToken)
+	if !ok {
+		return
+	}
 
-	instance := game.GenerateSingleBuilding(req.Building)
+	instance := game.GenerateSingleBuilding(game.NewRNG(seed), req.Building)
 
-	// Ask Ollama to set the scene — 10s timeout, fallback if unavailable.
-	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
-	defer cancel()
-	flavorText := aiClient.BuildingEntrance(ctx, instance.Building.Name, instance.MonsterGroup.Name)
+	// Ask Ollama to set the scene — narration timeout, fallback if unavailable.
+	// History is read before this visit is recorded.
+	var flavorText, streamURL, history string
+	if c != nil {
+		history = journalHistory(r.Context(), journal, c.ID, memory)
+		addJournal(r.Context(), journal, character.JournalEntry{CharacterID: c.ID, Kind: character.JournalBuilding, Subject: instance.Building.Name})
+	}
+	if req.Stream {
+		streamURL = narrationStreamURL("entrance", url.Values{"building": {instance.Building.Name}, "group": {instance.MonsterGroup.Name}}, req.CharacterID)
+	} else {
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+		flavorText = aiClient.BuildingEntrance(ctx, instance.Building.Name, instance.MonsterGroup.Name, history)
+	}
 
 	// Get the leader monster's opening line.
 	var leaderDialogue string
 	if len(instance.MonsterGroup.Monsters) > 0 {
 		leader := instance.MonsterGroup.Monsters[len(instance.MonsterGroup.Monsters)-1]
-		ctx2, cancel2 := context.WithTimeout(r.Context(), 10*time.Second)
+		ctx2, cancel2 := context.WithTimeout(r.Context(), timeout)
 		defer cancel2()
 		leaderDialogue = aiClient.MonsterDialogue(ctx2, leader.Name)
 	}
 
 	writeJSON(w, http.StatusOK, map[string]interface{}{
-		"building":        instance.Building,
-		"monster_group":   instance.MonsterGroup,
-		"flavor_text":     flavorText,
-		"leader_dialogue": leaderDialogue,
+		"building":         instance.Building,
+		"monster_group":    instance.MonsterGroup,
+		"flavor_text":      flavorText,
+		"narration_stream": streamURL,
+		"leader_dialogue":  leaderDialogue,
+		"seed":             seed,
+	})
+}
+
+// handleNarrationStream streams one narration line as server-sent events, so
+// the client can show it as the model writes it.
+//
+//	GET /api/narration/stream?type=entrance&building=Hospital&group=Zombie+Ward
+//	GET /api/narration/stream?type=combat_hit&monster=Zombie&class=Brawler&crit=true
+//	GET /api/narration/stream?type=combat_miss&monster=Zombie&crit=false
+//
+// Add character_id to bring in that character's journal (see journalHistory).
+// Events: "chunk" {"text": "..."} as text arrives, then one "done"
+// {"text": full line, "fallback": bool}. The narration timeout applies, and
+// closing the connection stops generation.
+func handleNarrationStream(w http.ResponseWriter, r *http.Request, journal *character.JournalStore, aiClient *ai.Client, timeout time.Duration, memory int) {
+	q := r.URL.Query()
+	reqType := q.Get("type")
+	data := ai.PromptData{
+		Building: q.Get("building"),
+		Group:    q.Get("group"),
+		Monster:  q.Get("monster"),
+		Class:    q.Get("class"),
+		Crit:     q.Get("crit") == "true",
+		History:  journalHistory(r.Context(), journal, q.Get("character_id"), memory),
+	}
+	// Only names from the content pack reach the prompt.
+	switch reqType {
+	case "entrance":
+		if !knownBuilding(data.Building) || resources.GroupByName(data.Group) == nil {
+			writeError(w, http.StatusBadRequest, "INVALID_NARRATION", "unknown building or monster group", "use names from /api/building/enter")
+			return
+		}
+	case "combat_hit", "combat_miss":
+		if resources.MonsterByName(data.Monster) == nil || (reqType == "combat_hit" && resources.ClassByName(data.Class) == nil) {
+			writeError(w, http.StatusBadRequest, "INVALID_NARRATION", "unknown monster or class", "use names from the encounter")
+			return
+		}
+	default:
+		writeError(w, http.StatusBadRequest, "INVALID_NARRATION", "unknown narration type", "type must be entrance, combat_hit, or combat_miss")
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	_ = rc.SetWriteDeadline(time.Now().Add(timeout + 5*time.Second))
+
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+	text, fallback := aiClient.Stream(ctx, reqType, data, func(chunk string) {
+		writeSSE(w, rc, "chunk", map[string]string{"text": chunk})
 	})
+	if r.Context().Err() != nil {
+		return // browser went away
+	}
+	writeSSE(w, rc, "done", map[string]interface{}{"text": text, "fallback": fallback})
+}
+
+// narrationStreamURL is the handleNarrationStream URL for one line, narrated
+// for characterID if it is set.
+func narrationStreamURL(reqType string, params url.Values, characterID string) string {
+	params.Set("type", reqType)
+	if characterID != "" {
+		params.Set("character_id", characterID)
+	}
+	return "/api/narration/stream?" + params.Encode()
+}
+
+func knownBuilding(name string) bool {
+	for _, b := range resources.Buildings() {
+		if b.Name == name {
+			return true
+		}
+	}
+	return false
 }
 
 // handleCombatEncounter runs a single combat roll against a named monster with AI narration.
 // POST /api/combat/encounter  {"monster": "Zombie", "stat": 5, "bonus": 0, "character_class": "Brawler", "crit_threshold": 20}
-func handleCombatEncounter(w http.ResponseWriter, r *http.Request, aiClient *ai.Client) {
+// With "character_id", stat and class come from the character's effective stats
+// and the narration recalls their history. A single roll is only a preview, so
+// nothing goes in their journal (encounters record kills).
+func handleCombatEncounter(w http.ResponseWriter, r *http.Request, store *character.Store, journal *character.JournalStore, aiClient *ai.Client, timeout time.Duration, memory int) {
+	seed, ok := requestSeed(w, r)
+	if !ok {
+		return
+	}
 	var req struct {
 		Monster        string `json:"monster"`
 		Stat           int    `json:"stat"`
 		Bonus          int    `json:"bonus"`
 		CharacterClass string `json:"character_class"`
+		CharacterID    string `json:"character_id"`
 		CritThreshold  int    `json:"crit_threshold"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		writeError(w, http.StatusBadRequest, "INVALID_BODY", "invalid JSON body", "send {monster,stat,bonus,character_class}")
 		return
 	}
+	c, ok := loadOptionalCharacter(w, r, store, req.CharacterID)
+	if !ok {
+		return
+	}
+	if c != nil {
+		req.CharacterClass = c.Class
+		req.Stat = game.AttackStat(c.Class, c.EffectiveStats())
+	}
 	if req.Monster == "" {
 		writeError(w, http.StatusBadRequest, "MISSING_MONSTER", "monster name required", "use a monster name from the group")
 		return
 	}
+	// Only names from the content pack reach the narrator and its pool.
+	m := resources.MonsterByName(req.Monster)
+	if m == nil {
+		writeError(w, http.StatusBadRequest, "UNKNOWN_MONSTER", fmt.Sprintf("unknown monster %q", req.Monster), "use a monster name from the group")
+		return
+	}
 	if req.CharacterClass == "" {
 		req.CharacterClass = "Survivor"
+	} else if resources.ClassByName(req.CharacterClass) == nil {
+		writeError(w, http.StatusBadRequest, "UNKNOWN_CLASS", fmt.Sprintf("unknown class %q", req.CharacterClass), "use a class name from /api/items")
+		return
 	}
 	if req.CritThreshold <= 0 {
 		req.CritThreshold = 20
 	}
 
-	rollReq := game.CombatRollRequest{StatValue: req.Stat, Bonus: req.Bonus}
-	result := game.Roll(rollReq, req.CritThreshold)
+	// Roll against the monster's Defense rather than a flat 10.
+	rollReq := game.CombatRollRequest{StatValue: req.Stat, Bonus: req.Bonus, Class: req.CharacterClass, Target: m.Defense}
+	result := game.Roll(game.NewRNG(seed), rollReq, req.CritThreshold)
 
-	// AI narration — 8s timeout, fallback always ready.
-	ctx, cancel := context.WithTimeout(r.Context(), 8*time.Second)
+	// AI narration — narration timeout, fallback always ready.
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
 	defer cancel()
 
-	var narration string
+	var narration, history string
+	if c != nil {
+		history = journalHistory(r.Context(), journal, c.ID, memory)
+	}
 	switch result.Outcome {
 	case game.OutcomeCritSuccess, game.OutcomeSuccess:
 		isCrit := result.Outcome == game.OutcomeCritSuccess
-		narration = aiClient.CombatHit(ctx, req.Monster, req.CharacterClass, isCrit)
+		narration = aiClient.CombatHit(ctx, req.Monster, req.CharacterClass, isCrit, history)
 		obs.MonstersDefeatedTotal.WithLabelValues(req.Monster).Inc()
 	case game.OutcomeCritFailure, game.OutcomeFailure:
 		isCritFail := result.Outcome == game.OutcomeCritFailure
-		narration = aiClient.CombatMiss(ctx, req.Monster, isCritFail)
+		narration = aiClient.CombatMiss(ctx, req.Monster, isCritFail, history)
 	}
 
 	writeJSON(w, http.StatusOK, map[string]interface{}{
 		"roll":      result,
 		"narration": narration,
 		"hit":       result.Outcome == game.OutcomeSuccess || result.Outcome == game.OutcomeCritSuccess,
+		"seed":      seed,
 	})
 }
 
-func handleCreateCharacter(w http.ResponseWriter, r *http.Request, store *character.Store) {
-	var req character.GenerateRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, "INVALID_BODY", "invalid JSON body", "send {name: \"...\", class: \"...\"}")
+// handleStartEncounter creates a server-side encounter for a party.
+// POST /api/encounter  {"character_ids": ["..."], "group": "Zombie Ward"}
+// Instead of group, send "monsters": ["Zombie", ...] or "building": "Hospital"
+// (random group for that building; random building if all three are empty),
+// or "site": {"world_id", "tile_id", "building": 0} to fight a world building
+// the whole party is standing on. Winning a site clears it, rolls the
+// building's loot, and splits XP by xpSplit. The other kinds are practice
+// fights: anyone can pick them, so they pay no XP or loot.
+// ?seed= (admin token only) replays the same fight for the same actions.
+func handleStartEncounter(w http.ResponseWriter, r *http.Request, store *character.Store, journal *character.JournalStore, parties *character.PartyStore, encounters *game.EncounterStore, worlds *game.WorldStore, xpSplit, adminToken string) {
+	seed, ok := actionSeed(w, r, adminToken)
+	if !ok {
 		return
 	}
-	c, err := character.Generate(req)
-	if err != nil {
-		writeError(w, http.StatusBadRequest, "INVALID_CHARACTER", err.Error(), "check name and class fields")
+	var req struct {
+		CharacterIDs []string            `json:"character_ids"`
+		PartyID      string              `json:"party_id"` // instead of character_ids: members who are up
+		Group        string              `json:"group"`
+		Monsters     []string            `json:"monsters"`
+		Building     string              `json:"building"`
+		Site         *game.EncounterSite `json:"site"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_BODY", "invalid JSON body", `send {"character_ids": [...], "group": "..."}`)
 		return
 	}
-	if err := store.Save(r.Context(), c); err != nil {
-		slog.Error("save character failed", "error", err)
-		writeError(w, http.StatusInternalServerError, "STORE_ERROR", "failed to save character", "check server logs")
+	if req.PartyID != "" {
+		if req.CharacterIDs, ok = partyFighters(w, r, store, parties, req.PartyID); !ok {
+			return
+		}
+	}
+	if len(req.CharacterIDs) == 0 || len(req.CharacterIDs) > game.MaxPartySize {
+		writeError(w, http.StatusBadRequest, "INVALID_PARTY", fmt.Sprintf("character_ids must list 1-%d characters", game.MaxPartySize), "create characters with POST /api/character first")
 		return
 	}
-	writeJSON(w, http.StatusCreated, c)
-}
-
-// handleCharacterByID routes all /api/character/:id sub-resources.
-//
-// Routes:
-//   GET  /api/character/:id            — load character
-//   PUT  /api/character/:id            — partial update
-//   GET  /api/character/:id/sheet      — full sheet with class def
-//   POST /api/character/:id/craft      — craft item (consume materials)
-//   POST /api/character/:id/equip      — equip item into slot
-//   POST /api/character/:id/item/drop  — drop item from inventory
-//   POST /api/character/:id/levelup    — server-side LevelUp()
-func handleCharacterByID(w http.ResponseWriter, r *http.Request, path string, store *character.Store) {
-	trimmed := strings.TrimPrefix(path, "/api/character/")
-	parts := strings.SplitN(trimmed, "/", 2)
-	id := parts[0]
 
-	if id == "" {
-		writeError(w, http.StatusBadRequest, "MISSING_ID", "character ID required", "use /api/character/:id")
-		return
+	var group resources.MonsterGroup
+	var loot []string // the site's loot table, rolled if the party wins
+	switch {
+	case req.Site != nil:
+		world, ok := loadWorld(w, r, worlds, req.Site.WorldID)
+		if !ok {
+			return
+		}
+		for _, id := range req.CharacterIDs {
+			b, err := world.Building(id, req.Site.TileID, req.Site.Building)
+			if err != nil {
+				writeWorldError(w, err)
+				return
+			}
+			if b.Cleared {
+				writeWorldError(w, game.ErrAlreadyCleared)
+				return
+			}
+			group, loot = b.MonsterGroup, b.Building.Loot
+		}
+	case req.Group != "":
+		g := resources.GroupByName(req.Group)
+		if g == nil {
+			writeError(w, http.StatusBadRequest, "UNKNOWN_GROUP", "no monster group with that name", "use a group name from /api/building/enter or special_groups in /api/items")
+			return
+		}
+		group = *g
+	case len(req.Monsters) > 0:
+		group.Name = "Wandering Monsters"
+		for _, name := range req.Monsters {
+			m := resources.MonsterByName(name)
+			if m == nil {
+				writeError(w, http.StatusBadRequest, "UNKNOWN_MONSTER", fmt.Sprintf("unknown monster %q", name), "check monster names in resources")
+				return
+			}
+			group.Monsters = append(group.Monsters, *m)
+		}
+	default:
+		group = game.GenerateSingleBuilding(game.NewRNG(seed), req.Building).MonsterGroup
 	}
 
-	sub := ""
-	if len(parts) == 2 {
-		sub = parts[1]
+	seen := make(map[string]bool, len(req.CharacterIDs))
+	party := make([]*character.Character, 0, len(req.CharacterIDs))
+	for _, id := range req.CharacterIDs {
+		if seen[id] {
+			writeError(w, http.StatusBadRequest, "INVALID_PARTY", "duplicate character in party", "list each character once")
+			return
+		}
+		seen[id] = true
+		c, err := store.Load(r.Context(), id)
+		if err != nil {
+			slog.Error("load character failed", "id", id, "error", err)
+			writeError(w, http.StatusInternalServerError, "STORE_ERROR", "failed to load character", "check server logs")
+			return
+		}
+		if c == nil {
+			writeError(w, http.StatusNotFound, "CHARACTER_NOT_FOUND", "no character with ID "+id, "check character_ids")
+			return
+		}
+		party = append(party, c)
+	}
+	if !requireNotFighting(w, r, encounters, req.CharacterIDs) {
+		return
 	}
-	method := r.Method
 
-	c, err := store.Load(r.Context(), id)
+	e, err := game.NewEncounter(uuid.New().String(), seed, group, party)
 	if err != nil {
-		slog.Error("load character failed", "id", id, "error", err)
-		writeError(w, http.StatusInternalServerError, "STORE_ERROR", "failed to load character", "check server logs")
+		writeError(w, http.StatusBadRequest, "INVALID_ENCOUNTER", err.Error(), "heal downed characters before fighting")
+		return
+	}
+	e.Site, e.Loot, e.XPSplit, e.Practice = req.Site, loot, xpSplit, req.Site == nil
+	if err := encounters.Create(r.Context(), e); err != nil {
+		writeEncounterCreateError(w, err)
+		return
+	}
+	// Monsters that won initiative may already have attacked.
+	if err := syncEncounterParty(r.Context(), store, journal, e, e.Log); err != nil {
+		slog.Error("sync encounter party failed", "encounter", e.ID, "error", err)
+		writeError(w, http.StatusInternalServerError, "STORE_ERROR", "failed to save party", "check server logs")
+		return
+	}
+	writeJSON(w, http.StatusCreated, e)
+}
+
+// requireNotFighting writes 409 IN_ENCOUNTER and returns false if any of
+// characterIDs is already in an active encounter. A character fights one
+// encounter at a time; each writes its HP and statuses back to them.
+func requireNotFighting(w http.ResponseWriter, r *http.Request, encounters *game.EncounterStore, characterIDs []string) bool {
+	for _, id := range characterIDs {
+		fighting, err := encounters.ActiveFor(r.Context(), id)
+		if err != nil {
+			slog.Error("find active encounter failed", "id", id, "error", err)
+			writeError(w, http.StatusInternalServerError, "STORE_ERROR", "failed to load encounters", "check server logs")
+			return false
+		}
+		if fighting != "" {
+			writeError(w, http.StatusConflict, "IN_ENCOUNTER", "a character is already in a fight", "finish or flee encounter "+fighting+" first")
+			return false
+		}
+	}
+	return true
+}
+
+// writeEncounterCreateError maps an EncounterStore.Create error to a response.
+func writeEncounterCreateError(w http.ResponseWriter, err error) {
+	if errors.Is(err, game.ErrAlreadyFighting) {
+		writeError(w, http.StatusConflict, "IN_ENCOUNTER", err.Error(), "finish or flee that encounter first")
+		return
+	}
+	slog.Error("save encounter failed", "error", err)
+	writeError(w, http.StatusInternalServerError, "STORE_ERROR", "failed to save encounter", "check server logs")
+}
+
+// handleEncounterByID routes /api/encounter/:id sub-resources.
+//
+// Routes:
+//   GET  /api/encounter/:id          — full encounter state (turn order, HP, log)
+//   POST /api/encounter/:id/action   — {"character_id": "...", "action": "attack|heal|flee", "target": "m0"}
+func handleEncounterByID(w http.ResponseWriter, r *http.Request, path string, store *character.Store, journal *character.JournalStore, encounters *game.EncounterStore, worlds *game.WorldStore, aiClient *ai.Client, timeout time.Duration, memory int) {
+	parts := strings.SplitN(strings.TrimPrefix(path, "/api/encounter/"), "/", 2)
+	id := parts[0]
+	sub := ""
+	if len(parts) == 2 {
+		sub = parts[1]
+	}
+	if id == "" {
+		writeError(w, http.StatusBadRequest, "MISSING_ID", "encounter ID required", "use /api/encounter/:id")
+		return
+	}
+
+	switch {
+	case sub == "" && r.Method == http.MethodGet:
+		e, err := encounters.Load(r.Context(), id)
+		if err != nil {
+			slog.Error("load encounter failed", "id", id, "error", err)
+			writeError(w, http.StatusInternalServerError, "STORE_ERROR", "failed to load encounter", "check server logs")
+			return
+		}
+		if e == nil {
+			writeError(w, http.StatusNotFound, "ENCOUNTER_NOT_FOUND", "no encounter with that ID", "start one with POST /api/encounter")
+			return
+		}
+		writeJSON(w, http.StatusOK, e)
+
+	case sub == "action" && r.Method == http.MethodPost:
+		handleEncounterAction(w, r, id, store, journal, encounters, worlds, aiClient, timeout, memory)
+
+	default:
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "route not found", "check path and method")
+	}
+}
+
+// handleEncounterAction takes the acting character's turn, then resolves monster
+// turns up to the next character. Character HP and XP are saved after every action;
+// a win is settled by settleEncounter (site cleared, XP and loot handed out).
+func handleEncounterAction(w http.ResponseWriter, r *http.Request, id string, store *character.Store, journal *character.JournalStore, encounters *game.EncounterStore, worlds *game.WorldStore, aiClient *ai.Client, timeout time.Duration, memory int) {
+	var req struct {
+		CharacterID string `json:"character_id"`
+		Action      string `json:"action"`
+		Target      string `json:"target"`
+		Stream      bool   `json:"stream"` // return narration_stream instead of waiting for narration
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.CharacterID == "" {
+		writeError(w, http.StatusBadRequest, "INVALID_BODY", "character_id and action required", `send {"character_id": "...", "action": "attack|heal|flee", "target": "m0"}`)
+		return
+	}
+	if req.Action == "" {
+		req.Action = game.ActionAttack
+	}
+	history := journalHistory(r.Context(), journal, req.CharacterID, memory) // before this action's entries
+
+	var events []game.EncounterEvent
+	e, err := encounters.Update(r.Context(), id, func(e *game.Encounter) error {
+		var err error
+		events, err = e.Act(req.CharacterID, req.Action, req.Target)
+		return err
+	})
+	switch {
+	case errors.Is(err, game.ErrEncounterOver):
+		writeError(w, http.StatusConflict, "ENCOUNTER_OVER", err.Error(), "start a new encounter")
+		return
+	case errors.Is(err, game.ErrNotYourTurn):
+		writeError(w, http.StatusConflict, "NOT_YOUR_TURN", err.Error(), "GET the encounter to see whose turn it is")
+		return
+	case errors.Is(err, game.ErrUnknownAction), errors.Is(err, game.ErrInvalidTarget), errors.Is(err, game.ErrNoAbility):
+		writeError(w, http.StatusBadRequest, "INVALID_ACTION", err.Error(), "target a living monster ID such as m0")
+		return
+	case err != nil:
+		slog.Error("encounter action failed", "id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "STORE_ERROR", "failed to update encounter", "check server logs")
+		return
+	case e == nil:
+		writeError(w, http.StatusNotFound, "ENCOUNTER_NOT_FOUND", "no encounter with that ID", "start one with POST /api/encounter")
+		return
+	}
+
+	if events, err = settleEncounter(r.Context(), store, journal, encounters, worlds, e, events); err != nil {
+		slog.Error("settle encounter failed", "encounter", e.ID, "error", err)
+		writeError(w, http.StatusInternalServerError, "STORE_ERROR", "failed to save party", "check server logs")
+		return
+	}
+
+	// Narrate the character's own attack — narration timeout, fallback always ready.
+	var narration, streamURL string
+	if len(events) > 0 && events[0].Roll != nil {
+		actor, target := e.Combatant(events[0].Actor), e.Combatant(events[0].Target)
+		switch {
+		case req.Stream && events[0].Hit:
+			streamURL = narrationStreamURL("combat_hit", url.Values{"monster": {target.Name}, "class": {actor.Class},
+				"crit": {strconv.FormatBool(events[0].Roll.Outcome == game.OutcomeCritSuccess)}}, actor.CharacterID)
+		case req.Stream:
+			streamURL = narrationStreamURL("combat_miss", url.Values{"monster": {target.Name},
+				"crit": {strconv.FormatBool(events[0].Roll.Outcome == game.OutcomeCritFailure)}}, actor.CharacterID)
+		default:
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+			if events[0].Hit {
+				narration = aiClient.CombatHit(ctx, target.Name, actor.Class, events[0].Roll.Outcome == game.OutcomeCritSuccess, history)
+			} else {
+				narration = aiClient.CombatMiss(ctx, target.Name, events[0].Roll.Outcome == game.OutcomeCritFailure, history)
+			}
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"encounter":        e,
+		"events":           events,
+		"narration":        narration,
+		"narration_stream": streamURL,
+	})
+}
+
+// settleEncounter persists what the latest events did to the party. On the
+// action that wins a site, the site is claimed first: if another fight
+// already cleared it, the rewards are forfeited so a building can't be farmed.
+// Loot goes to the survivors' packs and the drops are saved on the encounter.
+// It returns events with any forfeit applied.
+func settleEncounter(ctx context.Context, store *character.Store, journal *character.JournalStore, encounters *game.EncounterStore, worlds *game.WorldStore, e *game.Encounter, events []game.EncounterEvent) ([]game.EncounterEvent, error) {
+	won := wonIn(events)
+	if won && e.Site != nil {
+		_, err := worlds.Update(ctx, e.Site.WorldID, func(world *game.World) error {
+			return world.ClearBuilding(e.Site.TileID, e.Site.Building)
+		})
+		switch {
+		case errors.Is(err, game.ErrAlreadyCleared):
+			var forfeit game.EncounterEvent
+			updated, err := encounters.Update(ctx, e.ID, func(e *game.Encounter) error {
+				forfeit = e.ForfeitRewards("Someone got here first. There's nothing left to take.")
+				return nil
+			})
+			if err != nil || updated == nil {
+				return events, err
+			}
+			*e = *updated
+			events = append(game.WithoutRewards(events), forfeit)
+		case err != nil:
+			slog.Error("clear world building failed", "encounter", e.ID, "world", e.Site.WorldID, "error", err)
+		}
+	}
+
+	if err := syncEncounterParty(ctx, store, journal, e, events); err != nil {
+		return events, err
+	}
+	if won && e.Rewards != nil && len(e.Rewards.Loot) > 0 {
+		drops, leftBehind := e.Rewards.Drops, e.Rewards.LeftBehind
+		if _, err := encounters.Update(ctx, e.ID, func(stored *game.Encounter) error {
+			if stored.Rewards != nil {
+				stored.Rewards.Drops, stored.Rewards.LeftBehind = drops, leftBehind
+			}
+			return nil
+		}); err != nil {
+			return events, err
+		}
+	}
+	return events, nil
+}
+
+// wonIn reports whether events include the party's victory.
+func wonIn(events []game.EncounterEvent) bool {
+	for _, ev := range events {
+		if ev.Type == string(game.EncounterVictory) {
+			return true
+		}
+	}
+	return false
+}
+
+// syncEncounterParty writes the HP the last action gained or cost (see
+// game.Combatant.HPChange), statuses, and any XP earned in events back to
+// the stored characters, so the encounter stays the single source of truth.
+// If events include the victory, the rolled loot is handed to the survivors
+// (see game.DistributeLoot) and recorded in e.Rewards. What events meant for
+// each character goes in their journal. Each character is written with
+// Store.Update, so a craft or stash move saved mid-fight is kept rather than
+// overwritten.
+func syncEncounterParty(ctx context.Context, store *character.Store, journal *character.JournalStore, e *game.Encounter, events []game.EncounterEvent) error {
+	xp := make(map[string]int)
+	for _, ev := range events {
+		xp[ev.Actor] += ev.XP
+	}
+	var fighters []*game.Combatant
+	var survivors []*character.Character
+	var entries []character.JournalEntry
+	for _, cb := range e.Order {
+		if cb.Kind != game.KindCharacter {
+			continue
+		}
+		c, err := store.Load(ctx, cb.CharacterID)
+		if err != nil {
+			return err
+		}
+		if c == nil {
+			continue // deleted mid-fight; nothing to sync
+		}
+		entries = append(entries, encounterJournal(e, cb, c.HP, events)...)
+		fighters = append(fighters, cb)
+		if cb.Alive() {
+			survivors = append(survivors, c)
+		}
+	}
+	loot := make(map[string][]string) // character ID → items picked up
+	if wonIn(events) && e.Rewards != nil && len(e.Rewards.Loot) > 0 {
+		drops, leftBehind := game.DistributeLoot(e.Rewards.Loot, survivors)
+		e.Rewards.Drops, e.Rewards.LeftBehind = drops, leftBehind
+		for _, d := range drops {
+			loot[d.CharacterID] = append(loot[d.CharacterID], d.Item)
+		}
+	}
+	for _, cb := range fighters {
+		var overflow []string // loot that no longer fits: the pack filled up after it was shared out
+		if _, err := store.Update(ctx, cb.CharacterID, func(c *character.Character) error {
+			c.HP = max(0, min(c.HP+cb.HPChange(), c.MaxHP))
+			c.XP += xp[cb.ID]
+			c.Statuses = append([]character.Status{}, cb.Statuses...)
+			_, overflow = c.AddItems(loot[c.ID])
+			return nil
+		}); err != nil {
+			return err
+		}
+		if len(overflow) > 0 {
+			e.Rewards.LeaveBehind(cb.CharacterID, overflow)
+		}
+	}
+	addJournal(ctx, journal, entries...)
+	return nil
+}
+
+// encounterJournal is what events mean for cb's journal: each kill, dropping
+// to a quarter of max HP or less (from above it, so a long fight on low HP
+// counts once), and how the fight ended. prevHP is cb's HP before events.
+func encounterJournal(e *game.Encounter, cb *game.Combatant, prevHP int, events []game.EncounterEvent) []character.JournalEntry {
+	entry := func(kind, subject string) character.JournalEntry {
+		return character.JournalEntry{CharacterID: cb.CharacterID, Kind: kind, Subject: subject, EncounterID: e.ID}
+	}
+	var entries []character.JournalEntry
+	var ending *character.JournalEntry
+	lastHitBy := e.Group
+	for _, ev := range events {
+		switch {
+		case ev.Type == "defeated" && ev.Actor == cb.ID && ev.Target != cb.ID:
+			if m := e.Combatant(ev.Target); m != nil {
+				entries = append(entries, entry(character.JournalKill, m.Name))
+			}
+		case ev.Type == string(game.EncounterVictory):
+			end := entry(character.JournalVictory, e.Group)
+			ending = &end
+		case ev.Type == string(game.EncounterDefeat):
+			end := entry(character.JournalDefeat, e.Group)
+			ending = &end
+		case ev.Target == cb.ID && ev.Damage > 0:
+			if m := e.Combatant(ev.Actor); m != nil && m.Kind != game.KindCharacter {
+				lastHitBy = m.Name
+			}
+		}
+	}
+	if low := cb.MaxHP / 4; cb.HP <= low && prevHP > low {
+		entries = append(entries, entry(character.JournalNearDeath, lastHitBy))
+	}
+	if ending != nil {
+		entries = append(entries, *ending)
+	}
+	return entries
+}
+
+// journalHistory summarizes characterID's last memory journal entries for a
+// narration prompt. It returns "" when there is nothing to add, memory is 0,
+// or the journal can't be read (logged; narration carries on without it).
+func journalHistory(ctx context.Context, journal *character.JournalStore, characterID string, memory int) string {
+	if characterID == "" || memory <= 0 {
+		return ""
+	}
+	entries, err := journal.Recent(ctx, characterID, memory)
+	if err != nil {
+		slog.Warn("load journal failed", "character", characterID, "error", err)
+		return ""
+	}
+	return character.SummarizeJournal(entries)
+}
+
+// addJournal records journal entries. The journal is flavour, so a failed
+// write is logged rather than failing the request.
+func addJournal(ctx context.Context, journal *character.JournalStore, entries ...character.JournalEntry) {
+	if err := journal.Add(ctx, entries...); err != nil {
+		slog.Warn("write journal failed", "entries", len(entries), "error", err)
+	}
+}
+
+// handleCreateWorld creates a persistent world map.
+// POST /api/world  {"name": "...", "tile_count": 9, "character_ids": ["..."]}
+// tile_count tiles are placed up front (0 = empty map, grow it with draw/place);
+// listed characters start on tile-01 when there is one.
+func handleCreateWorld(w http.ResponseWriter, r *http.Request, store *character.Store, worlds *game.WorldStore, adminToken string) {
+	seed, ok := actionSeed(w, r, adminToken)
+	if !ok {
+		return
+	}
+	var req struct {
+		Name         string   `json:"name"`
+		TileCount    int      `json:"tile_count"`
+		CharacterIDs []string `json:"character_ids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_BODY", "invalid JSON body", `send {"name": "...", "tile_count": 9, "character_ids": [...]}`)
+		return
+	}
+	if req.TileCount < 0 || req.TileCount > game.MaxWorldTiles {
+		writeError(w, http.StatusBadRequest, "INVALID_TILE_COUNT", fmt.Sprintf("tile_count must be 0-%d", game.MaxWorldTiles), "")
+		return
+	}
+	if len(req.CharacterIDs) > game.MaxPartySize {
+		writeError(w, http.StatusBadRequest, "INVALID_PARTY", fmt.Sprintf("at most %d characters", game.MaxPartySize), "")
+		return
+	}
+	if req.Name == "" {
+		req.Name = "The Wasteland"
+	}
+
+	world := game.NewWorld(uuid.New().String(), req.Name, seed, req.TileCount)
+	if len(world.Tiles) > 0 {
+		for _, id := range req.CharacterIDs {
+			c, ok := loadOptionalCharacter(w, r, store, id)
+			if !ok {
+				return
+			}
+			if c == nil {
+				writeError(w, http.StatusBadRequest, "INVALID_PARTY", "character IDs must not be empty", "")
+				return
+			}
+			if _, err := world.Enter(c.ID, world.Tiles[0].ID); err != nil {
+				writeWorldError(w, err)
+				return
+			}
+		}
+	}
+	if err := worlds.Save(r.Context(), world); err != nil {
+		slog.Error("save world failed", "error", err)
+		writeError(w, http.StatusInternalServerError, "STORE_ERROR", "failed to save world", "check server logs")
+		return
+	}
+	if err := syncWorldPositions(r.Context(), store, world, req.CharacterIDs); err != nil {
+		slog.Error("sync world positions failed", "world", world.ID, "error", err)
+	}
+	writeJSON(w, http.StatusCreated, world.ViewFor(""))
+}
+
+// handleWorldByID routes /api/world/:id sub-resources.
+//
+// Routes:
+//   GET  /api/world/:id?character_id=  — world as that character sees it (party view if omitted)
+//   POST /api/world/:id/draw           — deal two tiles to choose from (same hand until placed)
+//   POST /api/world/:id/place          — {"choice": 0} place a tile from the hand
+//   POST /api/world/:id/move           — {"character_ids": [...], "tile_id": "tile-03"} walk the party to a tile
+//                                         (add "vehicle_id" and optionally "driver_id" to drive instead)
+//   POST /api/world/:id/search         — {"character_id": "..."} search the character's tile for a vehicle
+func handleWorldByID(w http.ResponseWriter, r *http.Request, path string, store *character.Store, journal *character.JournalStore, parties *character.PartyStore, encounters *game.EncounterStore, worlds *game.WorldStore, xpSplit string) {
+	parts := strings.SplitN(strings.TrimPrefix(path, "/api/world/"), "/", 2)
+	id := parts[0]
+	sub := ""
+	if len(parts) == 2 {
+		sub = parts[1]
+	}
+	if id == "" {
+		writeError(w, http.StatusBadRequest, "MISSING_ID", "world ID required", "use /api/world/:id")
+		return
+	}
+
+	switch {
+	case sub == "" && r.Method == http.MethodGet:
+		world, ok := loadWorld(w, r, worlds, id)
+		if !ok {
+			return
+		}
+		writeJSON(w, http.StatusOK, world.ViewFor(r.URL.Query().Get("character_id")))
+
+	case sub == "draw" && r.Method == http.MethodPost:
+		world, err := worlds.Update(r.Context(), id, func(world *game.World) error {
+			_, err := world.Draw()
+			return err
+		})
+		respondWorld(w, world, err, "")
+
+	case sub == "place" && r.Method == http.MethodPost:
+		var req struct {
+			Choice int `json:"choice"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "INVALID_BODY", "invalid JSON body", `send {"choice": 0}`)
+			return
+		}
+		world, err := worlds.Update(r.Context(), id, func(world *game.World) error {
+			_, err := world.Place(req.Choice)
+			return err
+		})
+		respondWorld(w, world, err, "")
+
+	case sub == "move" && r.Method == http.MethodPost:
+		handleMoveParty(w, r, id, store, journal, parties, encounters, worlds, xpSplit)
+
+	case sub == "search" && r.Method == http.MethodPost:
+		handleVehicleSearch(w, r, id, store, worlds)
+
+	default:
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "route not found", "check path and method")
+	}
+}
+
+// handleMoveParty walks the party along the cheapest path to a tile, revealing
+// every tile on the way. A travel ambush stops the party and starts an encounter.
+func handleMoveParty(w http.ResponseWriter, r *http.Request, id string, store *character.Store, journal *character.JournalStore, parties *character.PartyStore, encounters *game.EncounterStore, worlds *game.WorldStore, xpSplit string) {
+	var req struct {
+		CharacterIDs []string `json:"character_ids"`
+		PartyID      string   `json:"party_id"` // instead of character_ids: members who are up
+		TileID       string   `json:"tile_id"`
+		VehicleID    string   `json:"vehicle_id"` // optional: drive instead of walking
+		DriverID     string   `json:"driver_id"`  // owner of vehicle_id; defaults to the first character
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || (len(req.CharacterIDs) == 0 && req.PartyID == "") || req.TileID == "" {
+		writeError(w, http.StatusBadRequest, "INVALID_BODY", "character_ids (or party_id) and tile_id required", `send {"character_ids": [...], "tile_id": "tile-03"}`)
+		return
+	}
+	if req.PartyID != "" {
+		var ok bool
+		if req.CharacterIDs, ok = partyFighters(w, r, store, parties, req.PartyID); !ok {
+			return
+		}
+	}
+	if len(req.CharacterIDs) > game.MaxPartySize {
+		writeError(w, http.StatusBadRequest, "INVALID_PARTY", fmt.Sprintf("at most %d characters", game.MaxPartySize), "")
+		return
+	}
+	party := make([]*character.Character, 0, len(req.CharacterIDs))
+	for _, cid := range req.CharacterIDs {
+		c, ok := loadOptionalCharacter(w, r, store, cid)
+		if !ok {
+			return
+		}
+		if c == nil {
+			writeError(w, http.StatusBadRequest, "INVALID_PARTY", "character IDs must not be empty", "")
+			return
+		}
+		if c.HP <= 0 {
+			writeError(w, http.StatusBadRequest, "CHARACTER_DOWN", c.Name+" is down", "heal before moving")
+			return
+		}
+		party = append(party, c)
+	}
+	if !requireNotFighting(w, r, encounters, req.CharacterIDs) {
+		return
+	}
+
+	var driver *character.Character
+	var vehicle *character.OwnedVehicle
+	if req.VehicleID != "" {
+		if req.DriverID == "" {
+			req.DriverID = req.CharacterIDs[0]
+		}
+		for _, c := range party {
+			if c.ID == req.DriverID {
+				driver = c
+			}
+		}
+		if driver == nil {
+			writeError(w, http.StatusBadRequest, "INVALID_PARTY", "driver must be in character_ids", "")
+			return
+		}
+		if vehicle = driver.Vehicle(req.VehicleID); vehicle == nil {
+			writeVehicleError(w, game.ErrUnknownVehicle)
+			return
+		}
+		if err := game.CanDrive(vehicle, len(party)); err != nil {
+			writeVehicleError(w, err)
+			return
+		}
+	}
+
+	var move game.MoveResult
+	world, err := worlds.Update(r.Context(), id, func(world *game.World) error {
+		var err error
+		if vehicle != nil {
+			move, err = world.Drive(req.CharacterIDs, req.TileID, vehicle.Vehicle)
+		} else {
+			move, err = world.Move(req.CharacterIDs, req.TileID)
+		}
+		return err
+	})
+	if err != nil || world == nil {
+		respondWorld(w, world, err, "")
+		return
+	}
+	if vehicle != nil && vehicle.FuelNeeded {
+		if _, err := store.Update(r.Context(), driver.ID, func(c *character.Character) error {
+			if v := c.Vehicle(req.VehicleID); v != nil {
+				v.Fuel = max(0, v.Fuel-1)
+			}
+			return nil
+		}); err != nil {
+			slog.Error("save driver failed", "id", driver.ID, "error", err)
+		}
+	}
+	if err := syncWorldPositions(r.Context(), store, world, req.CharacterIDs); err != nil {
+		slog.Error("sync world positions failed", "world", world.ID, "error", err)
+	}
+	if req.PartyID != "" {
+		_, err := parties.Update(r.Context(), req.PartyID, func(p *character.Party) error {
+			p.WorldID, p.Location = world.ID, move.To
+			return nil
+		})
+		if err != nil {
+			slog.Error("save party location failed", "party", req.PartyID, "error", err)
+		}
+	}
+
+	resp := map[string]any{
+		"world": world.ViewFor(req.CharacterIDs[0]),
+		"move":  move,
+	}
+	if move.Ambush != nil {
+		e, err := game.NewEncounter(uuid.New().String(), move.AmbushSeed, *move.Ambush, party)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "INVALID_ENCOUNTER", err.Error(), "")
+			return
+		}
+		e.XPSplit = xpSplit
+		if err := encounters.Create(r.Context(), e); err != nil {
+			writeEncounterCreateError(w, err)
+			return
+		}
+		if err := syncEncounterParty(r.Context(), store, journal, e, e.Log); err != nil {
+			slog.Error("sync encounter party failed", "encounter", e.ID, "error", err)
+		}
+		resp["encounter"] = e
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// handleVehicleSearch searches the character's tile for something to drive.
+// The roll is scouting against game.VehicleSearchTarget; a tile can be
+// searched once. A find goes straight into the character's garage.
+// POST /api/world/:id/search  {"character_id": "..."}
+func handleVehicleSearch(w http.ResponseWriter, r *http.Request, id string, store *character.Store, worlds *game.WorldStore) {
+	var req struct {
+		CharacterID string `json:"character_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.CharacterID == "" {
+		writeError(w, http.StatusBadRequest, "INVALID_BODY", "character_id required", `send {"character_id": "..."}`)
+		return
+	}
+	c, ok := loadOptionalCharacter(w, r, store, req.CharacterID)
+	if !ok {
+		return
+	}
+	if c.HP <= 0 {
+		writeError(w, http.StatusBadRequest, "CHARACTER_DOWN", "character is down", "heal before searching")
+		return
+	}
+	if len(c.Vehicles) >= character.MaxVehicles {
+		writeVehicleError(w, game.ErrGarageFull)
+		return
+	}
+
+	var search game.VehicleSearch
+	world, err := worlds.Update(r.Context(), id, func(world *game.World) error {
+		pos, ok := world.Positions[c.ID]
+		if !ok {
+			return game.ErrNotOnTile
+		}
+		var err error
+		search, err = world.SearchTile(pos, c.EffectiveStats().Scouting)
+		return err
+	})
+	if err != nil || world == nil {
+		respondWorld(w, world, err, "")
+		return
+	}
+
+	resp := map[string]any{"search": search}
+	if search.Found != nil {
+		var v *character.OwnedVehicle
+		fresh, err := store.Update(r.Context(), c.ID, func(c *character.Character) error {
+			var err error
+			v, err = game.ClaimVehicle(c, uuid.New().String(), search.Found.Vehicle)
+			return err
+		})
+		switch {
+		case errors.Is(err, game.ErrGarageFull):
+			writeVehicleError(w, err)
+			return
+		case err != nil || fresh == nil:
+			slog.Error("save character failed", "id", c.ID, "error", err)
+			writeError(w, http.StatusInternalServerError, "STORE_ERROR", "failed to save vehicle", "check server logs")
+			return
+		}
+		c, resp["vehicle"] = fresh, v
+	}
+	resp["character"] = c
+	resp["world"] = world.ViewFor(c.ID)
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// syncWorldPositions mirrors world positions onto Character.Location.
+func syncWorldPositions(ctx context.Context, store *character.Store, world *game.World, ids []string) error {
+	for _, id := range ids {
+		if _, err := store.Update(ctx, id, func(c *character.Character) error {
+			c.Location = world.Positions[id]
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadWorld loads a world or writes the error response and returns ok=false.
+func loadWorld(w http.ResponseWriter, r *http.Request, worlds *game.WorldStore, id string) (*game.World, bool) {
+	world, err := worlds.Load(r.Context(), id)
+	if err != nil {
+		slog.Error("load world failed", "id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "STORE_ERROR", "failed to load world", "check server logs")
+		return nil, false
+	}
+	if world == nil {
+		writeError(w, http.StatusNotFound, "WORLD_NOT_FOUND", "no world with that ID", "create one with POST /api/world")
+		return nil, false
+	}
+	return world, true
+}
+
+// respondWorld writes the result of a worlds.Update as viewer sees it.
+func respondWorld(w http.ResponseWriter, world *game.World, err error, viewer string) {
+	switch {
+	case err != nil:
+		writeWorldError(w, err)
+	case world == nil:
+		writeError(w, http.StatusNotFound, "WORLD_NOT_FOUND", "no world with that ID", "create one with POST /api/world")
+	default:
+		writeJSON(w, http.StatusOK, world.ViewFor(viewer))
+	}
+}
+
+// writeWorldError maps world rule errors onto HTTP responses.
+func writeWorldError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, game.ErrWorldFull):
+		writeError(w, http.StatusConflict, "WORLD_FULL", err.Error(), "start a new world")
+	case errors.Is(err, game.ErrNoHand):
+		writeError(w, http.StatusConflict, "NO_HAND", err.Error(), "POST /api/world/:id/draw first")
+	case errors.Is(err, game.ErrAlreadyCleared):
+		writeError(w, http.StatusConflict, "ALREADY_CLEARED", err.Error(), "pick another building")
+	case errors.Is(err, game.ErrNotOnTile), errors.Is(err, game.ErrCharacterNotHere):
+		writeError(w, http.StatusConflict, "NOT_ON_TILE", err.Error(), "move there with POST /api/world/:id/move")
+	case errors.Is(err, game.ErrAlreadyScavenged):
+		writeError(w, http.StatusConflict, "ALREADY_SCAVENGED", err.Error(), "move to a tile nobody has scavenged")
+	case errors.Is(err, game.ErrNoPath), errors.Is(err, game.ErrTooFar):
+		writeError(w, http.StatusConflict, "UNREACHABLE", err.Error(), fmt.Sprintf("pick a tile within %d movement points; placed tiles connect edge to edge", game.MoveBudget))
+	case errors.Is(err, game.ErrPartySplit):
+		writeError(w, http.StatusConflict, "PARTY_SPLIT", err.Error(), "move members one at a time onto a shared tile first")
+	case errors.Is(err, game.ErrNoVehicleHere):
+		writeError(w, http.StatusConflict, "NO_VEHICLES_HERE", err.Error(), "search a highway or gas station")
+	case errors.Is(err, game.ErrAlreadySearched):
+		writeError(w, http.StatusConflict, "ALREADY_SEARCHED", err.Error(), "try another tile")
+	case errors.Is(err, game.ErrTooManyPassengers):
+		writeVehicleError(w, err)
+	case errors.Is(err, game.ErrBadChoice), errors.Is(err, game.ErrUnknownTile), errors.Is(err, game.ErrUnknownBuilding):
+		writeError(w, http.StatusBadRequest, "INVALID_WORLD_ACTION", err.Error(), "GET the world to see placed tiles and the hand")
+	default:
+		slog.Error("world update failed", "error", err)
+		writeError(w, http.StatusInternalServerError, "STORE_ERROR", "failed to update world", "check server logs")
+	}
+}
+
+func handleCreateCharacter(w http.ResponseWriter, r *http.Request, store *character.Store) {
+	var req character.GenerateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_BODY", "invalid JSON body", "send {name: \"...\", class: \"...\"}")
+		return
+	}
+	c, err := character.Generate(req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_CHARACTER", err.Error(), "check name and class fields")
+		return
+	}
+	if err := store.Save(r.Context(), c); err != nil {
+		slog.Error("save character failed", "error", err)
+		writeError(w, http.StatusInternalServerError, "STORE_ERROR", "failed to save character", "check server logs")
+		return
+	}
+	writeJSON(w, http.StatusCreated, c)
+}
+
+// handleCharacterByID routes all /api/character/:id sub-resources.
+//
+// Routes:
+//   GET  /api/character/:id            — load character
+//   PUT  /api/character/:id            — admin-only override (X-Admin-Token)
+//   GET  /api/character/:id/sheet      — full sheet with class def
+//   POST /api/character/:id/craft      — craft item (consume materials)
+//   POST /api/character/:id/equip      — equip item into slot
+//   POST /api/character/:id/item/drop  — drop item from inventory
+//   POST /api/character/:id/item/use   — use an item, in or out of an encounter (/use is an alias)
+//   POST /api/character/:id/scavenge   — scavenge the character's tile in a world and keep what fits
+//   POST /api/character/:id/levelup    — server-side LevelUp()
+//   GET  /api/character/:id/journal    — campaign journal, oldest first (?limit=, default 50)
+//   POST /api/character/:id/vehicles/:vid/repair — spend materials on a repair check
+//   POST /api/character/:id/vehicles/:vid/refuel — pour in one Fuel
+//
+// HP and XP otherwise change only through encounters (/api/encounter).
+func handleCharacterByID(w http.ResponseWriter, r *http.Request, path string, store *character.Store, journal *character.JournalStore, encounters *game.EncounterStore, worlds *game.WorldStore, memory int, adminToken string) {
+	trimmed := strings.TrimPrefix(path, "/api/character/")
+	parts := strings.SplitN(trimmed, "/", 2)
+	id := parts[0]
+
+	if id == "" {
+		writeError(w, http.StatusBadRequest, "MISSING_ID", "character ID required", "use /api/character/:id")
+		return
+	}
+
+	sub := ""
+	if len(parts) == 2 {
+		sub = parts[1]
+	}
+	method := r.Method
+
+	c, err := store.Load(r.Context(), id)
+	if err != nil {
+		slog.Error("load character failed", "id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "STORE_ERROR", "failed to load character", "check server logs")
 		return
 	}
 	if c == nil {
@@ -364,6 +1752,9 @@ func handleCharacterByID(w http.ResponseWriter, r *http.Request, path string, st
 		writeJSON(w, http.StatusOK, c)
 
 	case sub == "" && method == http.MethodPut:
+		if !requireAdmin(w, r, adminToken) {
+			return
+		}
 		handleUpdateCharacter(w, r, c, store)
 
 	case sub == "sheet" && method == http.MethodGet:
@@ -374,6 +1765,9 @@ func handleCharacterByID(w http.ResponseWriter, r *http.Request, path string, st
 			"xp_to_next":        character.XPForNextLevel(c.Level),
 			"ready_to_level_up": c.IsReadyToLevelUp(),
 			"max_inventory":     c.MaxInventorySlots(),
+			"modifiers":         game.ClassModifiers(c.Class),
+			"effective_stats":   c.EffectiveStats(),
+			"stat_breakdown":    c.StatBreakdown(time.Now()),
 		})
 
 	case sub == "craft" && method == http.MethodPost:
@@ -385,198 +1779,964 @@ func handleCharacterByID(w http.ResponseWriter, r *http.Request, path string, st
 	case sub == "item/drop" && method == http.MethodPost:
 		handleDropItem(w, r, c, store)
 
+	case (sub == "item/use" || sub == "use") && method == http.MethodPost:
+		handleUseItem(w, r, c, store, journal, encounters, worlds)
+
+	case sub == "scavenge" && method == http.MethodPost:
+		handleCharacterScavenge(w, r, c, store, worlds, adminToken)
+
 	case sub == "levelup" && method == http.MethodPost:
 		handleLevelUp(w, r, c, store)
 
+	case sub == "journal" && method == http.MethodGet:
+		handleJournal(w, r, c, journal, memory)
+
+	case strings.HasPrefix(sub, "vehicles/") && method == http.MethodPost:
+		handleVehicleAction(w, r, strings.TrimPrefix(sub, "vehicles/"), c, store, adminToken)
+
+	default:
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "route not found", "check path and method")
+	}
+}
+
+// handleJournal returns the character's most recent journal entries, oldest
+// first, and summary: the history narration prompts currently get for them.
+// GET /api/character/:id/journal?limit=50
+func handleJournal(w http.ResponseWriter, r *http.Request, c *character.Character, journal *character.JournalStore, memory int) {
+	limit := 50
+	if s := r.URL.Query().Get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 || n > 500 {
+			writeError(w, http.StatusBadRequest, "INVALID_LIMIT", "limit must be 1-500", "omit it for the last 50 entries")
+			return
+		}
+		limit = n
+	}
+	entries, err := journal.Recent(r.Context(), c.ID, limit)
+	if err != nil {
+		slog.Error("load journal failed", "id", c.ID, "error", err)
+		writeError(w, http.StatusInternalServerError, "STORE_ERROR", "failed to load journal", "check server logs")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"character_id": c.ID,
+		"entries":      entries,
+		"summary":      journalHistory(r.Context(), journal, c.ID, memory),
+	})
+}
+
+// handleUpdateCharacter applies a partial update to a character. Admin only:
+// players change state through the validated actions above.
+// PUT /api/character/:id
+func handleUpdateCharacter(w http.ResponseWriter, r *http.Request, c *character.Character, store *character.Store) {
+	var req struct {
+		HP        *int                 `json:"hp,omitempty"`
+		XP        *int                 `json:"xp,omitempty"`
+		Level     *int                 `json:"level,omitempty"`
+		Inventory []string             `json:"inventory,omitempty"`
+		Equipment *character.Equipment `json:"equipment,omitempty"`
+		Location  string               `json:"location,omitempty"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_BODY", "invalid JSON body", "send partial character fields")
+		return
+	}
+	if req.HP != nil {
+		c.HP = *req.HP
+		if c.HP < 0 {
+			c.HP = 0
+		}
+		if c.HP > c.MaxHP {
+			c.HP = c.MaxHP
+		}
+	}
+	if req.XP != nil {
+		c.XP = *req.XP
+	}
+	if req.Level != nil {
+		c.Level = *req.Level
+	}
+	if req.Inventory != nil {
+		c.Inventory = req.Inventory
+	}
+	if req.Equipment != nil {
+		c.Equipment = *req.Equipment
+	}
+	if req.Location != "" {
+		c.Location = req.Location
+	}
+	slog.Warn("admin character override", "id", c.ID, "hp", c.HP, "xp", c.XP, "level", c.Level)
+	if err := store.Save(r.Context(), c); err != nil {
+		if characterConflict(w, err) {
+			return
+		}
+		slog.Error("update character failed", "id", c.ID, "error", err)
+		writeError(w, http.StatusInternalServerError, "STORE_ERROR", "failed to save character", "check server logs")
+		return
+	}
+	writeJSON(w, http.StatusOK, c)
+}
+
+// handleCraftItem crafts an item: verifies materials, consumes them, adds result.
+// POST /api/character/:id/craft  {"item_name": "Medkit"}
+func handleCraftItem(w http.ResponseWriter, r *http.Request, c *character.Character, store *character.Store) {
+	var req struct {
+		ItemName string `json:"item_name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.ItemName == "" {
+		writeError(w, http.StatusBadRequest, "INVALID_BODY", "item_name required", `send {"item_name": "..."}`)
+		return
+	}
+	item := resources.CraftableItemByName(req.ItemName)
+	if item == nil {
+		writeError(w, http.StatusBadRequest, "UNKNOWN_ITEM", "item is not craftable", "check /api/items for the craftable list")
+		return
+	}
+	mods := game.ClassModifiers(c.Class)
+	crafting := c.EffectiveStats().Crafting
+	if required := mods.RequiredCraftingLevel(*item); crafting < required {
+		writeError(w, http.StatusBadRequest, "SKILL_TOO_LOW", "crafting stat too low",
+			fmt.Sprintf("need crafting %d, have %d", required, crafting))
+		return
+	}
+	materials := mods.MaterialsFor(*item)
+	if len(c.Inventory) >= c.MaxInventorySlots() {
+		writeError(w, http.StatusBadRequest, "INVENTORY_FULL", "inventory is full", "drop an item first")
+		return
+	}
+	// Verify materials (supports duplicate material requirements).
+	needed := make(map[string]int)
+	for _, mat := range materials {
+		needed[mat]++
+	}
+	have := make(map[string]int)
+	for _, inv := range c.Inventory {
+		have[inv]++
+	}
+	for mat, count := range needed {
+		if have[mat] < count {
+			writeError(w, http.StatusBadRequest, "MISSING_MATERIALS",
+				fmt.Sprintf("missing %d x %s", count-have[mat], mat),
+				"check your inventory for required materials")
+			return
+		}
+	}
+	for _, mat := range materials {
+		c.RemoveFirstItem(mat)
+	}
+	c.Inventory = append(c.Inventory, item.Name)
+	if err := store.Save(r.Context(), c); err != nil {
+		if characterConflict(w, err) {
+			return
+		}
+		slog.Error("craft save failed", "id", c.ID, "error", err)
+		writeError(w, http.StatusInternalServerError, "STORE_ERROR", "failed to save after craft", "check server logs")
+		return
+	}
+	writeJSON(w, http.StatusOK, c)
+}
+
+// handleEquipItem sets a character's equipment slot.
+// POST /api/character/:id/equip  {"slot": "weapon", "item": "Reinforced Bat"}
+// Send item:"" to unequip. The item must be an equippable recipe, and an item
+// already in another slot needs a second copy.
+func handleEquipItem(w http.ResponseWriter, r *http.Request, c *character.Character, store *character.Store) {
+	var req struct {
+		Slot string `json:"slot"` // weapon | armor | accessory
+		Item string `json:"item"` // item name or "" to unequip
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_BODY", "invalid body", `send {"slot":"weapon|armor|accessory","item":"..."}`)
+		return
+	}
+	validSlots := map[string]bool{"weapon": true, "armor": true, "accessory": true}
+	if !validSlots[req.Slot] {
+		writeError(w, http.StatusBadRequest, "INVALID_SLOT", "slot must be weapon, armor, or accessory", "")
+		return
+	}
+	if req.Item != "" {
+		if recipe := resources.CraftableItemByName(req.Item); recipe == nil || !recipe.Equippable {
+			writeError(w, http.StatusBadRequest, "NOT_EQUIPPABLE", req.Item+" can't be equipped", "equippable recipes are listed in /api/items")
+			return
+		}
+		if !c.ContainsItem(req.Item) {
+			writeError(w, http.StatusBadRequest, "NOT_IN_INVENTORY", "item not in inventory", "you can only equip items you're carrying")
+			return
+		}
+		// Each equipped slot needs its own copy.
+		inOtherSlots := 0
+		for slot, item := range map[string]string{"weapon": c.Equipment.Weapon, "armor": c.Equipment.Armor, "accessory": c.Equipment.Accessory} {
+			if slot != req.Slot && item == req.Item {
+				inOtherSlots++
+			}
+		}
+		if c.CountItem(req.Item) <= inOtherSlots {
+			writeError(w, http.StatusBadRequest, "ALREADY_EQUIPPED", "every "+req.Item+" you carry is already equipped", "unequip it first or carry another")
+			return
+		}
+	}
+	switch req.Slot {
+	case "weapon":
+		c.Equipment.Weapon = req.Item
+	case "armor":
+		c.Equipment.Armor = req.Item
+	case "accessory":
+		c.Equipment.Accessory = req.Item
+	}
+	if err := store.Save(r.Context(), c); err != nil {
+		if characterConflict(w, err) {
+			return
+		}
+		slog.Error("equip save failed", "id", c.ID, "error", err)
+		writeError(w, http.StatusInternalServerError, "STORE_ERROR", "failed to save after equip", "check server logs")
+		return
+	}
+	writeJSON(w, http.StatusOK, c)
+}
+
+// handleDropItem removes an item from inventory and auto-unequips it.
+// POST /api/character/:id/item/drop  {"item_name": "Bandage"}
+func handleDropItem(w http.ResponseWriter, r *http.Request, c *character.Character, store *character.Store) {
+	var req struct {
+		ItemName string `json:"item_name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.ItemName == "" {
+		writeError(w, http.StatusBadRequest, "INVALID_BODY", "item_name required", `send {"item_name": "..."}`)
+		return
+	}
+	if !c.RemoveFirstItem(req.ItemName) {
+		writeError(w, http.StatusBadRequest, "NOT_IN_INVENTORY", "item not in inventory", "nothing to drop")
+		return
+	}
+	// Auto-unequip from any slot.
+	c.Unequip(req.ItemName)
+	if err := store.Save(r.Context(), c); err != nil {
+		if characterConflict(w, err) {
+			return
+		}
+		slog.Error("drop save failed", "id", c.ID, "error", err)
+		writeError(w, http.StatusInternalServerError, "STORE_ERROR", "failed to save after drop", "check server logs")
+		return
+	}
+	writeJSON(w, http.StatusOK, c)
+}
+
+// handleUseItem consumes an item for its effect (resources.ItemEffects()).
+// Outside a fight it heals, cures, or buffs the character. With
+// "encounter_id" it takes the character's turn instead: consumables work on
+// the user, thrown items hit every monster.
+// POST /api/character/:id/item/use  {"item_name": "Bandage", "encounter_id": "..."}
+func handleUseItem(w http.ResponseWriter, r *http.Request, c *character.Character, store *character.Store, journal *character.JournalStore, encounters *game.EncounterStore, worlds *game.WorldStore) {
+	var req struct {
+		ItemName    string `json:"item_name"`
+		EncounterID string `json:"encounter_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.ItemName == "" {
+		writeError(w, http.StatusBadRequest, "INVALID_BODY", "item_name required", `send {"item_name": "..."}`)
+		return
+	}
+	if !c.ContainsItem(req.ItemName) {
+		writeError(w, http.StatusBadRequest, "NOT_IN_INVENTORY", "item not in inventory", "you can only use items you're carrying")
+		return
+	}
+	if req.EncounterID != "" {
+		handleUseItemInEncounter(w, r, c, req.ItemName, req.EncounterID, store, journal, encounters, worlds)
+		return
+	}
+	// The fight's sync would overwrite whatever the item did to HP or statuses.
+	fighting, err := encounters.ActiveFor(r.Context(), c.ID)
+	if err != nil {
+		slog.Error("find active encounter failed", "id", c.ID, "error", err)
+		writeError(w, http.StatusInternalServerError, "STORE_ERROR", "failed to load encounters", "check server logs")
+		return
+	}
+	if fighting != "" {
+		writeError(w, http.StatusConflict, "IN_ENCOUNTER", c.Name+" is in a fight", `use it on your turn with "encounter_id": "`+fighting+`"`)
+		return
+	}
+
+	use, err := game.UseItem(c, req.ItemName)
+	if err != nil {
+		writeItemError(w, err)
+		return
+	}
+	c.RemoveFirstItem(req.ItemName)
+	c.Unequip(req.ItemName)
+	if err := store.Save(r.Context(), c); err != nil {
+		if characterConflict(w, err) {
+			return
+		}
+		slog.Error("use item save failed", "id", c.ID, "error", err)
+		writeError(w, http.StatusInternalServerError, "STORE_ERROR", "failed to save after item use", "check server logs")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"character": c,
+		"item":      use.Item,
+		"healed":    use.Healed,
+		"cured":     use.Cured,
+		"applied":   use.Applied,
+	})
+}
+
+// handleUseItemInEncounter spends the character's turn on an item, then
+// consumes it and syncs the party like any other encounter action.
+func handleUseItemInEncounter(w http.ResponseWriter, r *http.Request, c *character.Character, item, encounterID string, store *character.Store, journal *character.JournalStore, encounters *game.EncounterStore, worlds *game.WorldStore) {
+	var events []game.EncounterEvent
+	e, err := encounters.Update(r.Context(), encounterID, func(e *game.Encounter) error {
+		var err error
+		events, err = e.UseItem(c.ID, item)
+		return err
+	})
+	switch {
+	case errors.Is(err, game.ErrEncounterOver):
+		writeError(w, http.StatusConflict, "ENCOUNTER_OVER", err.Error(), "use it outside the fight")
+		return
+	case errors.Is(err, game.ErrNotYourTurn):
+		writeError(w, http.StatusConflict, "NOT_YOUR_TURN", err.Error(), "GET the encounter to see whose turn it is")
+		return
+	case err != nil && !errors.Is(err, game.ErrNotUsable) && !errors.Is(err, game.ErrNoEffect):
+		slog.Error("encounter item use failed", "id", encounterID, "error", err)
+		writeError(w, http.StatusInternalServerError, "STORE_ERROR", "failed to update encounter", "check server logs")
+		return
+	case err != nil:
+		writeItemError(w, err)
+		return
+	case e == nil:
+		writeError(w, http.StatusNotFound, "ENCOUNTER_NOT_FOUND", "no encounter with that ID", "start one with POST /api/encounter")
+		return
+	}
+
+	// The turn is spent, so the item goes from whatever the character holds now.
+	if _, err := store.Update(r.Context(), c.ID, func(c *character.Character) error {
+		c.RemoveFirstItem(item)
+		c.Unequip(item)
+		return nil
+	}); err != nil {
+		slog.Error("use item save failed", "id", c.ID, "error", err)
+		writeError(w, http.StatusInternalServerError, "STORE_ERROR", "failed to save after item use", "check server logs")
+		return
+	}
+	if events, err = settleEncounter(r.Context(), store, journal, encounters, worlds, e, events); err != nil {
+		slog.Error("settle encounter failed", "encounter", e.ID, "error", err)
+		writeError(w, http.StatusInternalServerError, "STORE_ERROR", "failed to save party", "check server logs")
+		return
+	}
+	if fresh, err := store.Load(r.Context(), c.ID); err == nil && fresh != nil {
+		c = fresh
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"character": c,
+		"item":      item,
+		"encounter": e,
+		"events":    events,
+	})
+}
+
+// characterConflict writes a 409 if err says the character was saved by
+// another request since this one loaded it, and reports whether it did.
+func characterConflict(w http.ResponseWriter, err error) bool {
+	if !errors.Is(err, character.ErrStale) {
+		return false
+	}
+	writeError(w, http.StatusConflict, "CHARACTER_CHANGED", err.Error(), "reload the character and try again")
+	return true
+}
+
+// writeItemError maps item rule errors onto HTTP responses.
+func writeItemError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, game.ErrNotUsable):
+		writeError(w, http.StatusBadRequest, "NOT_USABLE", err.Error(), "check item_effects in /api/items")
+	case errors.Is(err, game.ErrNotInCombat):
+		writeError(w, http.StatusBadRequest, "NOT_IN_COMBAT", err.Error(), "pass encounter_id on your turn")
+	case errors.Is(err, game.ErrNoEffect):
+		writeError(w, http.StatusBadRequest, "NO_EFFECT", err.Error(), "save it for later")
+	default:
+		slog.Error("item use failed", "error", err)
+		writeError(w, http.StatusInternalServerError, "STORE_ERROR", "failed to use item", "check server logs")
+	}
+}
+
+// handleVehicleAction repairs or refuels one of the character's vehicles.
+// path is ":vid/repair" or ":vid/refuel". Repairs honour ?seed= with the
+// admin token (see actionSeed).
+func handleVehicleAction(w http.ResponseWriter, r *http.Request, path string, c *character.Character, store *character.Store, adminToken string) {
+	vid, action, _ := strings.Cut(path, "/")
+	resp := map[string]any{}
+	switch action {
+	case "repair":
+		seed, ok := actionSeed(w, r, adminToken)
+		if !ok {
+			return
+		}
+		res, err := game.RepairVehicle(game.NewRNG(seed), c, vid)
+		if err != nil {
+			writeVehicleError(w, err)
+			return
+		}
+		resp["repair"], resp["seed"] = res, seed
+	case "refuel":
+		if _, err := game.Refuel(c, vid); err != nil {
+			writeVehicleError(w, err)
+			return
+		}
+	default:
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "route not found", "use /vehicles/:vid/repair or /vehicles/:vid/refuel")
+		return
+	}
+	if err := store.Save(r.Context(), c); err != nil {
+		if characterConflict(w, err) {
+			return
+		}
+		slog.Error("vehicle save failed", "id", c.ID, "error", err)
+		writeError(w, http.StatusInternalServerError, "STORE_ERROR", "failed to save vehicle", "check server logs")
+		return
+	}
+	resp["character"], resp["vehicle"] = c, c.Vehicle(vid)
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// writeVehicleError maps vehicle rule errors onto HTTP responses.
+func writeVehicleError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, game.ErrUnknownVehicle):
+		writeError(w, http.StatusNotFound, "VEHICLE_NOT_FOUND", err.Error(), "see the character's vehicles list")
+	case errors.Is(err, game.ErrGarageFull):
+		writeError(w, http.StatusConflict, "GARAGE_FULL", err.Error(), fmt.Sprintf("a character can keep %d vehicles", character.MaxVehicles))
+	case errors.Is(err, game.ErrNothingToRepair):
+		writeError(w, http.StatusConflict, "NOTHING_TO_REPAIR", err.Error(), "")
+	case errors.Is(err, game.ErrMissingMaterials):
+		writeError(w, http.StatusBadRequest, "MISSING_MATERIALS", err.Error(), "or carry a "+game.RepairKit)
+	case errors.Is(err, game.ErrVehicleBroken):
+		writeError(w, http.StatusConflict, "VEHICLE_BROKEN", err.Error(), "POST /api/character/:id/vehicles/:vid/repair")
+	case errors.Is(err, game.ErrNoFuel):
+		writeError(w, http.StatusConflict, "NO_FUEL", err.Error(), "POST /api/character/:id/vehicles/:vid/refuel")
+	case errors.Is(err, game.ErrFuelNotNeeded):
+		writeError(w, http.StatusBadRequest, "NO_FUEL_NEEDED", err.Error(), "pedal")
+	case errors.Is(err, game.ErrNoFuelInInventory):
+		writeError(w, http.StatusBadRequest, "NOT_IN_INVENTORY", err.Error(), "scavenge a gas station")
+	case errors.Is(err, game.ErrTooManyPassengers):
+		writeError(w, http.StatusConflict, "OVER_CAPACITY", err.Error(), "leave someone behind or walk")
 	default:
-		writeError(w, http.StatusNotFound, "NOT_FOUND", "route not found", "check path and method")
+		slog.Error("vehicle action failed", "error", err)
+		writeError(w, http.StatusInternalServerError, "STORE_ERROR", "failed to update vehicle", "check server logs")
 	}
 }
 
-// handleUpdateCharacter applies a partial update to a character.
-// PUT /api/character/:id
-func handleUpdateCharacter(w http.ResponseWriter, r *http.Request, c *character.Character, store *character.Store) {
+// handleCharacterScavenge scavenges the tile the character stands on in a
+// world, rolling with their effective scouting and class, and adds what fits
+// to their inventory. Each tile can be scavenged once (World.ScavengeTile).
+// POST /api/character/:id/scavenge  {"world_id": "..."}
+func handleCharacterScavenge(w http.ResponseWriter, r *http.Request, c *character.Character, store *character.Store, worlds *game.WorldStore, adminToken string) {
 	var req struct {
-		HP        *int                 `json:"hp,omitempty"`
-		XP        *int                 `json:"xp,omitempty"`
-		Level     *int                 `json:"level,omitempty"`
-		Inventory []string             `json:"inventory,omitempty"`
-		Equipment *character.Equipment `json:"equipment,omitempty"`
-		Location  string               `json:"location,omitempty"`
+		WorldID string `json:"world_id"`
 	}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, "INVALID_BODY", "invalid JSON body", "send partial character fields")
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.WorldID == "" {
+		writeError(w, http.StatusBadRequest, "INVALID_BODY", "world_id required", `send {"world_id": "..."}`)
 		return
 	}
-	if req.HP != nil {
-		c.HP = *req.HP
-		if c.HP < 0 {
-			c.HP = 0
-		}
-		if c.HP > c.MaxHP {
-			c.HP = c.MaxHP
-		}
+	if c.HP <= 0 {
+		writeError(w, http.StatusBadRequest, "CHARACTER_DOWN", "character is down", "heal before scavenging")
+		return
 	}
-	if req.XP != nil {
-		c.XP = *req.XP
+	seed, ok := actionSeed(w, r, adminToken)
+	if !ok {
+		return
 	}
-	if req.Level != nil {
-		c.Level = *req.Level
+	world, err := worlds.Update(r.Context(), req.WorldID, func(world *game.World) error {
+		_, err := world.ScavengeTile(c.ID)
+		return err
+	})
+	if err != nil || world == nil {
+		respondWorld(w, world, err, "")
+		return
 	}
-	if req.Inventory != nil {
-		c.Inventory = req.Inventory
+	result := seededScavenge{game.Scavenge(game.NewRNG(seed), c.EffectiveStats().Scouting, game.ClassModifiers(c.Class)), seed}
+	names := make([]string, len(result.Found))
+	for i, s := range result.Found {
+		names[i] = s.Name
 	}
-	if req.Equipment != nil {
-		c.Equipment = *req.Equipment
+	var added, leftBehind []string
+	c, err = store.Update(r.Context(), c.ID, func(c *character.Character) error {
+		added, leftBehind = c.AddItems(names)
+		return nil
+	})
+	if err != nil || c == nil {
+		slog.Error("scavenge save failed", "id", req.WorldID, "error", err)
+		writeError(w, http.StatusInternalServerError, "STORE_ERROR", "failed to save after scavenge", "check server logs")
+		return
 	}
-	if req.Location != "" {
-		c.Location = req.Location
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"character":   c,
+		"scavenge":    result,
+		"added":       nonNil(added),
+		"left_behind": nonNil(leftBehind),
+		"world":       world.ViewFor(c.ID),
+	})
+}
+
+// requireAdmin checks the admin token from X-Admin-Token or
+// "Authorization: Bearer". With no token configured, admin routes are disabled.
+// On failure it writes the error response and returns false.
+func requireAdmin(w http.ResponseWriter, r *http.Request, adminToken string) bool {
+	if adminToken == "" {
+		writeError(w, http.StatusForbidden, "ADMIN_DISABLED", "admin overrides are disabled", "set ADMIN_TOKEN to enable them")
+		return false
+	}
+	got := r.Header.Get("X-Admin-Token")
+	if got == "" {
+		got = strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	}
+	if subtle.ConstantTimeCompare([]byte(got), []byte(adminToken)) != 1 {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "admin token required", "send X-Admin-Token")
+		return false
 	}
+	return true
+}
+
+// nonNil keeps empty lists as [] rather than null in responses.
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+// handleLevelUp calls LevelUp() server-side and persists the result.
+// POST /api/character/:id/levelup
+func handleLevelUp(w http.ResponseWriter, r *http.Request, c *character.Character, store *character.Store) {
+	if !c.IsReadyToLevelUp() {
+		writeError(w, http.StatusBadRequest, "NOT_READY", "not enough XP to level up",
+			fmt.Sprintf("need %d XP, currently have %d", character.XPForNextLevel(c.Level), c.XP))
+		return
+	}
+	c.LevelUp()
 	if err := store.Save(r.Context(), c); err != nil {
-		slog.Error("update character failed", "id", c.ID, "error", err)
-		writeError(w, http.StatusInternalServerError, "STORE_ERROR", "failed to save character", "check server logs")
+		if characterConflict(w, err) {
+			return
+		}
+		slog.Error("levelup save failed", "id", c.ID, "error", err)
+		writeError(w, http.StatusInternalServerError, "STORE_ERROR", "failed to save after level up", "check server logs")
 		return
 	}
 	writeJSON(w, http.StatusOK, c)
 }
 
-// handleCraftItem crafts an item: verifies materials, consumes them, adds result.
-// POST /api/character/:id/craft  {"item_name": "Medkit"}
-func handleCraftItem(w http.ResponseWriter, r *http.Request, c *character.Character, store *character.Store) {
+// loadOptionalCharacter loads id when non-empty. On failure it writes the error
+// response and returns ok=false; an empty id returns (nil, true).
+func loadOptionalCharacter(w http.ResponseWriter, r *http.Request, store *character.Store, id string) (*character.Character, bool) {
+	if id == "" {
+		return nil, true
+	}
+	c, err := store.Load(r.Context(), id)
+	if err != nil {
+		slog.Error("load character failed", "id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "STORE_ERROR", "failed to load character", "check server logs")
+		return nil, false
+	}
+	if c == nil {
+		writeError(w, http.StatusNotFound, "CHARACTER_NOT_FOUND", "no character with that ID", "check character_id")
+		return nil, false
+	}
+	return c, true
+}
+
+// requestSeed reads the optional ?seed= query parameter that makes a generated
+// result reproducible, or picks a fresh seed. Responses echo the seed so any
+// map, roll, or encounter can be replayed. On a bad value it writes the error
+// response and returns ok=false.
+func requestSeed(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	raw := r.URL.Query().Get("seed")
+	if raw == "" {
+		return game.NewSeed(), true
+	}
+	seed, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_SEED", "seed must be a 64-bit integer", "omit seed for a random one")
+		return 0, false
+	}
+	return seed, true
+}
+
+// actionSeed is requestSeed for requests that change game state. The server
+// picks the seed, so a player can't search seeds offline for guaranteed loot
+// or rolls; ?seed= is honoured only with the admin token, for replaying a bug.
+func actionSeed(w http.ResponseWriter, r *http.Request, adminToken string) (int64, bool) {
+	if r.URL.Query().Get("seed") == "" {
+		return game.NewSeed(), true
+	}
+	if !requireAdmin(w, r, adminToken) {
+		return 0, false
+	}
+	return requestSeed(w, r)
+}
+
+// seedFor is actionSeed when the request changes state (e.g. it writes a
+// character's journal) and requestSeed for a read-only preview.
+func seedFor(w http.ResponseWriter, r *http.Request, changesState bool, adminToken string) (int64, bool) {
+	if changesState {
+		return actionSeed(w, r, adminToken)
+	}
+	return requestSeed(w, r)
+}
+
+// seededScavenge is a scavenge result with the seed that rolled it.
+type seededScavenge struct {
+	game.ScavengeResult
+	Seed int64 `json:"seed"`
+}
+
+// ── Parties ──────────────────────────────────────────────────────────────────
+
+// handleCreateParty creates a persistent party led by leader_id.
+// POST /api/party  {"name": "...", "leader_id": "...", "member_ids": ["..."]}
+func handleCreateParty(w http.ResponseWriter, r *http.Request, store *character.Store, parties *character.PartyStore) {
 	var req struct {
-		ItemName string `json:"item_name"`
+		Name      string   `json:"name"`
+		LeaderID  string   `json:"leader_id"`
+		MemberIDs []string `json:"member_ids"`
 	}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.ItemName == "" {
-		writeError(w, http.StatusBadRequest, "INVALID_BODY", "item_name required", `send {"item_name": "..."}`)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.LeaderID == "" {
+		writeError(w, http.StatusBadRequest, "INVALID_BODY", "leader_id required", `send {"name": "...", "leader_id": "...", "member_ids": [...]}`)
 		return
 	}
-	item := resources.CraftableItemByName(req.ItemName)
-	if item == nil {
-		writeError(w, http.StatusBadRequest, "UNKNOWN_ITEM", "item is not craftable", "check /api/items for the craftable list")
+	leader, ok := loadOptionalCharacter(w, r, store, req.LeaderID)
+	if !ok {
 		return
 	}
-	if c.Stats.Crafting < item.CraftingLevel {
-		writeError(w, http.StatusBadRequest, "SKILL_TOO_LOW", "crafting stat too low",
-			fmt.Sprintf("need crafting %d, have %d", item.CraftingLevel, c.Stats.Crafting))
-		return
+	now := time.Now().UTC()
+	p := &character.Party{
+		ID:        uuid.New().String(),
+		Name:      req.Name,
+		MemberIDs: []string{},
+		Stash:     []string{},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if p.Name == "" {
+		p.Name = leader.Name + "'s party"
+	}
+	for _, id := range append([]string{leader.ID}, req.MemberIDs...) {
+		if id == leader.ID && p.HasMember(id) {
+			continue // leader listed again in member_ids
+		}
+		if _, ok := loadOptionalCharacter(w, r, store, id); !ok {
+			return
+		}
+		if err := p.AddMember(id); err != nil {
+			writePartyError(w, err)
+			return
+		}
 	}
-	if len(c.Inventory) >= c.MaxInventorySlots() {
-		writeError(w, http.StatusBadRequest, "INVENTORY_FULL", "inventory is full", "drop an item first")
+	if err := parties.Save(r.Context(), p); err != nil {
+		writePartyError(w, err)
 		return
 	}
-	// Verify materials (supports duplicate material requirements).
-	needed := make(map[string]int)
-	for _, mat := range item.Materials {
-		needed[mat]++
+	writeJSON(w, http.StatusCreated, partyView(r.Context(), store, p))
+}
+
+// handlePartyByID routes /api/party/:id sub-resources. Every action that names
+// a character_id requires that character to be a member (or, for join, free).
+//
+// Routes:
+//   GET    /api/party/:id                 — party with member characters
+//   DELETE /api/party/:id                 — disband (the stash is lost)
+//   POST   /api/party/:id/join            — {"character_id": "..."}
+//   POST   /api/party/:id/leave           — {"character_id": "..."} the last one out disbands it
+//   POST   /api/party/:id/leader          — {"character_id": "..."} hand over leadership
+//   POST   /api/party/:id/stash/deposit   — {"character_id": "...", "item_name": "..."}
+//   POST   /api/party/:id/stash/withdraw  — {"character_id": "...", "item_name": "..."}
+//   POST   /api/party/:id/scavenge        — best scout rolls; finds go to the stash
+func handlePartyByID(w http.ResponseWriter, r *http.Request, path string, store *character.Store, parties *character.PartyStore, worlds *game.WorldStore, adminToken string) {
+	parts := strings.SplitN(strings.TrimPrefix(path, "/api/party/"), "/", 2)
+	id := parts[0]
+	sub := ""
+	if len(parts) == 2 {
+		sub = parts[1]
 	}
-	have := make(map[string]int)
-	for _, inv := range c.Inventory {
-		have[inv]++
+	if id == "" {
+		writeError(w, http.StatusBadRequest, "MISSING_ID", "party ID required", "use /api/party/:id")
+		return
 	}
-	for mat, count := range needed {
-		if have[mat] < count {
-			writeError(w, http.StatusBadRequest, "MISSING_MATERIALS",
-				fmt.Sprintf("missing %d x %s", count-have[mat], mat),
-				"check your inventory for required materials")
+
+	switch {
+	case sub == "" && r.Method == http.MethodGet:
+		p, ok := loadParty(w, r, parties, id)
+		if !ok {
 			return
 		}
+		writeJSON(w, http.StatusOK, partyView(r.Context(), store, p))
+
+	case sub == "" && r.Method == http.MethodDelete:
+		if _, ok := loadParty(w, r, parties, id); !ok {
+			return
+		}
+		if err := parties.Delete(r.Context(), id); err != nil {
+			writePartyError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"disbanded": id})
+
+	case sub == "scavenge" && r.Method == http.MethodPost:
+		handlePartyScavenge(w, r, id, store, parties, worlds, adminToken)
+
+	case r.Method == http.MethodPost && (sub == "join" || sub == "leave" || sub == "leader" || sub == "stash/deposit" || sub == "stash/withdraw"):
+		handlePartyMemberAction(w, r, id, sub, store, parties)
+
+	default:
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "route not found", "check path and method")
 	}
-	for _, mat := range item.Materials {
-		c.RemoveFirstItem(mat)
-	}
-	c.Inventory = append(c.Inventory, item.Name)
-	if err := store.Save(r.Context(), c); err != nil {
-		slog.Error("craft save failed", "id", c.ID, "error", err)
-		writeError(w, http.StatusInternalServerError, "STORE_ERROR", "failed to save after craft", "check server logs")
-		return
-	}
-	writeJSON(w, http.StatusOK, c)
 }
 
-// handleEquipItem sets a character's equipment slot.
-// POST /api/character/:id/equip  {"slot": "weapon", "item": "Reinforced Bat"}
-// Send item:"" to unequip.
-func handleEquipItem(w http.ResponseWriter, r *http.Request, c *character.Character, store *character.Store) {
+// handlePartyMemberAction applies one member's join, leave, leader, or stash
+// action. Stash moves also save the character's inventory, after the party.
+func handlePartyMemberAction(w http.ResponseWriter, r *http.Request, id, action string, store *character.Store, parties *character.PartyStore) {
 	var req struct {
-		Slot string `json:"slot"` // weapon | armor | accessory
-		Item string `json:"item"` // item name or "" to unequip
+		CharacterID string `json:"character_id"`
+		ItemName    string `json:"item_name"`
 	}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, "INVALID_BODY", "invalid body", `send {"slot":"weapon|armor|accessory","item":"..."}`)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.CharacterID == "" {
+		writeError(w, http.StatusBadRequest, "INVALID_BODY", "character_id required", `send {"character_id": "..."}`)
 		return
 	}
-	validSlots := map[string]bool{"weapon": true, "armor": true, "accessory": true}
-	if !validSlots[req.Slot] {
-		writeError(w, http.StatusBadRequest, "INVALID_SLOT", "slot must be weapon, armor, or accessory", "")
+	if strings.HasPrefix(action, "stash/") && req.ItemName == "" {
+		writeError(w, http.StatusBadRequest, "INVALID_BODY", "item_name required", `send {"character_id": "...", "item_name": "..."}`)
 		return
 	}
-	if req.Item != "" && !c.ContainsItem(req.Item) {
-		writeError(w, http.StatusBadRequest, "NOT_IN_INVENTORY", "item not in inventory", "you can only equip items you're carrying")
+	c, ok := loadOptionalCharacter(w, r, store, req.CharacterID)
+	if !ok {
 		return
 	}
-	switch req.Slot {
-	case "weapon":
-		c.Equipment.Weapon = req.Item
-	case "armor":
-		c.Equipment.Armor = req.Item
-	case "accessory":
-		c.Equipment.Accessory = req.Item
+
+	// A stash move writes two rows. The party side goes first; if the
+	// character side then fails, undo reverses the stash change.
+	var undo func(*character.Party) error
+	var pack func(*character.Character) error
+	switch action {
+	case "stash/deposit":
+		undo = func(p *character.Party) error { return p.Withdraw(req.ItemName) }
+		pack = func(c *character.Character) error {
+			if !c.RemoveFirstItem(req.ItemName) {
+				return errNotCarrying
+			}
+			c.Unequip(req.ItemName)
+			return nil
+		}
+	case "stash/withdraw":
+		undo = func(p *character.Party) error { return p.Deposit(req.ItemName) }
+		pack = func(c *character.Character) error {
+			if len(c.Inventory) >= c.MaxInventorySlots() {
+				return errInventoryFull
+			}
+			c.Inventory = append(c.Inventory, req.ItemName)
+			return nil
+		}
 	}
-	if err := store.Save(r.Context(), c); err != nil {
-		slog.Error("equip save failed", "id", c.ID, "error", err)
-		writeError(w, http.StatusInternalServerError, "STORE_ERROR", "failed to save after equip", "check server logs")
+	p, err := parties.Update(r.Context(), id, func(p *character.Party) error {
+		if action != "join" && !p.HasMember(c.ID) {
+			return character.ErrNotMember
+		}
+		switch action {
+		case "join":
+			return p.AddMember(c.ID)
+		case "leave":
+			return p.RemoveMember(c.ID)
+		case "leader":
+			p.LeaderID = c.ID
+		case "stash/deposit":
+			if !c.ContainsItem(req.ItemName) {
+				return errNotCarrying
+			}
+			return p.Deposit(req.ItemName)
+		case "stash/withdraw":
+			if len(c.Inventory) >= c.MaxInventorySlots() {
+				return errInventoryFull
+			}
+			return p.Withdraw(req.ItemName)
+		}
+		return nil
+	})
+	if err != nil || p == nil {
+		if err == nil {
+			writeError(w, http.StatusNotFound, "PARTY_NOT_FOUND", "no party with that ID", "create one with POST /api/party")
+			return
+		}
+		writePartyError(w, err)
 		return
 	}
-	writeJSON(w, http.StatusOK, c)
+	if pack != nil {
+		if _, err := store.Update(r.Context(), c.ID, pack); err != nil {
+			if _, uerr := parties.Update(r.Context(), id, undo); uerr != nil {
+				slog.Error("undo stash move failed", "party", id, "character", c.ID, "item", req.ItemName, "error", uerr)
+			}
+			if characterConflict(w, err) {
+				return
+			}
+			writePartyError(w, err)
+			return
+		}
+	}
+	if len(p.MemberIDs) == 0 {
+		if err := parties.Delete(r.Context(), p.ID); err != nil {
+			slog.Error("disband empty party failed", "party", p.ID, "error", err)
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"disbanded": p.ID})
+		return
+	}
+	writeJSON(w, http.StatusOK, partyView(r.Context(), store, p))
 }
 
-// handleDropItem removes an item from inventory and auto-unequips it.
-// POST /api/character/:id/item/drop  {"item_name": "Bandage"}
-func handleDropItem(w http.ResponseWriter, r *http.Request, c *character.Character, store *character.Store) {
-	var req struct {
-		ItemName string `json:"item_name"`
+// handlePartyScavenge scavenges the party's tile with its best scout (highest
+// effective scouting among members who are up). Finds go to the stash. Like a
+// character scavenge, it uses up the tile.
+// POST /api/party/:id/scavenge
+func handlePartyScavenge(w http.ResponseWriter, r *http.Request, id string, store *character.Store, parties *character.PartyStore, worlds *game.WorldStore, adminToken string) {
+	seed, ok := actionSeed(w, r, adminToken)
+	if !ok {
+		return
 	}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.ItemName == "" {
-		writeError(w, http.StatusBadRequest, "INVALID_BODY", "item_name required", `send {"item_name": "..."}`)
+	ids, ok := partyFighters(w, r, store, parties, id)
+	if !ok {
 		return
 	}
-	if !c.RemoveFirstItem(req.ItemName) {
-		writeError(w, http.StatusBadRequest, "NOT_IN_INVENTORY", "item not in inventory", "nothing to drop")
+	var scout *character.Character
+	for _, cid := range ids {
+		c, err := store.Load(r.Context(), cid)
+		if err != nil || c == nil {
+			continue
+		}
+		if scout == nil || c.EffectiveStats().Scouting > scout.EffectiveStats().Scouting {
+			scout = c
+		}
+	}
+	if scout == nil {
+		writeError(w, http.StatusInternalServerError, "STORE_ERROR", "failed to load party members", "check server logs")
 		return
 	}
-	// Auto-unequip from any slot.
-	if c.Equipment.Weapon == req.ItemName {
-		c.Equipment.Weapon = ""
+	party, ok := loadParty(w, r, parties, id)
+	if !ok {
+		return
+	}
+	if party.WorldID == "" {
+		writeError(w, http.StatusConflict, "NOT_ON_TILE", "party has not entered a world", "move there with POST /api/world/:id/move")
+		return
 	}
-	if c.Equipment.Armor == req.ItemName {
-		c.Equipment.Armor = ""
+	world, err := worlds.Update(r.Context(), party.WorldID, func(world *game.World) error {
+		_, err := world.ScavengeTile(scout.ID)
+		return err
+	})
+	if err != nil || world == nil {
+		respondWorld(w, world, err, "")
+		return
 	}
-	if c.Equipment.Accessory == req.ItemName {
-		c.Equipment.Accessory = ""
+	result := seededScavenge{game.Scavenge(game.NewRNG(seed), scout.EffectiveStats().Scouting, game.ClassModifiers(scout.Class)), seed}
+	names := make([]string, len(result.Found))
+	for i, s := range result.Found {
+		names[i] = s.Name
 	}
-	if err := store.Save(r.Context(), c); err != nil {
-		slog.Error("drop save failed", "id", c.ID, "error", err)
-		writeError(w, http.StatusInternalServerError, "STORE_ERROR", "failed to save after drop", "check server logs")
+	var added, leftBehind []string
+	p, err := parties.Update(r.Context(), id, func(p *character.Party) error {
+		added, leftBehind = p.AddToStash(names)
+		return nil
+	})
+	if err != nil || p == nil {
+		writePartyError(w, err)
 		return
 	}
-	writeJSON(w, http.StatusOK, c)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"party":       p,
+		"scout":       scout.Name,
+		"scavenge":    result,
+		"added":       nonNil(added),
+		"left_behind": nonNil(leftBehind),
+	})
 }
 
-// handleLevelUp calls LevelUp() server-side and persists the result.
-// POST /api/character/:id/levelup
-func handleLevelUp(w http.ResponseWriter, r *http.Request, c *character.Character, store *character.Store) {
-	if !c.IsReadyToLevelUp() {
-		writeError(w, http.StatusBadRequest, "NOT_READY", "not enough XP to level up",
-			fmt.Sprintf("need %d XP, currently have %d", character.XPForNextLevel(c.Level), c.XP))
-		return
+var (
+	errNotCarrying   = errors.New("item not in inventory")
+	errInventoryFull = errors.New("inventory is full")
+)
+
+// partyFighters returns the IDs of party members who are up, in join order.
+// Members at 0 HP sit out. It writes the error response and returns ok=false
+// if the party is missing or nobody can act.
+func partyFighters(w http.ResponseWriter, r *http.Request, store *character.Store, parties *character.PartyStore, id string) ([]string, bool) {
+	p, ok := loadParty(w, r, parties, id)
+	if !ok {
+		return nil, false
+	}
+	var ids []string
+	for _, cid := range p.MemberIDs {
+		c, err := store.Load(r.Context(), cid)
+		if err != nil {
+			slog.Error("load character failed", "id", cid, "error", err)
+			writeError(w, http.StatusInternalServerError, "STORE_ERROR", "failed to load character", "check server logs")
+			return nil, false
+		}
+		if c != nil && c.HP > 0 {
+			ids = append(ids, cid)
+		}
 	}
-	c.LevelUp()
-	if err := store.Save(r.Context(), c); err != nil {
-		slog.Error("levelup save failed", "id", c.ID, "error", err)
-		writeError(w, http.StatusInternalServerError, "STORE_ERROR", "failed to save after level up", "check server logs")
-		return
+	if len(ids) == 0 {
+		writeError(w, http.StatusBadRequest, "CHARACTER_DOWN", "everyone in the party is down", "heal someone first")
+		return nil, false
+	}
+	return ids, true
+}
+
+// loadParty loads a party or writes the error response and returns ok=false.
+func loadParty(w http.ResponseWriter, r *http.Request, parties *character.PartyStore, id string) (*character.Party, bool) {
+	p, err := parties.Load(r.Context(), id)
+	if err != nil {
+		slog.Error("load party failed", "id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "STORE_ERROR", "failed to load party", "check server logs")
+		return nil, false
+	}
+	if p == nil {
+		writeError(w, http.StatusNotFound, "PARTY_NOT_FOUND", "no party with that ID", "create one with POST /api/party")
+		return nil, false
+	}
+	return p, true
+}
+
+// partyView is a party with its member characters, leader first.
+func partyView(ctx context.Context, store *character.Store, p *character.Party) map[string]interface{} {
+	members := make([]*character.Character, 0, len(p.MemberIDs))
+	for _, id := range p.MemberIDs {
+		c, err := store.Load(ctx, id)
+		if err != nil || c == nil {
+			continue
+		}
+		members = append(members, c)
+	}
+	return map[string]interface{}{
+		"party":   p,
+		"members": members,
+	}
+}
+
+// writePartyError maps party rule errors onto HTTP responses.
+func writePartyError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, character.ErrPartyFull):
+		writeError(w, http.StatusConflict, "PARTY_FULL", err.Error(), fmt.Sprintf("a party has at most %d members", character.MaxPartySize))
+	case errors.Is(err, character.ErrAlreadyInParty):
+		writeError(w, http.StatusConflict, "ALREADY_IN_PARTY", err.Error(), "leave the other party first")
+	case errors.Is(err, character.ErrNotMember):
+		writeError(w, http.StatusBadRequest, "NOT_A_MEMBER", err.Error(), "join the party first")
+	case errors.Is(err, character.ErrStashFull):
+		writeError(w, http.StatusConflict, "STASH_FULL", err.Error(), fmt.Sprintf("the stash holds %d items", character.MaxStashSlots))
+	case errors.Is(err, character.ErrNotInStash):
+		writeError(w, http.StatusBadRequest, "NOT_IN_STASH", err.Error(), "GET the party to see the stash")
+	case errors.Is(err, errNotCarrying):
+		writeError(w, http.StatusBadRequest, "NOT_IN_INVENTORY", err.Error(), "you can only stash items you're carrying")
+	case errors.Is(err, errInventoryFull):
+		writeError(w, http.StatusBadRequest, "INVENTORY_FULL", err.Error(), "drop an item first")
+	default:
+		slog.Error("party update failed", "error", err)
+		writeError(w, http.StatusInternalServerError, "STORE_ERROR", "failed to update party", "check server logs")
 	}
-	writeJSON(w, http.StatusOK, c)
 }
 
 // ── SRE Middleware ────────────────────────────────────────────────────────────
@@ -620,6 +2780,14 @@ func normalizePath(path string) string {
 		return "/api/building/:action"
 	case strings.HasPrefix(path, "/api/combat/"):
 		return "/api/combat/:action"
+	case strings.HasPrefix(path, "/api/encounter/"):
+		return "/api/encounter/:id"
+	case strings.HasPrefix(path, "/api/world/"):
+		return "/api/world/:id"
+	case strings.HasPrefix(path, "/api/riddle/"):
+		return "/api/riddle/:id"
+	case strings.HasPrefix(path, "/api/party/"):
+		return "/api/party/:id"
 	case strings.HasPrefix(path, "/js/"):
 		return "/js/*"
 	case strings.HasPrefix(path, "/css/"):
@@ -639,8 +2807,26 @@ func (r *statusRecorder) WriteHeader(code int) {
 	r.ResponseWriter.WriteHeader(code)
 }
 
+// Unwrap lets http.ResponseController reach the underlying writer's Flush.
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
 // ── Response helpers ─────────────────────────────────────────────────────────
 
+// writeSSE sends one server-sent event with a JSON payload and flushes it.
+func writeSSE(w http.ResponseWriter, rc *http.ResponseController, event string, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		slog.Error("encode event failed", "error", err)
+		return
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+	if err := rc.Flush(); err != nil {
+		slog.Warn("flush event failed", "error", err)
+	}
+}
+
 func writeJSON(w http.ResponseWriter, code int, v interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(code)
@@ -5,6 +5,7 @@ go 1.23.0
 require (
 	github.com/google/uuid v1.6.0
 	github.com/prometheus/client_golang v1.20.4
+	go.yaml.in/yaml/v3 v3.0.4
 	modernc.org/sqlite v1.33.1
 )
 
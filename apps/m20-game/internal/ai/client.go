@@ -0,0 +1,265 @@
+// Package ai turns game moments into LLM narration for game-flavored AI
+// responses. Riddles power the Sphinx encounter. Monster dialogue adds
+// flavour text. Prompts are templates (prompts/*.tmpl) sent to a pluggable
+// Narrator backend: Ollama, an OpenAI-compatible server, or a fake.
+// Falls back gracefully if the backend is unavailable — the game still works.
+package ai
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"m20-game/internal/obs"
+)
+
+// Client builds prompts from templates, sends them to a Narrator, and falls
+// back to canned lines when it fails.
+type Client struct {
+	narrator Narrator
+	prompts  *Prompts
+	model    atomic.Value // string; swappable on config reload
+	style    atomic.Value // string; swappable on config reload
+	pool     *Pool        // nil: every line is generated on request
+}
+
+// NewClient creates a narration client. style "" means DefaultStyle.
+func NewClient(narrator Narrator, prompts *Prompts, model, style string) *Client {
+	c := &Client{narrator: narrator, prompts: prompts}
+	c.SetModel(model)
+	c.SetStyle(style)
+	return c
+}
+
+// SetModel switches the model used for subsequent requests.
+func (c *Client) SetModel(model string) {
+	c.model.Store(model)
+}
+
+// Model returns the model currently in use.
+func (c *Client) Model() string {
+	m, _ := c.model.Load().(string)
+	return m
+}
+
+// SetStyle switches the narration style prepended to prompts ("" means
+// DefaultStyle).
+func (c *Client) SetStyle(style string) {
+	if style == "" {
+		style = DefaultStyle
+	}
+	c.style.Store(style)
+}
+
+// Style returns the narration style currently in use.
+func (c *Client) Style() string {
+	s, _ := c.style.Load().(string)
+	return s
+}
+
+// generate renders the reqType prompt and sends it to the narrator.
+func (c *Client) generate(ctx context.Context, reqType string, data PromptData) (string, error) {
+	return c.generateStream(ctx, reqType, data, nil)
+}
+
+// generateStream is generate, passing text to onChunk as it arrives if
+// onChunk is set and the narrator is a Streamer. Otherwise onChunk (if set)
+// gets the whole response at once. The response is checked and cleaned by
+// validateOutput; a rejected one is an *OutputError (status "invalid").
+func (c *Client) generateStream(ctx context.Context, reqType string, data PromptData, onChunk func(string)) (string, error) {
+	start := time.Now()
+
+	data.Style = c.Style()
+	prompt, err := c.prompts.Render(reqType, data)
+	if err != nil {
+		obs.AIRequestsTotal.WithLabelValues(reqType, "error").Inc()
+		return "", err
+	}
+
+	req := Request{Type: reqType, Model: c.Model(), Prompt: prompt, JSON: reqType == "riddle"}
+	var resp string
+	if s, ok := c.narrator.(Streamer); ok && onChunk != nil {
+		resp, err = s.Stream(ctx, req, onChunk)
+	} else if resp, err = c.narrator.Generate(ctx, req); err == nil && onChunk != nil {
+		onChunk(resp)
+	}
+	if err != nil && (errors.Is(context.Cause(ctx), errLineFull) || errors.Is(context.Cause(ctx), errBlocked)) {
+		err = nil // Stream had all it could use; validation decides the rest
+	}
+	if err == nil {
+		if resp, err = validateOutput(reqType, prompt, resp); err != nil {
+			var out *OutputError
+			errors.As(err, &out)
+			obs.AIOutputRejectedTotal.WithLabelValues(reqType, out.Reason).Inc()
+			obs.AIRequestsTotal.WithLabelValues(reqType, "invalid").Inc()
+			return "", err
+		}
+	}
+	if err != nil {
+		status := "error"
+		switch {
+		case errors.Is(err, ErrBreakerOpen), errors.Is(err, ErrQueueFull), errors.Is(err, ErrQueueTimeout):
+			status = "rejected"
+		case errors.Is(ctx.Err(), context.Canceled):
+			status = "canceled"
+		case ctx.Err() != nil:
+			status = "timeout"
+		}
+		obs.AIRequestsTotal.WithLabelValues(reqType, status).Inc()
+		return resp, err
+	}
+
+	obs.AIRequestsTotal.WithLabelValues(reqType, "success").Inc()
+	obs.AIRequestDuration.WithLabelValues(reqType).Observe(time.Since(start).Seconds())
+
+	return resp, nil
+}
+
+// narrate serves a pooled line if there is one, otherwise asks the narrator,
+// falling back to fallback on failure.
+func (c *Client) narrate(ctx context.Context, reqType string, data PromptData, fallback string) string {
+	var key string
+	if c.usePool(reqType, data) {
+		key = c.poolKey(reqType, data)
+		if text, ok := c.pool.take(ctx, key, PoolJob{Type: reqType, Data: data}); ok {
+			return text
+		}
+	}
+	resp, err := c.generate(ctx, reqType, data)
+	if err != nil {
+		return fallback
+	}
+	if key != "" {
+		c.pool.remember(key, resp)
+	}
+	return resp
+}
+
+// Reasons Stream stops reading the model early.
+var (
+	errLineFull = errors.New("line limit reached")
+	errBlocked  = errors.New("blocked word in stream")
+)
+
+// Stream narrates like the named method (prompt "entrance", "combat_hit", or
+// "combat_miss"), handing the text to onChunk as the model writes it. A
+// pooled line or the fallback arrives as a single chunk. Output stops at the
+// prompt's line limit, and before any chunk that completes a blocked word.
+// Cancelling ctx (the client went away) stops the model too. It returns the
+// full text, cleaned by validateOutput, and whether it is the fallback; once
+// chunks have gone out, that text is the caller's to show in their place.
+func (c *Client) Stream(ctx context.Context, reqType string, data PromptData, onChunk func(string)) (text string, fallback bool) {
+	var key string
+	if c.usePool(reqType, data) {
+		key = c.poolKey(reqType, data)
+		if text, ok := c.pool.take(ctx, key, PoolJob{Type: reqType, Data: data}); ok {
+			onChunk(text)
+			return text, false
+		}
+	}
+
+	streamCtx, stop := context.WithCancelCause(ctx)
+	defer stop(nil)
+	limit, sent := lineLimit(reqType), 0
+	var streamed strings.Builder
+	resp, err := c.generateStream(streamCtx, reqType, data, func(chunk string) {
+		if sent >= limit || context.Cause(streamCtx) != nil {
+			return
+		}
+		chunk = truncate(chunk, limit-sent)
+		if streamed.WriteString(chunk); checkContent(streamed.String()) != nil {
+			stop(errBlocked)
+			return
+		}
+		sent += len(chunk)
+		onChunk(chunk)
+		if sent >= limit {
+			stop(errLineFull)
+		}
+	})
+	if err != nil {
+		text = fallbackLine(reqType, data)
+		if sent == 0 {
+			onChunk(text)
+		}
+		return text, true
+	}
+	if key != "" {
+		c.pool.remember(key, resp)
+	}
+	return resp, false
+}
+
+// fallbackLine is the canned line for a prompt.
+func fallbackLine(reqType string, d PromptData) string {
+	switch reqType {
+	case "entrance":
+		return fallbackEntrance(d.Building, d.Group)
+	case "dialogue":
+		return fallbackDialogue(d.Monster)
+	case "combat_hit":
+		return fallbackCombatHit(d.Monster, d.Crit)
+	case "combat_miss":
+		return fallbackCombatMiss(d.Monster, d.Crit)
+	}
+	return fallbackVictory(d.Group)
+}
+
+// lineLimit is the longest line served for a prompt type, in bytes.
+func lineLimit(reqType string) int {
+	switch reqType {
+	case "entrance":
+		return 400
+	case "dialogue":
+		return 150
+	}
+	return 200
+}
+
+// RiddleResult is a Sphinx riddle with its answer. The answer is never
+// marshalled; riddle sessions (game.RiddleSession) keep it server-side.
+type RiddleResult struct {
+	Riddle   string `json:"riddle"`
+	Answer   string `json:"-"`
+	Fallback bool   `json:"fallback"` // true if the narrator was unavailable
+}
+
+// GenerateRiddle asks the narrator for a post-apocalyptic Sphinx riddle.
+// Falls back to a hardcoded riddle if it is unavailable.
+func (c *Client) GenerateRiddle(ctx context.Context) RiddleResult {
+	resp, err := c.generate(ctx, "riddle", PromptData{})
+	if err != nil {
+		return fallbackRiddle()
+	}
+	riddle, answer, _ := parseRiddle(resp) // validated by generate
+	return RiddleResult{Riddle: riddle, Answer: answer}
+}
+
+// BuildingEntrance generates flavor text when a player enters a building.
+// It sets the atmosphere before the monster group is revealed. history is
+// the character's summarized journal ("" for none).
+func (c *Client) BuildingEntrance(ctx context.Context, buildingName, groupName, history string) string {
+	return c.narrate(ctx, "entrance", PromptData{Building: buildingName, Group: groupName, History: history}, fallbackEntrance(buildingName, groupName))
+}
+
+// MonsterDialogue generates the opening line when a monster group spots the player.
+func (c *Client) MonsterDialogue(ctx context.Context, monsterName string) string {
+	return c.narrate(ctx, "dialogue", PromptData{Monster: monsterName}, fallbackDialogue(monsterName))
+}
+
+// CombatHit describes what a successful hit on a monster looks and feels like.
+func (c *Client) CombatHit(ctx context.Context, monsterName, characterClass string, isCrit bool, history string) string {
+	return c.narrate(ctx, "combat_hit", PromptData{Monster: monsterName, Class: characterClass, Crit: isCrit, History: history}, fallbackCombatHit(monsterName, isCrit))
+}
+
+// CombatMiss describes a failed attack — the scramble, the near miss, the panic.
+func (c *Client) CombatMiss(ctx context.Context, monsterName string, isCritFail bool, history string) string {
+	return c.narrate(ctx, "combat_miss", PromptData{Monster: monsterName, Crit: isCritFail, History: history}, fallbackCombatMiss(monsterName, isCritFail))
+}
+
+// MonsterDefeated describes the aftermath when all monsters in a group are beaten.
+func (c *Client) MonsterDefeated(ctx context.Context, groupName, buildingName string) string {
+	return c.narrate(ctx, "victory", PromptData{Group: groupName, Building: buildingName}, fallbackVictory(groupName))
+}
@@ -0,0 +1,173 @@
+package ai
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+func newTestClient(t *testing.T, n Narrator) *Client {
+	t.Helper()
+	prompts, err := LoadPrompts("")
+	if err != nil {
+		t.Fatalf("LoadPrompts failed: %v", err)
+	}
+	return NewClient(n, prompts, "test-model", "")
+}
+
+func TestClient_Narrate(t *testing.T) {
+	ctx := context.Background()
+	cases := []struct {
+		name         string
+		fake         *Fake
+		call         func(c *Client) string
+		want         string // exact line; "" checks wantFallback instead
+		wantFallback string
+	}{
+		{
+			name: "stage direction stripped",
+			fake: &Fake{Replies: map[string]string{"dialogue": "[dialogue] *hisses* Fresh meat!"}},
+			call: func(c *Client) string { return c.MonsterDialogue(ctx, "Zombie") },
+			want: "Fresh meat!",
+		},
+		{
+			name: "canned fake line",
+			fake: NewFake(),
+			call: func(c *Client) string { return c.CombatMiss(ctx, "Zombie", false, "") },
+		},
+		{
+			name:         "narrator down",
+			fake:         &Fake{Err: errors.New("connection refused")},
+			call:         func(c *Client) string { return c.BuildingEntrance(ctx, "Mall", "Shamblers", "") },
+			wantFallback: fallbackEntrance("Mall", "Shamblers"),
+		},
+		{
+			name:         "blocked reply",
+			fake:         &Fake{Replies: map[string]string{"victory": "The nazi zombies are gone."}},
+			call:         func(c *Client) string { return c.MonsterDefeated(ctx, "Shamblers", "Mall") },
+			wantFallback: fallbackVictory("Shamblers"),
+		},
+		{
+			name:         "refusal",
+			fake:         &Fake{Replies: map[string]string{"combat_hit": "I'm sorry, but I can't help with that."}},
+			call:         func(c *Client) string { return c.CombatHit(ctx, "Zombie", "Brawler", true, "") },
+			wantFallback: fallbackCombatHit("Zombie", true),
+		},
+	}
+	for _, tc := range cases {
+		got := tc.call(newTestClient(t, tc.fake))
+		switch {
+		case tc.wantFallback != "":
+			if got != tc.wantFallback {
+				t.Errorf("%s: got %q, want the fallback %q", tc.name, got, tc.wantFallback)
+			}
+		case tc.want != "":
+			if got != tc.want {
+				t.Errorf("%s: got %q, want %q", tc.name, got, tc.want)
+			}
+		default:
+			if got == "" || strings.HasPrefix(got, "[") {
+				t.Errorf("%s: expected a cleaned canned line, got %q", tc.name, got)
+			}
+		}
+	}
+}
+
+func TestClient_GenerateRiddle(t *testing.T) {
+	ctx := context.Background()
+	cases := []struct {
+		name         string
+		fake         *Fake
+		wantAnswer   string // "" for any answer
+		wantFallback bool
+	}{
+		{"json reply", &Fake{Replies: map[string]string{"riddle": `{"riddle": "What has hands but cannot clap?", "answer": "clock"}`}}, "clock", false},
+		{"canned fake riddle", NewFake(), "", false},
+		{"narrator down", &Fake{Err: errors.New("timeout")}, "", true},
+		{"unusable reply", &Fake{Replies: map[string]string{"riddle": "The Sphinx yawns."}}, "", true},
+	}
+	for _, tc := range cases {
+		r := newTestClient(t, tc.fake).GenerateRiddle(ctx)
+		if r.Fallback != tc.wantFallback {
+			t.Errorf("%s: Fallback = %v, want %v", tc.name, r.Fallback, tc.wantFallback)
+		}
+		if r.Riddle == "" || r.Answer == "" {
+			t.Errorf("%s: expected a riddle and answer, got %+v", tc.name, r)
+		}
+		if tc.wantAnswer != "" && r.Answer != tc.wantAnswer {
+			t.Errorf("%s: answer %q, want %q", tc.name, r.Answer, tc.wantAnswer)
+		}
+	}
+}
+
+func TestClient_Stream(t *testing.T) {
+	ctx := context.Background()
+	cases := []struct {
+		name         string
+		fake         *Fake
+		wantFallback bool
+		wantChunks   int // minimum chunks handed to onChunk
+	}{
+		{"word by word", &Fake{Replies: map[string]string{"combat_hit": "The zombie folds like a lawn chair."}}, false, 6},
+		{"narrator down", &Fake{Err: errors.New("connection refused")}, true, 1},
+		{"stops before a blocked word", &Fake{Replies: map[string]string{"combat_hit": "The zombie salutes like a nazi and falls."}}, true, 1},
+	}
+	for _, tc := range cases {
+		var chunks []string
+		text, fallback := newTestClient(t, tc.fake).Stream(ctx, "combat_hit", PromptData{Monster: "Zombie", Class: "Brawler"}, func(s string) {
+			chunks = append(chunks, s)
+		})
+		if fallback != tc.wantFallback {
+			t.Errorf("%s: fallback = %v, want %v", tc.name, fallback, tc.wantFallback)
+		}
+		if len(chunks) < tc.wantChunks {
+			t.Errorf("%s: got %d chunks, want at least %d", tc.name, len(chunks), tc.wantChunks)
+		}
+		if sent := strings.Join(chunks, ""); strings.Contains(strings.ToLower(sent), "nazi") {
+			t.Errorf("%s: blocked word reached the client: %q", tc.name, sent)
+		}
+		if !tc.wantFallback && strings.TrimSpace(strings.Join(chunks, "")) != text {
+			t.Errorf("%s: chunks %q don't add up to %q", tc.name, chunks, text)
+		}
+	}
+}
+
+func TestClient_PoolSkipsHistory(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "pool.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+	store, err := NewPoolStore(db)
+	if err != nil {
+		t.Fatalf("NewPoolStore failed: %v", err)
+	}
+	ctx := context.Background()
+	c := newTestClient(t, &Fake{Replies: map[string]string{"combat_miss": "Fresh from the model."}})
+	c.UsePool(NewPool(store, 2))
+
+	data := PromptData{Monster: "Zombie"}
+	key := c.poolKey("combat_miss", data)
+	if err := store.Add(ctx, "combat_miss", key, "From the pool."); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	cases := []struct {
+		name    string
+		history string
+		want    string
+	}{
+		{"history goes to the model", "Day 3: fled the mall.", "Fresh from the model."},
+		{"no history takes the pooled line", "", "From the pool."},
+	}
+	for _, tc := range cases {
+		if got := c.CombatMiss(ctx, "Zombie", false, tc.history); got != tc.want {
+			t.Errorf("%s: got %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strings"
+)
+
+// Fake is a deterministic Narrator for tests and offline play: the same
+// request always gets the same reply, and nothing touches the network.
+type Fake struct {
+	Replies map[string]string // by request type; overrides the canned lines
+	Err     error             // if set, every call fails with it (exercises fallbacks)
+}
+
+// NewFake returns a Fake with the canned lines.
+func NewFake() *Fake {
+	return &Fake{}
+}
+
+var fakeRiddles = []string{
+	`{"riddle": "I have a mouth but never eat, a bed but never sleep.", "answer": "river"}`,
+	`{"riddle": "The more you take, the more you leave behind.", "answer": "footsteps"}`,
+	`{"riddle": "I run out of gas, out of luck, and out of survivors.", "answer": "time"}`,
+}
+
+var fakeLines = []string{
+	"Something moves in the dark, and it is definitely not the cat.",
+	"A dramatic thunderclap sounds indoors, which should not be possible.",
+	"Somewhere, an organ plays a single ominous chord.",
+}
+
+// Generate returns Replies[req.Type] if set, otherwise a canned line chosen
+// by hashing the prompt. Riddle requests get the JSON the riddle prompt asks for.
+func (f *Fake) Generate(ctx context.Context, req Request) (string, error) {
+	if f.Err != nil {
+		return "", f.Err
+	}
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	if reply, ok := f.Replies[req.Type]; ok {
+		return reply, nil
+	}
+	h := fnv.New32a()
+	h.Write([]byte(req.Prompt))
+	n := int(h.Sum32() % 3)
+	if req.Type == "riddle" {
+		return fakeRiddles[n], nil
+	}
+	return fmt.Sprintf("[%s] %s", req.Type, fakeLines[n]), nil
+}
+
+// Stream returns the same reply as Generate, handed to onChunk a word at a
+// time.
+func (f *Fake) Stream(ctx context.Context, req Request, onChunk func(string)) (string, error) {
+	reply, err := f.Generate(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	var sent strings.Builder
+	for _, word := range strings.SplitAfter(reply, " ") {
+		if err := ctx.Err(); err != nil {
+			return sent.String(), err
+		}
+		sent.WriteString(word)
+		onChunk(word)
+	}
+	return reply, nil
+}
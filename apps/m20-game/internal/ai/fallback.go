@@ -0,0 +1,74 @@
+package ai
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// fallbackRiddles are served, one at random, when the narrator is down. A
+// single canned riddle would hand out the answer for as long as the breaker
+// stays open.
+var fallbackRiddles = []struct{ Riddle, Answer string }{
+	{"I am always ahead of you but never in front. What am I?", "Tomorrow"},
+	{"I have keys but open no locks, and since the grid went down nobody plays me. What am I?", "Piano"},
+	{"The more of me you take, the more you leave behind on the road out of town. What am I?", "Footsteps"},
+	{"I have a mouth but never eat, a bed but never sleep, and I still run past the ruins. What am I?", "River"},
+	{"I fall without ever getting hurt, and every survivor puts a bucket out for me. What am I?", "Rain"},
+	{"I am lighter than a feather, yet the strongest brute can't hold me for five minutes. What am I?", "Breath"},
+	{"I have a neck but no head, and I still hold the last clean water in the camp. What am I?", "Bottle"},
+	{"I go up and never come down, no matter how long the apocalypse drags on. What am I?", "Age"},
+	{"I have hands but cannot clap, and I keep ticking long after the town stopped. What am I?", "Clock"},
+	{"The more there is of me, the less you see. Every monster prefers me. What am I?", "Darkness"},
+	{"I am full of holes yet I keep the flies off your face at night. What am I?", "Net"},
+	{"I follow you all day and vanish at night, and even the zombies have one. What am I?", "Shadow"},
+	{"Say my name and I disappear. The whole camp keeps me when a horde walks past. What am I?", "Silence"},
+	{"I have cities but no houses, roads but no cars, and I got you this far. What am I?", "Map"},
+	{"Feed me and I live, give me water and I die. Every camp needs me by nightfall. What am I?", "Fire"},
+	{"I have a spine but no bones, and survivors burn me for warmth when the shelves run dry. What am I?", "Book"},
+}
+
+func fallbackRiddle() RiddleResult {
+	r := fallbackRiddles[rand.Intn(len(fallbackRiddles))]
+	return RiddleResult{Riddle: r.Riddle, Answer: r.Answer, Fallback: true}
+}
+
+func fallbackEntrance(building, group string) string {
+	return fmt.Sprintf("You kick open the door of the %s. It smells like bad decisions and something that used to be a person. The %s hasn't spotted you yet — which honestly feels like a personal insult.", building, group)
+}
+
+func fallbackDialogue(monster string) string {
+	lines := map[string]string{
+		"Zombie":       "...(groaning intensifies dramatically)...",
+		"Vampire":      "You are SO overdressed for this apocalypse. Unlike me.",
+		"Werewolf":     "*(low territorial growl that somehow sounds offended)*",
+		"Mummy":        "I have been waiting 3,000 years for someone to bother me.",
+		"Frankenstein": "NEW. THING. HERE. FRIEND? ...No.",
+		"Basilisk":     "*(the sound of scales and very smug silence)*",
+		"Golem":        "INTRUDER. PROTOCOL. INITIATED. HAVE A NICE DAY.",
+		"Sphinx":       "Wrong answer means I eat you. Right answer means I also eat you. ...Kidding. Maybe.",
+		"Wraith":       "*(the temperature drops and something is VERY upset)*",
+		"Windego":      "I remember being human once. I also remember pizza. Both are gone.",
+	}
+	if line, ok := lines[monster]; ok {
+		return line
+	}
+	return fmt.Sprintf("The %s looks at you with the energy of someone who has been waiting all day for this.", monster)
+}
+
+func fallbackCombatHit(monster string, isCrit bool) string {
+	if isCrit {
+		return fmt.Sprintf("SPECTACULAR hit — the %s does the full movie stagger and lands in something unfortunate.", monster)
+	}
+	return fmt.Sprintf("Your attack connects! The %s reels dramatically. Points for commitment.", monster)
+}
+
+func fallbackCombatMiss(monster string, isCritFail bool) string {
+	if isCritFail {
+		return fmt.Sprintf("You trip over literally nothing and the %s judges you openly for it.", monster)
+	}
+	return fmt.Sprintf("Swing and a miss! The %s seems almost embarrassed for you.", monster)
+}
+
+func fallbackVictory(group string) string {
+	return fmt.Sprintf("The %s is history. You do a mental victory lap. The room is yours now. It smells terrible. Totally worth it.", group)
+}
@@ -0,0 +1,212 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"m20-game/internal/obs"
+)
+
+// Errors a Guard returns without calling the backend. Client treats them like
+// any other failure and serves the fallback line.
+var (
+	ErrBreakerOpen  = errors.New("ai: circuit breaker open")
+	ErrQueueFull    = errors.New("ai: generation queue full")
+	ErrQueueTimeout = errors.New("ai: deadline passed while queued")
+)
+
+// Breaker states, as reported by the m20_ai_breaker_state gauge.
+const (
+	BreakerClosed   = "closed"    // calls go through
+	BreakerOpen     = "open"      // calls fail fast until the cooldown ends
+	BreakerHalfOpen = "half_open" // one probe call decides
+)
+
+// GuardOptions tunes a Guard (config ai_max_in_flight, ai_queue_size,
+// ai_breaker_failures, ai_breaker_cooldown).
+type GuardOptions struct {
+	MaxInFlight int           // generations running at once
+	QueueSize   int           // callers allowed to wait for a slot
+	Failures    int           // consecutive failures that open the breaker
+	Cooldown    time.Duration // how long the breaker stays open before a probe
+}
+
+// Guard wraps a Narrator with a circuit breaker and a bounded queue, so a dead
+// or slow model costs callers nothing and a CPU-only model is never asked for
+// more than MaxInFlight generations at once.
+//
+// Callers wait for a slot for as long as their context allows, and at most
+// QueueSize of them wait at a time. After Failures backend errors in a row
+// the breaker opens: calls fail at once with ErrBreakerOpen until Cooldown
+// has passed, then a single probe is let through and its result closes or
+// re-opens it. A caller cancelling (e.g. a browser leaving a stream) is not a
+// backend failure.
+type Guard struct {
+	next  Narrator
+	opts  GuardOptions
+	slots chan struct{}
+
+	mu       sync.Mutex
+	waiting  int
+	state    string
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// NewGuard wraps next.
+func NewGuard(next Narrator, opts GuardOptions) *Guard {
+	g := &Guard{next: next, opts: opts, slots: make(chan struct{}, max(1, opts.MaxInFlight))}
+	g.setState(BreakerClosed)
+	return g
+}
+
+// State returns the breaker state.
+func (g *Guard) State() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.state
+}
+
+// Generate runs next.Generate under the breaker and the in-flight limit.
+func (g *Guard) Generate(ctx context.Context, req Request) (string, error) {
+	return g.run(ctx, func() (string, error) { return g.next.Generate(ctx, req) })
+}
+
+// Stream runs next's Stream (or Generate, delivered as one chunk, if next
+// can't stream) under the breaker and the in-flight limit.
+func (g *Guard) Stream(ctx context.Context, req Request, onChunk func(string)) (string, error) {
+	return g.run(ctx, func() (string, error) {
+		if s, ok := g.next.(Streamer); ok {
+			return s.Stream(ctx, req, onChunk)
+		}
+		text, err := g.next.Generate(ctx, req)
+		if err == nil {
+			onChunk(text)
+		}
+		return text, err
+	})
+}
+
+func (g *Guard) run(ctx context.Context, call func() (string, error)) (string, error) {
+	probe, err := g.allow()
+	if err != nil {
+		return "", err
+	}
+	if err := g.acquire(ctx); err != nil {
+		g.release(probe)
+		return "", err
+	}
+	text, err := call()
+	<-g.slots
+	obs.AIInFlight.Dec()
+	g.record(ctx, probe, err)
+	return text, err
+}
+
+// allow checks the breaker. probe is true if this call is the half-open probe.
+func (g *Guard) allow() (probe bool, err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	switch g.state {
+	case BreakerOpen:
+		if time.Since(g.openedAt) < g.opts.Cooldown {
+			return false, ErrBreakerOpen
+		}
+		g.setState(BreakerHalfOpen)
+		fallthrough
+	case BreakerHalfOpen:
+		if g.probing {
+			return false, ErrBreakerOpen
+		}
+		g.probing = true
+		return true, nil
+	}
+	return false, nil
+}
+
+// release gives back a probe that never reached the backend.
+func (g *Guard) release(probe bool) {
+	if probe {
+		g.mu.Lock()
+		g.probing = false
+		g.mu.Unlock()
+	}
+}
+
+// acquire waits for an in-flight slot until ctx is done.
+func (g *Guard) acquire(ctx context.Context) error {
+	select {
+	case g.slots <- struct{}{}:
+		obs.AIInFlight.Inc()
+		return nil
+	default:
+	}
+
+	g.mu.Lock()
+	if g.waiting >= g.opts.QueueSize {
+		g.mu.Unlock()
+		return ErrQueueFull
+	}
+	g.waiting++
+	obs.AIQueueDepth.Set(float64(g.waiting))
+	g.mu.Unlock()
+	defer func() {
+		g.mu.Lock()
+		g.waiting--
+		obs.AIQueueDepth.Set(float64(g.waiting))
+		g.mu.Unlock()
+	}()
+
+	select {
+	case g.slots <- struct{}{}:
+		obs.AIInFlight.Inc()
+		return nil
+	case <-ctx.Done():
+		return ErrQueueTimeout
+	}
+}
+
+// record feeds a finished call's outcome to the breaker.
+func (g *Guard) record(ctx context.Context, probe bool, err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if probe {
+		g.probing = false
+	}
+	// The caller going away, or Client stopping a stream at the line limit,
+	// says nothing about the backend's health. A cancelled probe just lets
+	// the next call probe instead.
+	if err != nil && errors.Is(ctx.Err(), context.Canceled) {
+		return
+	}
+	if err == nil {
+		g.failures = 0
+		if g.state != BreakerClosed {
+			g.setState(BreakerClosed)
+		}
+		return
+	}
+	g.failures++
+	if g.state == BreakerHalfOpen || (g.state == BreakerClosed && g.failures >= g.opts.Failures) {
+		g.setState(BreakerOpen)
+		g.openedAt = time.Now()
+	}
+}
+
+// setState moves the breaker and updates its metrics. g.mu must be held.
+func (g *Guard) setState(state string) {
+	if g.state != "" {
+		obs.AIBreakerTransitionsTotal.WithLabelValues(state).Inc()
+	}
+	g.state = state
+	for _, s := range []string{BreakerClosed, BreakerHalfOpen, BreakerOpen} {
+		v := 0.0
+		if s == state {
+			v = 1
+		}
+		obs.AIBreakerState.WithLabelValues(s).Set(v)
+	}
+}
@@ -0,0 +1,110 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// blockingNarrator holds every call until release is closed.
+type blockingNarrator struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingNarrator) Generate(ctx context.Context, req Request) (string, error) {
+	b.started <- struct{}{}
+	select {
+	case <-b.release:
+		return "done", nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+func TestGuard_BreakerTransitions(t *testing.T) {
+	fake := NewFake()
+	g := NewGuard(fake, GuardOptions{MaxInFlight: 1, QueueSize: 1, Failures: 2, Cooldown: 20 * time.Millisecond})
+	ctx := context.Background()
+	req := Request{Type: "entrance", Prompt: "p"}
+	backendDown := errors.New("connection refused")
+
+	steps := []struct {
+		name      string
+		err       error // the fake's error for this call
+		wait      time.Duration
+		wantErr   error // nil: the call succeeds
+		wantState string
+	}{
+		{"healthy call", nil, 0, nil, BreakerClosed},
+		{"first failure stays closed", backendDown, 0, backendDown, BreakerClosed},
+		{"second failure opens", backendDown, 0, backendDown, BreakerOpen},
+		{"open fails fast", nil, 0, ErrBreakerOpen, BreakerOpen},
+		{"failed probe re-opens", backendDown, 30 * time.Millisecond, backendDown, BreakerOpen},
+		{"still cooling down", nil, 0, ErrBreakerOpen, BreakerOpen},
+		{"good probe closes", nil, 30 * time.Millisecond, nil, BreakerClosed},
+		{"one failure after recovery stays closed", backendDown, 0, backendDown, BreakerClosed},
+	}
+	for _, s := range steps {
+		time.Sleep(s.wait)
+		fake.Err = s.err
+		_, err := g.Generate(ctx, req)
+		if !errors.Is(err, s.wantErr) {
+			t.Errorf("%s: got error %v, want %v", s.name, err, s.wantErr)
+		}
+		if got := g.State(); got != s.wantState {
+			t.Errorf("%s: state %s, want %s", s.name, got, s.wantState)
+		}
+	}
+}
+
+func TestGuard_CancelledCallerIsNotAFailure(t *testing.T) {
+	g := NewGuard(NewFake(), GuardOptions{MaxInFlight: 1, QueueSize: 1, Failures: 1, Cooldown: time.Minute})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := g.Generate(ctx, Request{Type: "entrance"}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if got := g.State(); got != BreakerClosed {
+		t.Errorf("Expected the breaker closed after a cancelled call, got %s", got)
+	}
+}
+
+func TestGuard_QueueLimits(t *testing.T) {
+	b := &blockingNarrator{started: make(chan struct{}, 1), release: make(chan struct{})}
+	g := NewGuard(b, GuardOptions{MaxInFlight: 1, QueueSize: 1, Failures: 5, Cooldown: time.Minute})
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := g.Generate(context.Background(), Request{Type: "entrance"})
+		done <- err
+	}()
+	<-b.started // holds the only slot
+
+	// One caller may wait; it gives up when its deadline passes.
+	waitCtx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	queued := make(chan error, 1)
+	go func() {
+		_, err := g.Generate(waitCtx, Request{Type: "entrance"})
+		queued <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	// A second waiter is over QueueSize and is turned away at once.
+	if _, err := g.Generate(context.Background(), Request{Type: "entrance"}); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("Expected ErrQueueFull, got %v", err)
+	}
+	if err := <-queued; !errors.Is(err, ErrQueueTimeout) {
+		t.Errorf("Expected ErrQueueTimeout for the waiter, got %v", err)
+	}
+
+	close(b.release)
+	if err := <-done; err != nil {
+		t.Errorf("Expected the running call to finish, got %v", err)
+	}
+	if got := g.State(); got != BreakerClosed {
+		t.Errorf("Expected queue rejections not to trip the breaker, got %s", got)
+	}
+}
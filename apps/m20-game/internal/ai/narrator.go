@@ -0,0 +1,32 @@
+package ai
+
+import "context"
+
+// Backends accepted by config ai_backend.
+const (
+	BackendOllama = "ollama" // Ollama /api/generate
+	BackendOpenAI = "openai" // any OpenAI-compatible /v1/chat/completions server
+	BackendFake   = "fake"   // canned, deterministic lines; no network
+)
+
+// Request is one prompt for a Narrator.
+type Request struct {
+	Type   string // riddle | entrance | dialogue | combat_hit | combat_miss | victory (metric label)
+	Model  string
+	Prompt string
+	JSON   bool // ask the backend for a JSON object (riddles) where it supports that
+}
+
+// Narrator is an LLM backend: it turns a rendered prompt into text. It knows
+// nothing about the game; Client builds the prompts and falls back when a
+// Narrator errors.
+type Narrator interface {
+	Generate(ctx context.Context, req Request) (string, error)
+}
+
+// Streamer is a Narrator that can hand over a response piece by piece as the
+// model writes it. onChunk gets each piece in order; the full text is also
+// returned. Stream stops early, with ctx's error, when ctx is cancelled.
+type Streamer interface {
+	Stream(ctx context.Context, req Request, onChunk func(string)) (string, error)
+}
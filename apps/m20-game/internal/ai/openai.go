@@ -0,0 +1,99 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// OpenAI is a Narrator for any OpenAI-compatible /v1/chat/completions server
+// (OpenAI itself, vLLM, llama.cpp server, LM Studio, ...).
+type OpenAI struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+}
+
+// NewOpenAI creates an OpenAI-compatible backend. baseURL is the server root,
+// without /v1. apiKey may be empty for local servers that don't check it.
+func NewOpenAI(baseURL, apiKey string, timeout time.Duration) *OpenAI {
+	return &OpenAI{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		apiKey:     apiKey,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatRequest struct {
+	Model          string          `json:"model"`
+	Messages       []chatMessage   `json:"messages"`
+	Stream         bool            `json:"stream"`
+	ResponseFormat *responseFormat `json:"response_format,omitempty"`
+}
+
+type responseFormat struct {
+	Type string `json:"type"` // "json_object"
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+}
+
+// Generate sends the prompt as a single user message and returns the first
+// choice's content. r.JSON turns on JSON mode (response_format json_object).
+func (o *OpenAI) Generate(ctx context.Context, r Request) (string, error) {
+	chat := chatRequest{
+		Model:    r.Model,
+		Messages: []chatMessage{{Role: "user", Content: r.Prompt}},
+		Stream:   false,
+	}
+	if r.JSON {
+		chat.ResponseFormat = &responseFormat{Type: "json_object"}
+	}
+	body, _ := json.Marshal(chat)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.baseURL+"/v1/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if o.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+o.apiKey)
+	}
+
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("openai request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("openai: status %d: %s", resp.StatusCode, truncate(string(data), 200))
+	}
+
+	var result chatResponse
+	if err := json.Unmarshal(data, &result); err != nil {
+		return "", fmt.Errorf("decode response: %w", err)
+	}
+	if len(result.Choices) == 0 {
+		return "", errors.New("openai: response has no choices")
+	}
+	return result.Choices[0].Message.Content, nil
+}
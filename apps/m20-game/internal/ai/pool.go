@@ -0,0 +1,246 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"m20-game/internal/obs"
+)
+
+// poolCacheLines is how many recent lines per key are kept in memory to
+// re-serve while the pool for that key is empty.
+const poolCacheLines = 8
+
+// poolCacheKeys caps how many keys the in-memory cache holds; past it, a new
+// key evicts an arbitrary old one.
+const poolCacheKeys = 1024
+
+// poolMaxAge is how long a pre-generated line may wait before RunPool drops it.
+const poolMaxAge = 7 * 24 * time.Hour
+
+// PoolJob asks the refill worker to top up one key: a prompt type and its
+// inputs. Only entrance, dialogue, combat_hit, and combat_miss are pooled.
+type PoolJob struct {
+	Type string
+	Data PromptData
+}
+
+// queuedJob is a refill job and the key it was queued under.
+type queuedJob struct {
+	key string
+	job PoolJob
+}
+
+// Pool serves narration from lines generated ahead of time, so a request
+// never waits on the model once its key has been seen. A served line is gone
+// from the store; a background worker (Client.RunPool) refills every key that
+// drops below size. While a key's pool is empty, a recently served line from
+// the in-memory cache is repeated instead.
+type Pool struct {
+	store *PoolStore
+	size  int
+	jobs  chan queuedJob
+
+	mu     sync.Mutex
+	queued map[string]bool     // keys with a refill job waiting
+	cache  map[string][]string // key → recent lines, newest last
+}
+
+// NewPool creates a pool that keeps size lines per key in store.
+func NewPool(store *PoolStore, size int) *Pool {
+	return &Pool{
+		store:  store,
+		size:   size,
+		jobs:   make(chan queuedJob, 256),
+		queued: make(map[string]bool),
+		cache:  make(map[string][]string),
+	}
+}
+
+// usePool reports whether a request goes through the pool. Prompts that carry
+// a character's history are one-offs, so they always go to the model.
+func (c *Client) usePool(reqType string, d PromptData) bool {
+	return c.pool != nil && pooled(reqType) && d.History == ""
+}
+
+// pooled reports whether reqType is served from the pool.
+func pooled(reqType string) bool {
+	switch reqType {
+	case "entrance", "dialogue", "combat_hit", "combat_miss":
+		return true
+	}
+	return false
+}
+
+// poolKey identifies a prompt by its type and inputs, plus a hash of the
+// model and rendered prompt, so lines written under an old style, template,
+// or model are never served after a change (Prune clears them out).
+func (c *Client) poolKey(reqType string, d PromptData) string {
+	d.Style = c.Style()
+	prompt, _ := c.prompts.Render(reqType, d)
+	h := fnv.New64a()
+	h.Write([]byte(c.Model() + "\n" + prompt))
+	return fmt.Sprintf("%s|%x", inputKey(reqType, d), h.Sum64())
+}
+
+// inputKey is the readable part of a pool key: type and inputs.
+func inputKey(reqType string, d PromptData) string {
+	parts := []string{reqType}
+	switch reqType {
+	case "entrance":
+		parts = append(parts, d.Building, d.Group)
+	case "dialogue":
+		parts = append(parts, d.Monster)
+	case "combat_hit":
+		parts = append(parts, d.Monster, d.Class)
+	case "combat_miss":
+		parts = append(parts, d.Monster)
+	}
+	if d.Crit {
+		parts = append(parts, "crit")
+	}
+	return strings.Join(parts, "|")
+}
+
+// take returns a pooled line, or a cached one if the pool is dry, and queues
+// a refill either way. ok is false if there is neither.
+func (p *Pool) take(ctx context.Context, key string, job PoolJob) (string, bool) {
+	reqType := job.Type
+	defer p.queue(key, job)
+
+	text, ok, err := p.store.Take(ctx, key)
+	if err != nil {
+		slog.Warn("narration pool take failed", "key", key, "error", err)
+	}
+	if ok {
+		p.remember(key, text)
+		obs.NarrationPoolTotal.WithLabelValues(reqType, "pool").Inc()
+		return text, true
+	}
+
+	p.mu.Lock()
+	recent := p.cache[key]
+	if len(recent) > 0 {
+		text = recent[rand.Intn(len(recent))]
+	}
+	p.mu.Unlock()
+	if text != "" {
+		obs.NarrationPoolTotal.WithLabelValues(reqType, "cache").Inc()
+		return text, true
+	}
+	obs.NarrationPoolTotal.WithLabelValues(reqType, "miss").Inc()
+	return "", false
+}
+
+// remember adds a served line to the key's in-memory cache.
+func (p *Pool) remember(key, text string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.cache[key]; !ok && len(p.cache) >= poolCacheKeys {
+		for old := range p.cache {
+			delete(p.cache, old)
+			break
+		}
+	}
+	recent := append(p.cache[key], text)
+	if len(recent) > poolCacheLines {
+		recent = recent[len(recent)-poolCacheLines:]
+	}
+	p.cache[key] = recent
+}
+
+// queue schedules a refill for the job's key unless one is already waiting.
+// It never blocks: if the queue is full the job is dropped and the next
+// request for the key queues it again.
+func (p *Pool) queue(key string, job PoolJob) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.queued[key] {
+		return
+	}
+	select {
+	case p.jobs <- queuedJob{key: key, job: job}:
+		p.queued[key] = true
+	default:
+	}
+}
+
+func (p *Pool) done(key string) {
+	p.mu.Lock()
+	delete(p.queued, key)
+	p.mu.Unlock()
+}
+
+// UsePool makes the client serve pooled prompt types from p. Call RunPool to
+// keep it filled.
+func (c *Client) UsePool(p *Pool) {
+	c.pool = p
+}
+
+// RunPool is the refill worker: it prunes lines older than a week, queues
+// warm (keys worth having before anyone asks), then tops up each queued key
+// to the pool size, one model call at a time with timeout each. After a
+// failed call it backs off so a dead model isn't hammered. Blocks until ctx
+// is done.
+func (c *Client) RunPool(ctx context.Context, timeout time.Duration, warm []PoolJob) {
+	p := c.pool
+	if p == nil {
+		return
+	}
+	if err := p.store.Prune(ctx, time.Now().Add(-poolMaxAge)); err != nil {
+		slog.Warn("narration pool prune failed", "error", err)
+	}
+	go func() {
+		for _, job := range warm {
+			p.queue(c.poolKey(job.Type, job.Data), job)
+		}
+	}()
+
+	backoff := time.Second
+	for {
+		var q queuedJob
+		select {
+		case <-ctx.Done():
+			return
+		case q = <-p.jobs:
+		}
+
+		failed := false
+		job, key := q.job, q.key
+		for {
+			n, err := p.store.Count(ctx, key)
+			if err != nil || n >= p.size {
+				break
+			}
+			genCtx, cancel := context.WithTimeout(ctx, timeout)
+			text, err := c.generate(genCtx, job.Type, job.Data)
+			cancel()
+			if err != nil { // includes replies that failed validation
+				failed = true
+				break
+			}
+			if err := p.store.Add(ctx, job.Type, key, text); err != nil {
+				slog.Warn("narration pool add failed", "key", key, "error", err)
+				break
+			}
+		}
+		p.done(key)
+
+		if !failed {
+			backoff = time.Second
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, time.Minute)
+	}
+}
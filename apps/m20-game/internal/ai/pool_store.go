@@ -0,0 +1,78 @@
+package ai
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// PoolStore keeps pre-generated narration lines in the game's SQLite
+// database, grouped by pool key (see poolKey). Lines are served oldest first
+// and each is served once.
+type PoolStore struct {
+	db *sql.DB
+	mu sync.Mutex // serialises Take so two requests never get the same line
+}
+
+// NewPoolStore creates the narration_pool table if needed.
+func NewPoolStore(db *sql.DB) (*PoolStore, error) {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS narration_pool (
+			id          INTEGER PRIMARY KEY AUTOINCREMENT,
+			type        TEXT NOT NULL,
+			key         TEXT NOT NULL,
+			text        TEXT NOT NULL,
+			created_at  DATETIME NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS idx_narration_pool_key ON narration_pool(key, id);
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("create narration_pool table: %w", err)
+	}
+	return &PoolStore{db: db}, nil
+}
+
+// Add stores a line under key.
+func (s *PoolStore) Add(ctx context.Context, reqType, key, text string) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO narration_pool (type, key, text, created_at) VALUES (?, ?, ?, ?)`,
+		reqType, key, text, time.Now().UTC())
+	return err
+}
+
+// Take removes and returns the oldest line under key.
+// Returns ("", false, nil) if there is none.
+func (s *PoolStore) Take(ctx context.Context, key string) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var id int64
+	var text string
+	err := s.db.QueryRowContext(ctx,
+		`SELECT id, text FROM narration_pool WHERE key = ? ORDER BY id LIMIT 1`, key).Scan(&id, &text)
+	if err == sql.ErrNoRows {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM narration_pool WHERE id = ?`, id); err != nil {
+		return "", false, err
+	}
+	return text, true, nil
+}
+
+// Prune deletes every line created before cutoff.
+func (s *PoolStore) Prune(ctx context.Context, cutoff time.Time) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM narration_pool WHERE created_at < ?`, cutoff.UTC())
+	return err
+}
+
+// Count returns how many lines are waiting under key.
+func (s *PoolStore) Count(ctx context.Context, key string) (int, error) {
+	var n int
+	err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM narration_pool WHERE key = ?`, key).Scan(&n)
+	return n, err
+}
@@ -0,0 +1,108 @@
+package ai
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// DefaultStyle is the narration style (config narration_style) used when
+// none is configured: the ZAMN campy B-movie tone.
+const DefaultStyle = "You are the narrator of a campy B-movie zombie apocalypse comedy — think Zombies Ate My Neighbors crossed with Army of Darkness. Be fun, slightly absurd, and dramatically over-the-top. The monsters are scary but also ridiculous. The survivors are heroic but also kind of ridiculous too."
+
+//go:embed prompts/*.tmpl
+var promptFS embed.FS
+
+// PromptNames lists every prompt template. Each is prompts/<name>.tmpl and
+// doubles as the request type in metrics.
+var PromptNames = []string{"riddle", "entrance", "dialogue", "combat_hit", "combat_miss", "victory"}
+
+// PromptData is what a prompt template can use. Not every prompt sets every
+// field; Crit means a crit success for combat_hit and a crit failure for
+// combat_miss. History is the character's recent journal, summarized (see
+// character.SummarizeJournal), or empty.
+type PromptData struct {
+	Style    string
+	Building string
+	Group    string
+	Monster  string
+	Class    string
+	Crit     bool
+	History  string
+}
+
+// Prompts holds the parsed prompt templates.
+type Prompts struct {
+	tmpl map[string]*template.Template
+}
+
+// LoadPrompts parses the embedded templates, then any <name>.tmpl in dir
+// (if dir is not empty) in their place. A file in dir that isn't a known
+// prompt is an error, as is a template that fails to render.
+func LoadPrompts(dir string) (*Prompts, error) {
+	p := &Prompts{tmpl: make(map[string]*template.Template, len(PromptNames))}
+	for _, name := range PromptNames {
+		data, err := promptFS.ReadFile("prompts/" + name + ".tmpl")
+		if err != nil {
+			return nil, err
+		}
+		if err := p.parse(name, string(data)); err != nil {
+			return nil, err
+		}
+	}
+
+	if dir != "" {
+		files, err := filepath.Glob(filepath.Join(dir, "*.tmpl"))
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range files {
+			name := strings.TrimSuffix(filepath.Base(path), ".tmpl")
+			if _, ok := p.tmpl[name]; !ok {
+				return nil, fmt.Errorf("prompt %s: unknown prompt (want one of %s)", path, strings.Join(PromptNames, ", "))
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("read prompt: %w", err)
+			}
+			if err := p.parse(name, string(data)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Render each once so a typo'd field fails at startup, not mid-fight.
+	for _, name := range PromptNames {
+		if _, err := p.Render(name, PromptData{}); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+func (p *Prompts) parse(name, text string) error {
+	t, err := template.New(name).Parse(text)
+	if err != nil {
+		return fmt.Errorf("parse prompt %s: %w", name, err)
+	}
+	p.tmpl[name] = t
+	return nil
+}
+
+// Render fills in a prompt. Leading and trailing whitespace is trimmed, so an
+// empty Style leaves no blank first line.
+func (p *Prompts) Render(name string, data PromptData) (string, error) {
+	t, ok := p.tmpl[name]
+	if !ok {
+		return "", fmt.Errorf("unknown prompt %q", name)
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("render prompt %s: %w", name, err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
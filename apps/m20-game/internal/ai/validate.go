@@ -0,0 +1,212 @@
+package ai
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Reasons a model reply is rejected, as the reason label on
+// m20_ai_output_rejected_total.
+const (
+	RejectEmpty   = "empty"   // nothing left after cleaning
+	RejectFormat  = "format"  // riddle isn't the JSON the prompt asked for, or fails its checks
+	RejectEcho    = "echo"    // the reply only repeated the prompt
+	RejectBlocked = "blocked" // contains a blocked word
+	RejectRefusal = "refusal" // the model broke character to refuse or explain itself
+)
+
+// OutputError is a model reply that failed validation. Client serves the
+// fallback line instead.
+type OutputError struct {
+	Reason string // one of the Reject* constants
+}
+
+func (e *OutputError) Error() string {
+	return "ai: output rejected: " + e.Reason
+}
+
+// blockedWords are never shown to players, whole-word and case-insensitive.
+// The game is campy, not crude; extend the list rather than the prompts.
+var blockedWords = []string{
+	"fuck", "fucking", "fucked", "shit", "cunt", "bitch", "whore", "slut",
+	"rape", "raped", "rapist", "porn", "nazi", "retard", "retarded",
+}
+
+// refusalPhrases mark a reply where the model stepped out of the story.
+var refusalPhrases = []string{
+	"as an ai", "as a language model", "i cannot", "i can't help", "i'm sorry, but",
+	"i am sorry, but", "i'm not able to", "i won't be able to",
+}
+
+var (
+	blockedRE = regexp.MustCompile(`(?i)\b(` + strings.Join(blockedWords, "|") + `)\b`)
+
+	// stageDirectionRE matches *growls*, (laughs), and [Narrator voice].
+	stageDirectionRE = regexp.MustCompile(`\*[^*\n]{1,80}\*|\([^()\n]{1,80}\)|\[[^\[\]\n]{1,80}\]`)
+
+	// speakerRE matches a "Narrator:" or "Zombie:" label starting a line.
+	speakerRE = regexp.MustCompile(`^(?:[Nn]arrator|[A-Z][A-Za-z' -]{0,30}):\s+`)
+
+	// preambleRE matches a chatty lead-in line such as "Sure! Here's the line:".
+	preambleRE = regexp.MustCompile(`(?i)^(sure|okay|ok|certainly|of course|here('s| is| are))\b.*:$`)
+)
+
+// validateOutput checks and cleans a model reply to prompt. Riddles must
+// parse (see parseRiddle) and come back unchanged; narration lines go through
+// cleanLine. A bad reply is an *OutputError.
+func validateOutput(reqType, prompt, text string) (string, error) {
+	if reqType == "riddle" {
+		_, _, err := parseRiddle(text)
+		return text, err
+	}
+	return cleanLine(text, prompt, lineLimit(reqType))
+}
+
+// cleanLine makes a narration reply presentable: it drops preambles, lines
+// that echo the prompt, stage directions, speaker labels, and wrapping
+// quotes, joins what is left into one line, and cuts it to limit bytes on a
+// sentence or word boundary. Then it applies the content filter.
+func cleanLine(text, prompt string, limit int) (string, error) {
+	promptLines := make(map[string]bool)
+	for _, l := range strings.Split(prompt, "\n") {
+		if l = normalize(l); len(l) >= 20 {
+			promptLines[l] = true
+		}
+	}
+
+	var kept []string
+	echoed := false
+	for _, l := range strings.Split(text, "\n") {
+		l = strings.TrimSpace(l)
+		if l == "" || (len(kept) == 0 && !echoed && preambleRE.MatchString(l)) {
+			continue
+		}
+		if promptLines[normalize(l)] {
+			echoed = true
+			continue
+		}
+		l = stageDirectionRE.ReplaceAllString(l, "")
+		l = speakerRE.ReplaceAllString(strings.TrimSpace(l), "")
+		if l = strings.TrimSpace(l); l != "" {
+			kept = append(kept, l)
+		}
+	}
+	line := unquote(strings.Join(strings.Fields(strings.Join(kept, " ")), " "))
+	switch {
+	case line == "" && echoed:
+		return "", &OutputError{Reason: RejectEcho}
+	case line == "":
+		return "", &OutputError{Reason: RejectEmpty}
+	}
+	if err := checkContent(line); err != nil {
+		return "", err
+	}
+	return clip(line, limit), nil
+}
+
+// checkContent applies the content filter: blocked words and refusals.
+func checkContent(s string) error {
+	if blockedRE.MatchString(s) {
+		return &OutputError{Reason: RejectBlocked}
+	}
+	lower := strings.ToLower(s)
+	for _, p := range refusalPhrases {
+		if strings.Contains(lower, p) {
+			return &OutputError{Reason: RejectRefusal}
+		}
+	}
+	return nil
+}
+
+// riddleReply is the JSON the riddle prompt asks for.
+type riddleReply struct {
+	Riddle string `json:"riddle"`
+	Answer string `json:"answer"`
+}
+
+// parseRiddle reads a riddle reply: JSON {"riddle", "answer"}, or the older
+// "RIDDLE: ... ANSWER: ..." text for prompt_dir templates that still ask for
+// it. The riddle must be at most 300 characters and pass the content filter;
+// the answer must be one word that the riddle doesn't give away.
+func parseRiddle(s string) (riddle, answer string, err error) {
+	var reply riddleReply
+	if start, end := strings.Index(s, "{"), strings.LastIndex(s, "}"); start >= 0 && end > start &&
+		json.Unmarshal([]byte(s[start:end+1]), &reply) == nil {
+		riddle, answer = reply.Riddle, reply.Answer
+	} else if r, a, ok := strings.Cut(s, "ANSWER:"); ok && strings.Contains(r, "RIDDLE:") {
+		_, riddle, _ = strings.Cut(r, "RIDDLE:")
+		answer = a
+	}
+	riddle = strings.Join(strings.Fields(stageDirectionRE.ReplaceAllString(riddle, "")), " ")
+	answer = strings.TrimFunc(answer, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+
+	if riddle == "" || answer == "" || utf8.RuneCountInString(riddle) > 300 || utf8.RuneCountInString(answer) > 20 ||
+		strings.ContainsFunc(answer, unicode.IsSpace) {
+		return "", "", &OutputError{Reason: RejectFormat}
+	}
+	if regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(answer) + `\b`).MatchString(riddle) {
+		return "", "", &OutputError{Reason: RejectFormat}
+	}
+	if err := checkContent(riddle + " " + answer); err != nil {
+		return "", "", err
+	}
+	return riddle, answer, nil
+}
+
+// normalize lowercases s and drops everything but letters and digits, for
+// comparing a reply line with the prompt.
+func normalize(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, s)
+}
+
+// unquote strips one pair of quotes wrapping the whole of s.
+func unquote(s string) string {
+	for _, q := range [][2]string{{`"`, `"`}, {"“", "”"}, {"'", "'"}} {
+		if len(s) > len(q[0])+len(q[1]) && strings.HasPrefix(s, q[0]) && strings.HasSuffix(s, q[1]) {
+			inner := s[len(q[0]) : len(s)-len(q[1])]
+			if !strings.Contains(inner, q[0]) {
+				return strings.TrimSpace(inner)
+			}
+		}
+	}
+	return s
+}
+
+// clip cuts s to at most n bytes. It ends on the last full sentence if that
+// keeps at least half, otherwise on the last word, and never splits a rune.
+func clip(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	cut := truncate(s, n)
+	if i := strings.LastIndexAny(cut, ".!?"); i >= n/2 {
+		return cut[:i+1]
+	}
+	cut = truncate(s, n-len("…"))
+	if i := strings.LastIndexByte(cut, ' '); i > 0 {
+		return strings.TrimRight(cut[:i], ",;:—- ") + "…"
+	}
+	return cut
+}
+
+// truncate caps s at n bytes without splitting a UTF-8 rune.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	if n <= 0 {
+		return ""
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
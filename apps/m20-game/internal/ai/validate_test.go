@@ -0,0 +1,113 @@
+package ai
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func rejectReason(err error) string {
+	var out *OutputError
+	if errors.As(err, &out) {
+		return out.Reason
+	}
+	return ""
+}
+
+func TestCleanLine(t *testing.T) {
+	prompt := "You are the narrator of a campy B-movie.\nDescribe a Zombie being hit by a Brawler."
+	cases := []struct {
+		name   string
+		text   string
+		want   string
+		reason string // Reject* if the line is refused
+	}{
+		{"plain line", "The zombie staggers back.", "The zombie staggers back.", ""},
+		{"preamble dropped", "Sure! Here's the line:\nThe zombie staggers back.", "The zombie staggers back.", ""},
+		{"stage directions and labels", "Narrator: *thunder* The zombie (groaning) staggers back.", "The zombie staggers back.", ""},
+		{"wrapping quotes", `"The zombie staggers back."`, "The zombie staggers back.", ""},
+		{"lines joined", "The zombie staggers.\n\n  It falls over.  ", "The zombie staggers. It falls over.", ""},
+		{"prompt echoed back", "Describe a Zombie being hit by a Brawler.\nThe zombie staggers back.", "The zombie staggers back.", ""},
+		{"only the echo", "Describe a Zombie being hit by a Brawler.", "", RejectEcho},
+		{"nothing left", "*growls*\n(silence)", "", RejectEmpty},
+		{"blocked word", "The zombie is a total Nazi about it.", "", RejectBlocked},
+		{"refusal", "I'm sorry, but I cannot describe violence.", "", RejectRefusal},
+	}
+	for _, tc := range cases {
+		got, err := cleanLine(tc.text, prompt, 200)
+		if reason := rejectReason(err); reason != tc.reason {
+			t.Errorf("%s: rejected for %q (%v), want %q", tc.name, reason, err, tc.reason)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("%s: cleanLine = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestClip(t *testing.T) {
+	cases := []struct {
+		name string
+		s    string
+		n    int
+		want string
+	}{
+		{"fits", "Short line.", 20, "Short line."},
+		{"ends on a sentence", "First sentence here. Second one runs on and on.", 30, "First sentence here."},
+		{"ends on a word", "One long sentence that keeps going without a stop", 21, "One long sentence…"},
+		{"no spaces", "Aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", 10, "Aaaaaaa"},
+	}
+	for _, tc := range cases {
+		got := clip(tc.s, tc.n)
+		if got != tc.want {
+			t.Errorf("%s: clip(%q, %d) = %q, want %q", tc.name, tc.s, tc.n, got, tc.want)
+		}
+		if len(got) > tc.n {
+			t.Errorf("%s: clip returned %d bytes, limit %d", tc.name, len(got), tc.n)
+		}
+	}
+
+	// Never split a multi-byte rune.
+	s := strings.Repeat("é", 40)
+	for n := 1; n < len(s); n++ {
+		if got := clip(s, n); !utf8.ValidString(got) {
+			t.Fatalf("clip(%d) split a rune: %q", n, got)
+		}
+	}
+}
+
+func TestParseRiddle(t *testing.T) {
+	cases := []struct {
+		name         string
+		reply        string
+		riddle       string
+		answer       string
+		wantRejected bool
+	}{
+		{"json", `{"riddle": "The more you take, the more you leave behind.", "answer": "footsteps"}`,
+			"The more you take, the more you leave behind.", "footsteps", false},
+		{"json wrapped in chatter", "Here you go:\n```json\n{\"riddle\": \"I have keys but open no locks.\", \"answer\": \"Piano.\"}\n```",
+			"I have keys but open no locks.", "Piano", false},
+		{"legacy text", "RIDDLE: What has hands but cannot clap?\nANSWER: clock",
+			"What has hands but cannot clap?", "clock", false},
+		{"stage directions stripped", `{"riddle": "*purrs* What gets wetter as it dries?", "answer": "towel"}`,
+			"What gets wetter as it dries?", "towel", false},
+		{"answer in the riddle", `{"riddle": "A clock has hands. What am I?", "answer": "clock"}`, "", "", true},
+		{"multi-word answer", `{"riddle": "What runs but never walks?", "answer": "a river"}`, "", "", true},
+		{"no answer", `{"riddle": "What runs but never walks?"}`, "", "", true},
+		{"not a riddle", "The Sphinx stares at you.", "", "", true},
+		{"too long", `{"riddle": "` + strings.Repeat("word ", 70) + `", "answer": "echo"}`, "", "", true},
+		{"blocked", `{"riddle": "What does a nazi fear most?", "answer": "justice"}`, "", "", true},
+	}
+	for _, tc := range cases {
+		riddle, answer, err := parseRiddle(tc.reply)
+		if (err != nil) != tc.wantRejected {
+			t.Errorf("%s: err = %v, want rejected=%v", tc.name, err, tc.wantRejected)
+			continue
+		}
+		if riddle != tc.riddle || answer != tc.answer {
+			t.Errorf("%s: got (%q, %q), want (%q, %q)", tc.name, riddle, answer, tc.riddle, tc.answer)
+		}
+	}
+}
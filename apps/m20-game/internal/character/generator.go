@@ -74,6 +74,9 @@ func Generate(req GenerateRequest) (*Character, error) {
 		Stats:     stats,
 		Inventory: []string{},
 		Equipment: Equipment{},
+		Effects:   []Effect{},
+		Vehicles:  []OwnedVehicle{},
+		Statuses:  []Status{},
 		Location:  "tile-01",
 		CreatedAt: now,
 		UpdatedAt: now,
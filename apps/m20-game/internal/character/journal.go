@@ -0,0 +1,82 @@
+package character
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Journal entry kinds.
+const (
+	JournalBuilding  = "building"   // entered a building; Subject is the building
+	JournalKill      = "kill"       // got the kill; Subject is the monster
+	JournalNearDeath = "near_death" // dropped to a quarter HP or less; Subject is who did it
+	JournalVictory   = "victory"    // the party cleared a group; Subject is the group
+	JournalDefeat    = "defeat"     // the party was beaten; Subject is the group
+)
+
+// JournalEntry is one thing that happened to a character, as recorded in
+// their campaign journal.
+type JournalEntry struct {
+	ID          int64     `json:"id"`
+	CharacterID string    `json:"character_id"`
+	Kind        string    `json:"kind"`
+	Subject     string    `json:"subject"`
+	EncounterID string    `json:"encounter_id,omitempty"`
+	Text        string    `json:"text"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Describe is the entry as a journal line, e.g. "Killed a Zombie".
+func (e JournalEntry) Describe() string {
+	return journalPhrase(e.Kind, e.Subject, 1, true)
+}
+
+// SummarizeJournal condenses entries (oldest first) into one line for a
+// narration prompt, merging repeats that follow each other:
+// "entered a Hospital, killed Zombie ×3, nearly died to a Ghoul."
+// It returns "" for no entries.
+func SummarizeJournal(entries []JournalEntry) string {
+	var parts []string
+	for i := 0; i < len(entries); {
+		n := 1
+		for i+n < len(entries) && entries[i+n].Kind == entries[i].Kind && entries[i+n].Subject == entries[i].Subject {
+			n++
+		}
+		parts = append(parts, journalPhrase(entries[i].Kind, entries[i].Subject, n, false))
+		i += n
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return strings.Join(parts, ", ") + "."
+}
+
+// journalPhrase words kind+subject happening n times in a row.
+func journalPhrase(kind, subject string, n int, capital bool) string {
+	var s string
+	switch kind {
+	case JournalBuilding:
+		s = "entered a " + subject
+	case JournalKill:
+		s = "killed a " + subject
+		if n > 1 {
+			s = fmt.Sprintf("killed %s ×%d", subject, n)
+		}
+	case JournalNearDeath:
+		s = "nearly died to a " + subject
+	case JournalVictory:
+		s = "cleared " + subject
+	case JournalDefeat:
+		s = "was driven off by " + subject
+	default:
+		s = kind + " " + subject
+	}
+	if n > 1 && kind != JournalKill {
+		s += fmt.Sprintf(" (×%d)", n)
+	}
+	if capital {
+		s = strings.ToUpper(s[:1]) + s[1:]
+	}
+	return s
+}
@@ -0,0 +1,89 @@
+package character
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// JournalStore keeps each character's campaign journal in the character
+// database. Entries are append-only.
+type JournalStore struct {
+	db *sql.DB
+}
+
+// NewJournalStore creates the journal table if needed.
+func NewJournalStore(db *sql.DB) (*JournalStore, error) {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS journal (
+			id            INTEGER PRIMARY KEY AUTOINCREMENT,
+			character_id  TEXT NOT NULL,
+			kind          TEXT NOT NULL,
+			subject       TEXT NOT NULL,
+			encounter_id  TEXT NOT NULL DEFAULT '',
+			text          TEXT NOT NULL,
+			created_at    DATETIME NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS idx_journal_character ON journal(character_id, id);
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("create journal table: %w", err)
+	}
+	return &JournalStore{db: db}, nil
+}
+
+// Add appends entries in order. Text defaults to Describe().
+func (s *JournalStore) Add(ctx context.Context, entries ...JournalEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	now := time.Now().UTC()
+	for _, e := range entries {
+		if e.Text == "" {
+			e.Text = e.Describe()
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO journal (character_id, kind, subject, encounter_id, text, created_at)
+			VALUES (?, ?, ?, ?, ?, ?)
+		`, e.CharacterID, e.Kind, e.Subject, e.EncounterID, e.Text, now); err != nil {
+			return fmt.Errorf("insert journal entry: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// Recent returns characterID's last limit entries, oldest first.
+func (s *JournalStore) Recent(ctx context.Context, characterID string, limit int) ([]JournalEntry, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, character_id, kind, subject, encounter_id, text, created_at
+		FROM journal WHERE character_id = ? ORDER BY id DESC LIMIT ?
+	`, characterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []JournalEntry{}
+	for rows.Next() {
+		var e JournalEntry
+		var createdAt string
+		if err := rows.Scan(&e.ID, &e.CharacterID, &e.Kind, &e.Subject, &e.EncounterID, &e.Text, &createdAt); err != nil {
+			return nil, fmt.Errorf("scan journal entry: %w", err)
+		}
+		e.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+	return entries, nil
+}
@@ -31,11 +31,16 @@ type Character struct {
 	HP        int            `json:"hp"`
 	MaxHP     int            `json:"max_hp"`
 	Stats     CharacterStats `json:"stats"`
-	Inventory []string       `json:"inventory"`  // item names
-	Equipment Equipment      `json:"equipment"`   // equipped items (3 slots)
-	Location  string         `json:"location"`   // current tile ID
+	Inventory []string       `json:"inventory"` // item names
+	Equipment Equipment      `json:"equipment"` // equipped items (3 slots)
+	Location  string         `json:"location"`  // current tile ID
+	Effects   []Effect       `json:"effects"`   // temporary stat modifiers; see EffectiveStats
+	Vehicles  []OwnedVehicle `json:"vehicles"`  // claimed vehicles (see vehicle.go)
+	Statuses  []Status       `json:"statuses"`  // bleeding, poisoned, ...; see status.go
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
+
+	Version int `json:"-"` // row version Save checks against; 0 = not stored yet
 }
 
 // XPForNextLevel returns the XP threshold for levelling up.
@@ -87,3 +92,49 @@ func (c *Character) ContainsItem(itemName string) bool {
 	}
 	return false
 }
+
+// CountItem returns how many of itemName the inventory holds.
+func (c *Character) CountItem(itemName string) int {
+	n := 0
+	for _, name := range c.Inventory {
+		if name == itemName {
+			n++
+		}
+	}
+	return n
+}
+
+// AddItems appends items until the inventory is full.
+// Returns the items that fit and those left behind.
+func (c *Character) AddItems(items []string) (added, leftBehind []string) {
+	for _, item := range items {
+		if len(c.Inventory) >= c.MaxInventorySlots() {
+			leftBehind = append(leftBehind, item)
+			continue
+		}
+		c.Inventory = append(c.Inventory, item)
+		added = append(added, item)
+	}
+	return added, leftBehind
+}
+
+// Unequip clears itemName from every equipment slot it occupies.
+func (c *Character) Unequip(itemName string) {
+	if c.Equipment.Weapon == itemName {
+		c.Equipment.Weapon = ""
+	}
+	if c.Equipment.Armor == itemName {
+		c.Equipment.Armor = ""
+	}
+	if c.Equipment.Accessory == itemName {
+		c.Equipment.Accessory = ""
+	}
+}
+
+// Heal restores up to amount HP without exceeding MaxHP.
+// Returns the HP actually restored.
+func (c *Character) Heal(amount int) int {
+	before := c.HP
+	c.HP = min(c.HP+amount, c.MaxHP)
+	return c.HP - before
+}
@@ -0,0 +1,103 @@
+package character
+
+import (
+	"errors"
+	"slices"
+	"time"
+)
+
+// MaxPartySize matches the four party slots in the UI.
+const MaxPartySize = 4
+
+// MaxStashSlots is how many items a party's shared stash holds.
+const MaxStashSlots = 30
+
+var (
+	ErrPartyFull      = errors.New("party is full")
+	ErrAlreadyInParty = errors.New("character is already in a party")
+	ErrNotMember      = errors.New("character is not in this party")
+	ErrStashFull      = errors.New("party stash is full")
+	ErrNotInStash     = errors.New("item is not in the stash")
+)
+
+// Party is a persistent group of characters that moves and fights together.
+// A character belongs to at most one party.
+type Party struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	LeaderID  string    `json:"leader_id"`
+	MemberIDs []string  `json:"member_ids"` // leader included, in join order
+	Stash     []string  `json:"stash"`      // shared items any member can take
+	WorldID   string    `json:"world_id,omitempty"`
+	Location  string    `json:"location"` // tile the party last moved to in WorldID
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// HasMember reports whether characterID is in the party.
+func (p *Party) HasMember(characterID string) bool {
+	return slices.Contains(p.MemberIDs, characterID)
+}
+
+// AddMember appends characterID to the party.
+func (p *Party) AddMember(characterID string) error {
+	if p.HasMember(characterID) {
+		return ErrAlreadyInParty
+	}
+	if len(p.MemberIDs) >= MaxPartySize {
+		return ErrPartyFull
+	}
+	p.MemberIDs = append(p.MemberIDs, characterID)
+	if p.LeaderID == "" {
+		p.LeaderID = characterID
+	}
+	return nil
+}
+
+// RemoveMember takes characterID out of the party. If the leader leaves,
+// leadership passes to the longest-standing member left.
+func (p *Party) RemoveMember(characterID string) error {
+	i := slices.Index(p.MemberIDs, characterID)
+	if i < 0 {
+		return ErrNotMember
+	}
+	p.MemberIDs = slices.Delete(p.MemberIDs, i, i+1)
+	if p.LeaderID == characterID {
+		p.LeaderID = ""
+		if len(p.MemberIDs) > 0 {
+			p.LeaderID = p.MemberIDs[0]
+		}
+	}
+	return nil
+}
+
+// Deposit moves one item into the stash.
+func (p *Party) Deposit(item string) error {
+	if len(p.Stash) >= MaxStashSlots {
+		return ErrStashFull
+	}
+	p.Stash = append(p.Stash, item)
+	return nil
+}
+
+// Withdraw takes one item out of the stash.
+func (p *Party) Withdraw(item string) error {
+	i := slices.Index(p.Stash, item)
+	if i < 0 {
+		return ErrNotInStash
+	}
+	p.Stash = slices.Delete(p.Stash, i, i+1)
+	return nil
+}
+
+// AddToStash stores what fits and returns the rest, like Character.AddItems.
+func (p *Party) AddToStash(items []string) (added, leftBehind []string) {
+	for _, item := range items {
+		if p.Deposit(item) != nil {
+			leftBehind = append(leftBehind, item)
+			continue
+		}
+		added = append(added, item)
+	}
+	return added, leftBehind
+}
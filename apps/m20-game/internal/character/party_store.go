@@ -0,0 +1,163 @@
+package character
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PartyStore persists parties next to the characters table. Membership lives
+// in its own table keyed by character, so one character cannot be in two
+// parties.
+type PartyStore struct {
+	db *sql.DB
+	mu sync.Mutex // serialises Update so two requests cannot race on one party
+}
+
+// NewPartyStore creates the parties and party_members tables if needed.
+func NewPartyStore(db *sql.DB) (*PartyStore, error) {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS parties (
+			id          TEXT PRIMARY KEY,
+			name        TEXT NOT NULL,
+			leader_id   TEXT NOT NULL,
+			stash_json  TEXT NOT NULL DEFAULT '[]',
+			world_id    TEXT NOT NULL DEFAULT '',
+			location    TEXT NOT NULL DEFAULT '',
+			created_at  DATETIME NOT NULL,
+			updated_at  DATETIME NOT NULL
+		);
+		CREATE TABLE IF NOT EXISTS party_members (
+			character_id TEXT PRIMARY KEY,
+			party_id     TEXT NOT NULL,
+			slot         INTEGER NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS party_members_party ON party_members (party_id);
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("create party tables: %w", err)
+	}
+	return &PartyStore{db: db}, nil
+}
+
+// Save creates or updates a party and replaces its member list. A member
+// already in another party fails with ErrAlreadyInParty.
+func (s *PartyStore) Save(ctx context.Context, p *Party) error {
+	if p.Stash == nil {
+		p.Stash = []string{}
+	}
+	stash, err := json.Marshal(p.Stash)
+	if err != nil {
+		return fmt.Errorf("marshal stash: %w", err)
+	}
+	p.UpdatedAt = time.Now().UTC()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO parties (id, name, leader_id, stash_json, world_id, location, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			name=excluded.name, leader_id=excluded.leader_id, stash_json=excluded.stash_json,
+			world_id=excluded.world_id, location=excluded.location, updated_at=excluded.updated_at
+	`, p.ID, p.Name, p.LeaderID, string(stash), p.WorldID, p.Location, p.CreatedAt, p.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("save party: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM party_members WHERE party_id = ?`, p.ID); err != nil {
+		return fmt.Errorf("clear party members: %w", err)
+	}
+	for slot, id := range p.MemberIDs {
+		_, err := tx.ExecContext(ctx, `INSERT INTO party_members (character_id, party_id, slot) VALUES (?, ?, ?)`, id, p.ID, slot)
+		if err != nil {
+			if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+				return fmt.Errorf("%w: %s", ErrAlreadyInParty, id)
+			}
+			return fmt.Errorf("save party member: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// Load retrieves a party by ID.
+// Returns (nil, nil) if not found.
+func (s *PartyStore) Load(ctx context.Context, id string) (*Party, error) {
+	p := Party{ID: id, MemberIDs: []string{}}
+	var stash, createdAt, updatedAt string
+	err := s.db.QueryRowContext(ctx, `
+		SELECT name, leader_id, stash_json, world_id, location, created_at, updated_at
+		FROM parties WHERE id = ?
+	`, id).Scan(&p.Name, &p.LeaderID, &stash, &p.WorldID, &p.Location, &createdAt, &updatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scan party: %w", err)
+	}
+	if err := json.Unmarshal([]byte(stash), &p.Stash); err != nil || p.Stash == nil {
+		p.Stash = []string{}
+	}
+	p.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	p.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
+
+	rows, err := s.db.QueryContext(ctx, `SELECT character_id FROM party_members WHERE party_id = ? ORDER BY slot`, id)
+	if err != nil {
+		return nil, fmt.Errorf("query party members: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var cid string
+		if err := rows.Scan(&cid); err != nil {
+			return nil, fmt.Errorf("scan party member: %w", err)
+		}
+		p.MemberIDs = append(p.MemberIDs, cid)
+	}
+	return &p, rows.Err()
+}
+
+// Update loads a party, applies fn, and saves it if fn succeeds.
+// Returns (nil, nil) if the party does not exist.
+func (s *PartyStore) Update(ctx context.Context, id string, fn func(*Party) error) (*Party, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, err := s.Load(ctx, id)
+	if err != nil || p == nil {
+		return nil, err
+	}
+	if err := fn(p); err != nil {
+		return nil, err
+	}
+	if err := s.Save(ctx, p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Delete removes a party and frees its members.
+func (s *PartyStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM party_members WHERE party_id = ?`, id); err != nil {
+		return err
+	}
+	_, err := s.db.ExecContext(ctx, `DELETE FROM parties WHERE id = ?`, id)
+	return err
+}
+
+// PartyOf returns the ID of the party characterID belongs to, or "".
+func (s *PartyStore) PartyOf(ctx context.Context, characterID string) (string, error) {
+	var id string
+	err := s.db.QueryRowContext(ctx, `SELECT party_id FROM party_members WHERE character_id = ?`, characterID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return id, err
+}
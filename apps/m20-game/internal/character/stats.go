@@ -0,0 +1,118 @@
+package character
+
+import (
+	"sort"
+	"time"
+
+	"m20-game/internal/resources"
+)
+
+// Effect is a temporary stat modifier (potion, injury, buff) that lapses at ExpiresAt.
+type Effect struct {
+	Name      string         `json:"name"`
+	Stats     map[string]int `json:"stats"` // keyed like EquipBonuses: "strength", "scouting", ...
+	ExpiresAt time.Time      `json:"expires_at"`
+}
+
+// Active reports whether the effect still applies at now.
+func (e Effect) Active(now time.Time) bool {
+	return now.Before(e.ExpiresAt)
+}
+
+// StatBreakdown shows where each point of an effective stat comes from.
+type StatBreakdown struct {
+	Base      CharacterStats `json:"base"`
+	Equipment CharacterStats `json:"equipment"`
+	Level     CharacterStats `json:"level"`
+	Effects   CharacterStats `json:"effects"`
+	Effective CharacterStats `json:"effective"`
+}
+
+// levelBonus is the flat bonus to every stat: +1 per two levels past first.
+func levelBonus(level int) int {
+	if level < 2 {
+		return 0
+	}
+	return (level - 1) / 2
+}
+
+// EffectiveStats returns base + equipment + level + active effects.
+// Game rules should read these rather than Stats.
+func (c *Character) EffectiveStats() CharacterStats {
+	return c.StatBreakdown(time.Now()).Effective
+}
+
+// StatBreakdown computes every stat contribution at now.
+func (c *Character) StatBreakdown(now time.Time) StatBreakdown {
+	b := StatBreakdown{Base: c.Stats}
+	for _, item := range []string{c.Equipment.Weapon, c.Equipment.Armor, c.Equipment.Accessory} {
+		if item == "" {
+			continue
+		}
+		b.Equipment.add(resources.EquipBonuses()[item])
+	}
+	lvl := levelBonus(c.Level)
+	b.Level = CharacterStats{lvl, lvl, lvl, lvl, lvl, lvl, lvl}
+	for _, e := range c.Effects {
+		if e.Active(now) {
+			b.Effects.add(e.Stats)
+		}
+	}
+	b.Effective = b.Base
+	for _, part := range []CharacterStats{b.Equipment, b.Level, b.Effects} {
+		b.Effective.add(part.Map())
+	}
+	return b
+}
+
+// PruneEffects drops effects that have expired by now. Returns true if any were removed.
+func (c *Character) PruneEffects(now time.Time) bool {
+	kept := c.Effects[:0]
+	for _, e := range c.Effects {
+		if e.Active(now) {
+			kept = append(kept, e)
+		}
+	}
+	removed := len(kept) != len(c.Effects)
+	c.Effects = kept
+	return removed
+}
+
+// Get returns a stat by its JSON name, or 0 if unknown.
+func (s CharacterStats) Get(name string) int {
+	return s.Map()[name]
+}
+
+// Map returns the stats keyed by JSON name.
+func (s CharacterStats) Map() map[string]int {
+	return map[string]int{
+		"strength":     s.Strength,
+		"stamina":      s.Stamina,
+		"marksmanship": s.Marksmanship,
+		"scouting":     s.Scouting,
+		"scavenging":   s.Scavenging,
+		"crafting":     s.Crafting,
+		"salvaging":    s.Salvaging,
+	}
+}
+
+// StatNames returns every stat's JSON name, sorted. Content packs key class
+// and equipment bonuses by these.
+func StatNames() []string {
+	names := make([]string, 0, 7)
+	for name := range (CharacterStats{}).Map() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (s *CharacterStats) add(bonus map[string]int) {
+	s.Strength += bonus["strength"]
+	s.Stamina += bonus["stamina"]
+	s.Marksmanship += bonus["marksmanship"]
+	s.Scouting += bonus["scouting"]
+	s.Scavenging += bonus["scavenging"]
+	s.Crafting += bonus["crafting"]
+	s.Salvaging += bonus["salvaging"]
+}
@@ -0,0 +1,43 @@
+package character
+
+// Status is a status condition (resources.StatusConditions) with the
+// encounter rounds it has left. Statuses only tick inside encounters; between
+// fights they wait, so a bleeding character stays bleeding until bandaged.
+type Status struct {
+	Name   string `json:"name"`
+	Rounds int    `json:"rounds"`
+	Source string `json:"source,omitempty"` // combatant ID that inflicted it, for kill credit
+}
+
+// AddStatus applies a status. An existing status of the same name is
+// refreshed to the longer duration rather than stacked.
+func AddStatus(list []Status, s Status) []Status {
+	for i := range list {
+		if list[i].Name == s.Name {
+			list[i].Rounds = max(list[i].Rounds, s.Rounds)
+			list[i].Source = s.Source
+			return list
+		}
+	}
+	return append(list, s)
+}
+
+// RemoveStatuses drops every status named in names and returns what was
+// removed.
+func RemoveStatuses(list []Status, names []string) (kept []Status, removed []string) {
+	kept = list[:0]
+	for _, s := range list {
+		cured := false
+		for _, n := range names {
+			if s.Name == n {
+				cured = true
+			}
+		}
+		if cured {
+			removed = append(removed, s.Name)
+		} else {
+			kept = append(kept, s)
+		}
+	}
+	return kept, removed
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -11,6 +12,13 @@ import (
 	_ "modernc.org/sqlite" // pure-Go SQLite driver, no CGO required
 )
 
+// ErrStale is returned by Save when the character was saved by someone else
+// since it was loaded; reload it and apply the change again.
+var ErrStale = errors.New("character was changed by another request")
+
+// updateAttempts is how often Update reloads after a stale save.
+const updateAttempts = 5
+
 // Store persists characters to SQLite.
 // The interface is designed for easy swap to PostgreSQL in production.
 type Store struct {
@@ -66,10 +74,36 @@ func migrate(db *sql.DB) error {
 		return fmt.Errorf("add equip_json column: %w", addErr)
 	}
 
+	// Additive migration: temporary effects for effective stats.
+	_, addErr = db.Exec(`ALTER TABLE characters ADD COLUMN effects_json TEXT NOT NULL DEFAULT '[]'`)
+	if addErr != nil && !strings.Contains(addErr.Error(), "duplicate column name") {
+		return fmt.Errorf("add effects_json column: %w", addErr)
+	}
+
+	// Additive migration: owned vehicles.
+	_, addErr = db.Exec(`ALTER TABLE characters ADD COLUMN vehicles_json TEXT NOT NULL DEFAULT '[]'`)
+	if addErr != nil && !strings.Contains(addErr.Error(), "duplicate column name") {
+		return fmt.Errorf("add vehicles_json column: %w", addErr)
+	}
+
+	// Additive migration: status conditions (bleeding, poisoned, ...).
+	_, addErr = db.Exec(`ALTER TABLE characters ADD COLUMN statuses_json TEXT NOT NULL DEFAULT '[]'`)
+	if addErr != nil && !strings.Contains(addErr.Error(), "duplicate column name") {
+		return fmt.Errorf("add statuses_json column: %w", addErr)
+	}
+
+	// Additive migration: row version for Save's lost-update check.
+	_, addErr = db.Exec(`ALTER TABLE characters ADD COLUMN version INTEGER NOT NULL DEFAULT 1`)
+	if addErr != nil && !strings.Contains(addErr.Error(), "duplicate column name") {
+		return fmt.Errorf("add version column: %w", addErr)
+	}
+
 	return nil
 }
 
-// Save creates or updates a character (upsert).
+// Save creates a character (Version 0) or updates it. An update only applies
+// if the stored row still has c.Version, so a request working from an old
+// copy cannot overwrite another's changes; it gets ErrStale instead.
 func (s *Store) Save(ctx context.Context, c *Character) error {
 	statsJSON, err := json.Marshal(c.Stats)
 	if err != nil {
@@ -83,42 +117,111 @@ func (s *Store) Save(ctx context.Context, c *Character) error {
 	if err != nil {
 		return fmt.Errorf("marshal equipment: %w", err)
 	}
+	c.PruneEffects(time.Now())
+	if c.Effects == nil {
+		c.Effects = []Effect{}
+	}
+	effectsJSON, err := json.Marshal(c.Effects)
+	if err != nil {
+		return fmt.Errorf("marshal effects: %w", err)
+	}
+	if c.Vehicles == nil {
+		c.Vehicles = []OwnedVehicle{}
+	}
+	vehiclesJSON, err := json.Marshal(c.Vehicles)
+	if err != nil {
+		return fmt.Errorf("marshal vehicles: %w", err)
+	}
+	if c.Statuses == nil {
+		c.Statuses = []Status{}
+	}
+	statusesJSON, err := json.Marshal(c.Statuses)
+	if err != nil {
+		return fmt.Errorf("marshal statuses: %w", err)
+	}
 
 	c.UpdatedAt = time.Now().UTC()
 
-	_, err = s.db.ExecContext(ctx, `
-		INSERT INTO characters
-			(id, name, class, level, xp, hp, max_hp, stats_json, inv_json, equip_json, location, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-		ON CONFLICT(id) DO UPDATE SET
-			name=excluded.name, class=excluded.class, level=excluded.level,
-			xp=excluded.xp, hp=excluded.hp, max_hp=excluded.max_hp,
-			stats_json=excluded.stats_json, inv_json=excluded.inv_json,
-			equip_json=excluded.equip_json, location=excluded.location,
-			updated_at=excluded.updated_at
-	`,
-		c.ID, c.Name, c.Class, c.Level, c.XP, c.HP, c.MaxHP,
-		string(statsJSON), string(invJSON), string(equipJSON), c.Location,
-		c.CreatedAt, c.UpdatedAt,
-	)
-	return err
+	var res sql.Result
+	if c.Version == 0 {
+		res, err = s.db.ExecContext(ctx, `
+			INSERT INTO characters
+				(id, name, class, level, xp, hp, max_hp, stats_json, inv_json, equip_json, effects_json, vehicles_json, statuses_json, location, created_at, updated_at, version)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 1)
+			ON CONFLICT(id) DO NOTHING
+		`,
+			c.ID, c.Name, c.Class, c.Level, c.XP, c.HP, c.MaxHP,
+			string(statsJSON), string(invJSON), string(equipJSON), string(effectsJSON), string(vehiclesJSON), string(statusesJSON), c.Location,
+			c.CreatedAt, c.UpdatedAt,
+		)
+	} else {
+		res, err = s.db.ExecContext(ctx, `
+			UPDATE characters SET
+				name=?, class=?, level=?, xp=?, hp=?, max_hp=?,
+				stats_json=?, inv_json=?, equip_json=?, effects_json=?,
+				vehicles_json=?, statuses_json=?, location=?,
+				updated_at=?, version=version+1
+			WHERE id = ? AND version = ?
+		`,
+			c.Name, c.Class, c.Level, c.XP, c.HP, c.MaxHP,
+			string(statsJSON), string(invJSON), string(equipJSON), string(effectsJSON), string(vehiclesJSON), string(statusesJSON), c.Location,
+			c.UpdatedAt, c.ID, c.Version,
+		)
+	}
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return ErrStale
+	}
+	c.Version++
+	return nil
+}
+
+// Update loads a character, applies fn, and saves it if fn succeeds. If
+// another request saved the character in between, it reloads and applies fn
+// again. Returns (nil, nil) if the character does not exist.
+func (s *Store) Update(ctx context.Context, id string, fn func(*Character) error) (*Character, error) {
+	for range updateAttempts - 1 {
+		c, err := s.update(ctx, id, fn)
+		if !errors.Is(err, ErrStale) {
+			return c, err
+		}
+	}
+	return s.update(ctx, id, fn)
+}
+
+func (s *Store) update(ctx context.Context, id string, fn func(*Character) error) (*Character, error) {
+	c, err := s.Load(ctx, id)
+	if err != nil || c == nil {
+		return nil, err
+	}
+	if err := fn(c); err != nil {
+		return nil, err
+	}
+	if err := s.Save(ctx, c); err != nil {
+		return nil, err
+	}
+	return c, nil
 }
 
 // Load retrieves a character by ID.
 // Returns (nil, nil) if not found.
 func (s *Store) Load(ctx context.Context, id string) (*Character, error) {
 	row := s.db.QueryRowContext(ctx, `
-		SELECT id, name, class, level, xp, hp, max_hp, stats_json, inv_json, equip_json, location, created_at, updated_at
+		SELECT id, name, class, level, xp, hp, max_hp, stats_json, inv_json, equip_json, effects_json, vehicles_json, statuses_json, location, created_at, updated_at, version
 		FROM characters WHERE id = ?
 	`, id)
 
 	var c Character
-	var statsJSON, invJSON, equipJSON string
+	var statsJSON, invJSON, equipJSON, effectsJSON, vehiclesJSON, statusesJSON string
 	var createdAt, updatedAt string
 
 	err := row.Scan(
 		&c.ID, &c.Name, &c.Class, &c.Level, &c.XP, &c.HP, &c.MaxHP,
-		&statsJSON, &invJSON, &equipJSON, &c.Location, &createdAt, &updatedAt,
+		&statsJSON, &invJSON, &equipJSON, &effectsJSON, &vehiclesJSON, &statusesJSON, &c.Location, &createdAt, &updatedAt, &c.Version,
 	)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -137,6 +240,17 @@ func (s *Store) Load(ctx context.Context, id string) (*Character, error) {
 	if err := json.Unmarshal([]byte(equipJSON), &c.Equipment); err != nil {
 		c.Equipment = Equipment{}
 	}
+	// effects_json is likewise non-fatal; expired effects are dropped on load.
+	if err := json.Unmarshal([]byte(effectsJSON), &c.Effects); err != nil || c.Effects == nil {
+		c.Effects = []Effect{}
+	}
+	c.PruneEffects(time.Now())
+	if err := json.Unmarshal([]byte(vehiclesJSON), &c.Vehicles); err != nil || c.Vehicles == nil {
+		c.Vehicles = []OwnedVehicle{}
+	}
+	if err := json.Unmarshal([]byte(statusesJSON), &c.Statuses); err != nil || c.Statuses == nil {
+		c.Statuses = []Status{}
+	}
 
 	c.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
 	c.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
@@ -144,6 +258,12 @@ func (s *Store) Load(ctx context.Context, id string) (*Character, error) {
 	return &c, nil
 }
 
+// DB exposes the underlying connection so other game stores can share the
+// same SQLite file (one writer, one WAL).
+func (s *Store) DB() *sql.DB {
+	return s.db
+}
+
 // Close shuts down the database connection.
 func (s *Store) Close() error {
 	return s.db.Close()
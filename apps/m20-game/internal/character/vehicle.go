@@ -0,0 +1,40 @@
+package character
+
+import "m20-game/internal/resources"
+
+// MaxVehicles is how many vehicles one character can keep track of.
+const MaxVehicles = 2
+
+// OwnedVehicle is a vehicle a character has claimed. Condition changes with
+// repairs; Fuel counts the drives left in the tank.
+type OwnedVehicle struct {
+	ID string `json:"id"`
+	resources.Vehicle
+	Fuel int `json:"fuel"`
+}
+
+// Vehicle returns the owned vehicle with id, or nil.
+func (c *Character) Vehicle(id string) *OwnedVehicle {
+	for i := range c.Vehicles {
+		if c.Vehicles[i].ID == id {
+			return &c.Vehicles[i]
+		}
+	}
+	return nil
+}
+
+// HasItems reports whether the inventory holds every item in items, counting
+// duplicates.
+func (c *Character) HasItems(items []string) bool {
+	have := make(map[string]int, len(c.Inventory))
+	for _, name := range c.Inventory {
+		have[name]++
+	}
+	for _, name := range items {
+		if have[name] == 0 {
+			return false
+		}
+		have[name]--
+	}
+	return true
+}
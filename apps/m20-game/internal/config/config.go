@@ -1,29 +1,389 @@
-// Package config loads environment variables with safe defaults.
+// Package config loads typed runtime configuration for m20-game.
+//
+// Sources are layered, later ones winning (same scheme as weather-service):
+//
+//	defaults → YAML file (-config / CONFIG_FILE) → environment → command-line flags
+//
+// Load validates the merged result so a bad value fails the process at startup.
 package config
 
-import "os"
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+)
 
 // Config holds all runtime configuration for m20-game.
 type Config struct {
-	Port      string
-	DBPath    string
-	OllamaURL string
-	LogLevel  string
+	Port              string        `yaml:"port"`
+	DBPath            string        `yaml:"db_path"`
+	LogLevel          string        `yaml:"log_level"`
+	ReadTimeout       time.Duration `yaml:"read_timeout"`
+	WriteTimeout      time.Duration `yaml:"write_timeout"`
+	AIBackend         string        `yaml:"ai_backend"` // narration backend: ollama|openai|fake
+	OllamaURL         string        `yaml:"ollama_url"`
+	OllamaModel       string        `yaml:"ollama_model"`
+	OllamaTimeout     time.Duration `yaml:"ollama_timeout"` // HTTP client ceiling per AI call (any backend)
+	OpenAIURL         string        `yaml:"openai_url"`     // OpenAI-compatible server root, without /v1
+	OpenAIModel       string        `yaml:"openai_model"`
+	OpenAIAPIKey      string        `yaml:"openai_api_key"`
+	NarrationStyle    string        `yaml:"narration_style"`     // tone prepended to prompts; "" = built-in B-movie style
+	PromptDir         string        `yaml:"prompt_dir"`          // <name>.tmpl overrides for the embedded prompt templates
+	NarrationPool     int           `yaml:"narration_pool"`      // pre-generated lines kept per prompt+inputs; 0 disables the pool
+	NarrationMemory   int           `yaml:"narration_memory"`    // journal entries summarized into narration prompts; those lines skip the pool; 0 disables
+	AIMaxInFlight     int           `yaml:"ai_max_in_flight"`    // AI generations running at once
+	AIQueueSize       int           `yaml:"ai_queue_size"`       // callers allowed to wait for a generation slot
+	AIBreakerFailures int           `yaml:"ai_breaker_failures"` // consecutive AI errors that open the circuit breaker
+	AIBreakerCooldown time.Duration `yaml:"ai_breaker_cooldown"` // how long the breaker stays open before a probe
+	NarrationTimeout  time.Duration `yaml:"narration_timeout"`   // entrance, dialogue, and combat lines
+	RiddleTimeout     time.Duration `yaml:"riddle_timeout"`      // Sphinx riddle generation
+	AdminToken        string        `yaml:"admin_token"`         // unlocks PUT /api/character/:id; "" disables it
+	XPSplit           string        `yaml:"xp_split"`            // how survivors share a won fight's XP: even|killer|level
+	ContentDirs       []string      `yaml:"content_dirs"`        // content pack overrides, applied in order over the embedded pack
+
+	// File is the YAML file the config was read from ("" if none).
+	File string `yaml:"-"`
 }
 
-// Load reads environment variables and returns a Config with defaults applied.
-func Load() *Config {
+// Default returns the built-in configuration used when no source overrides a value.
+func Default() *Config {
 	return &Config{
-		Port:      getEnv("PORT", "8082"),
-		DBPath:    getEnv("DB_PATH", "./data/m20.db"),
-		OllamaURL: getEnv("OLLAMA_URL", "http://ollama:11434"),
-		LogLevel:  getEnv("LOG_LEVEL", "info"),
+		Port:              "8082",
+		DBPath:            "./data/m20.db",
+		LogLevel:          "info",
+		ReadTimeout:       15 * time.Second,
+		WriteTimeout:      60 * time.Second,
+		AIBackend:         "ollama",
+		OllamaURL:         "http://ollama:11434",
+		OllamaModel:       "llama3.2:1b",
+		OllamaTimeout:     30 * time.Second,
+		OpenAIURL:         "https://api.openai.com",
+		OpenAIModel:       "gpt-4o-mini",
+		NarrationPool:     5,
+		NarrationMemory:   5,
+		AIMaxInFlight:     2,
+		AIQueueSize:       16,
+		AIBreakerFailures: 5,
+		AIBreakerCooldown: 30 * time.Second,
+		NarrationTimeout:  10 * time.Second,
+		RiddleTimeout:     25 * time.Second,
+		XPSplit:           "even",
+	}
+}
+
+// Load builds the configuration from all sources and validates it.
+// args are the command-line arguments without the program name (os.Args[1:]).
+func Load(args []string) (*Config, error) {
+	// First pass only discovers -config; flag values are re-applied on top
+	// of file and env below so they keep the highest precedence.
+	path := os.Getenv("CONFIG_FILE")
+	if err := newFlagSet(Default(), &path).Parse(args); err != nil {
+		return nil, err
+	}
+
+	cfg := Default()
+	if path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+	if err := cfg.applyEnv(); err != nil {
+		return nil, err
+	}
+	if err := newFlagSet(cfg, &path).Parse(args); err != nil {
+		return nil, err
+	}
+	cfg.File = path
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// newFlagSet binds every knob to cfg, using the current values as defaults.
+func newFlagSet(cfg *Config, path *string) *flag.FlagSet {
+	fs := flag.NewFlagSet("m20-game", flag.ContinueOnError)
+	fs.StringVar(path, "config", *path, "path to a YAML config file")
+	fs.StringVar(&cfg.Port, "port", cfg.Port, "HTTP listen port")
+	fs.StringVar(&cfg.DBPath, "db-path", cfg.DBPath, "SQLite database path")
+	fs.StringVar(&cfg.LogLevel, "log-level", cfg.LogLevel, "log level: debug|info|warn|error")
+	fs.DurationVar(&cfg.ReadTimeout, "read-timeout", cfg.ReadTimeout, "HTTP server read timeout")
+	fs.DurationVar(&cfg.WriteTimeout, "write-timeout", cfg.WriteTimeout, "HTTP server write timeout")
+	fs.StringVar(&cfg.AIBackend, "ai-backend", cfg.AIBackend, "narration backend: ollama|openai|fake")
+	fs.StringVar(&cfg.OllamaURL, "ollama-url", cfg.OllamaURL, "Ollama base URL")
+	fs.StringVar(&cfg.OllamaModel, "ollama-model", cfg.OllamaModel, "Ollama model name")
+	fs.DurationVar(&cfg.OllamaTimeout, "ollama-timeout", cfg.OllamaTimeout, "HTTP client timeout for AI backend calls")
+	fs.StringVar(&cfg.OpenAIURL, "openai-url", cfg.OpenAIURL, "OpenAI-compatible server root (without /v1)")
+	fs.StringVar(&cfg.OpenAIModel, "openai-model", cfg.OpenAIModel, "OpenAI-compatible model name")
+	fs.StringVar(&cfg.OpenAIAPIKey, "openai-api-key", cfg.OpenAIAPIKey, "API key for the OpenAI-compatible server (empty sends none)")
+	fs.StringVar(&cfg.NarrationStyle, "narration-style", cfg.NarrationStyle, "narration tone prepended to prompts (empty uses the built-in style)")
+	fs.StringVar(&cfg.PromptDir, "prompt-dir", cfg.PromptDir, "directory of <name>.tmpl prompt template overrides")
+	fs.IntVar(&cfg.NarrationPool, "narration-pool", cfg.NarrationPool, "pre-generated narration lines kept per prompt (0 disables)")
+	fs.IntVar(&cfg.NarrationMemory, "narration-memory", cfg.NarrationMemory, "recent journal entries summarized into narration prompts; those lines skip the narration pool (0 disables)")
+	fs.IntVar(&cfg.AIMaxInFlight, "ai-max-in-flight", cfg.AIMaxInFlight, "AI generations running at once")
+	fs.IntVar(&cfg.AIQueueSize, "ai-queue-size", cfg.AIQueueSize, "callers allowed to wait for an AI generation slot")
+	fs.IntVar(&cfg.AIBreakerFailures, "ai-breaker-failures", cfg.AIBreakerFailures, "consecutive AI errors that open the circuit breaker")
+	fs.DurationVar(&cfg.AIBreakerCooldown, "ai-breaker-cooldown", cfg.AIBreakerCooldown, "how long the AI circuit breaker stays open before a probe")
+	fs.DurationVar(&cfg.NarrationTimeout, "narration-timeout", cfg.NarrationTimeout, "timeout for entrance/dialogue/combat narration")
+	fs.DurationVar(&cfg.RiddleTimeout, "riddle-timeout", cfg.RiddleTimeout, "timeout for Sphinx riddle generation")
+	fs.StringVar(&cfg.AdminToken, "admin-token", cfg.AdminToken, "token for admin-only character overrides (empty disables them)")
+	fs.StringVar(&cfg.XPSplit, "xp-split", cfg.XPSplit, "XP split among survivors of a won encounter: even|killer|level")
+	fs.Func("content-dirs", "comma-separated content pack override directories", func(v string) error {
+		cfg.ContentDirs = splitList(v)
+		return nil
+	})
+	return fs
+}
+
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config file: %w", err)
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("parse config file %s: %w", path, err)
+	}
+	return nil
+}
+
+func (c *Config) applyEnv() error {
+	envString("PORT", &c.Port)
+	envString("DB_PATH", &c.DBPath)
+	envString("LOG_LEVEL", &c.LogLevel)
+	envString("AI_BACKEND", &c.AIBackend)
+	envString("OLLAMA_URL", &c.OllamaURL)
+	envString("OLLAMA_MODEL", &c.OllamaModel)
+	envString("OPENAI_URL", &c.OpenAIURL)
+	envString("OPENAI_MODEL", &c.OpenAIModel)
+	envString("OPENAI_API_KEY", &c.OpenAIAPIKey)
+	envString("NARRATION_STYLE", &c.NarrationStyle)
+	envString("PROMPT_DIR", &c.PromptDir)
+	envString("ADMIN_TOKEN", &c.AdminToken)
+	envString("XP_SPLIT", &c.XPSplit)
+	if v, ok := os.LookupEnv("CONTENT_DIRS"); ok && v != "" {
+		c.ContentDirs = splitList(v)
+	}
+	return errors.Join(
+		envDuration("READ_TIMEOUT", &c.ReadTimeout),
+		envDuration("WRITE_TIMEOUT", &c.WriteTimeout),
+		envDuration("OLLAMA_TIMEOUT", &c.OllamaTimeout),
+		envDuration("NARRATION_TIMEOUT", &c.NarrationTimeout),
+		envDuration("RIDDLE_TIMEOUT", &c.RiddleTimeout),
+		envInt("NARRATION_POOL", &c.NarrationPool),
+		envInt("NARRATION_MEMORY", &c.NarrationMemory),
+		envInt("AI_MAX_IN_FLIGHT", &c.AIMaxInFlight),
+		envInt("AI_QUEUE_SIZE", &c.AIQueueSize),
+		envInt("AI_BREAKER_FAILURES", &c.AIBreakerFailures),
+		envDuration("AI_BREAKER_COOLDOWN", &c.AIBreakerCooldown),
+	)
+}
+
+// Validate reports every invalid field at once, joined into a single error.
+func (c *Config) Validate() error {
+	var errs []error
+	if p, err := strconv.Atoi(c.Port); err != nil || p < 1 || p > 65535 {
+		errs = append(errs, fmt.Errorf("port: %q is not a valid TCP port", c.Port))
+	}
+	if strings.TrimSpace(c.DBPath) == "" {
+		errs = append(errs, errors.New("db_path: must not be empty"))
+	}
+	if _, err := ParseLogLevel(c.LogLevel); err != nil {
+		errs = append(errs, fmt.Errorf("log_level: %w", err))
+	}
+	if u, err := url.Parse(c.OllamaURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errs = append(errs, fmt.Errorf("ollama_url: %q must be an http(s) URL", c.OllamaURL))
+	}
+	if strings.TrimSpace(c.OllamaModel) == "" {
+		errs = append(errs, errors.New("ollama_model: must not be empty"))
+	}
+	switch c.AIBackend {
+	case "ollama", "fake":
+	case "openai":
+		if u, err := url.Parse(c.OpenAIURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, fmt.Errorf("openai_url: %q must be an http(s) URL", c.OpenAIURL))
+		}
+		if strings.TrimSpace(c.OpenAIModel) == "" {
+			errs = append(errs, errors.New("openai_model: must not be empty"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("ai_backend: unknown backend %q (want ollama|openai|fake)", c.AIBackend))
+	}
+	if c.PromptDir != "" {
+		if fi, err := os.Stat(c.PromptDir); err != nil || !fi.IsDir() {
+			errs = append(errs, fmt.Errorf("prompt_dir: %q is not a directory", c.PromptDir))
+		}
+	}
+	for _, dir := range c.ContentDirs {
+		if fi, err := os.Stat(dir); err != nil || !fi.IsDir() {
+			errs = append(errs, fmt.Errorf("content_dirs: %q is not a directory", dir))
+		}
+	}
+	if c.NarrationPool < 0 || c.NarrationPool > 50 {
+		errs = append(errs, fmt.Errorf("narration_pool: %d must be between 0 and 50", c.NarrationPool))
+	}
+	if c.NarrationMemory < 0 || c.NarrationMemory > 50 {
+		errs = append(errs, fmt.Errorf("narration_memory: %d must be between 0 and 50", c.NarrationMemory))
+	}
+	if c.AIMaxInFlight < 1 {
+		errs = append(errs, fmt.Errorf("ai_max_in_flight: %d must be at least 1", c.AIMaxInFlight))
 	}
+	if c.AIQueueSize < 0 {
+		errs = append(errs, fmt.Errorf("ai_queue_size: %d must not be negative", c.AIQueueSize))
+	}
+	if c.AIBreakerFailures < 1 {
+		errs = append(errs, fmt.Errorf("ai_breaker_failures: %d must be at least 1", c.AIBreakerFailures))
+	}
+	switch c.XPSplit {
+	case "even", "killer", "level":
+	default:
+		errs = append(errs, fmt.Errorf("xp_split: unknown rule %q (want even|killer|level)", c.XPSplit))
+	}
+	errs = append(errs,
+		positive("read_timeout", c.ReadTimeout),
+		positive("write_timeout", c.WriteTimeout),
+		positive("ollama_timeout", c.OllamaTimeout),
+		positive("narration_timeout", c.NarrationTimeout),
+		positive("riddle_timeout", c.RiddleTimeout),
+		positive("ai_breaker_cooldown", c.AIBreakerCooldown),
+	)
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+	return nil
 }
 
-func getEnv(key, fallback string) string {
-	if v := os.Getenv(key); v != "" {
-		return v
+// Redacted returns a JSON-friendly view of the config with secrets masked,
+// suitable for the /config endpoint. Credentials embedded in URLs are stripped.
+func (c *Config) Redacted() map[string]interface{} {
+	return map[string]interface{}{
+		"file":                c.File,
+		"port":                c.Port,
+		"db_path":             c.DBPath,
+		"log_level":           c.LogLevel,
+		"read_timeout":        c.ReadTimeout.String(),
+		"write_timeout":       c.WriteTimeout.String(),
+		"ai_backend":          c.AIBackend,
+		"ollama_url":          redactURL(c.OllamaURL),
+		"ollama_model":        c.OllamaModel,
+		"ollama_timeout":      c.OllamaTimeout.String(),
+		"openai_url":          redactURL(c.OpenAIURL),
+		"openai_model":        c.OpenAIModel,
+		"openai_api_key":      redactSecret(c.OpenAIAPIKey),
+		"narration_style":     c.NarrationStyle,
+		"prompt_dir":          c.PromptDir,
+		"narration_pool":      c.NarrationPool,
+		"narration_memory":    c.NarrationMemory,
+		"ai_max_in_flight":    c.AIMaxInFlight,
+		"ai_queue_size":       c.AIQueueSize,
+		"ai_breaker_failures": c.AIBreakerFailures,
+		"ai_breaker_cooldown": c.AIBreakerCooldown.String(),
+		"narration_timeout":   c.NarrationTimeout.String(),
+		"riddle_timeout":      c.RiddleTimeout.String(),
+		"admin_token":         redactSecret(c.AdminToken),
+		"xp_split":            c.XPSplit,
+		"content_dirs":        c.ContentDirs,
+	}
+}
+
+// NarratorModel returns the model for the configured AI backend.
+func (c *Config) NarratorModel() string {
+	if c.AIBackend == "openai" {
+		return c.OpenAIModel
+	}
+	return c.OllamaModel
+}
+
+// ParseLogLevel maps a config log level onto slog.
+func ParseLogLevel(s string) (slog.Level, error) {
+	switch strings.ToLower(s) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info", "":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return slog.LevelInfo, fmt.Errorf("unknown level %q (want debug|info|warn|error)", s)
+}
+
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.User == nil {
+		return raw
+	}
+	u.User = url.User("REDACTED")
+	return u.String()
+}
+
+// redactSecret masks a secret but still shows whether one is set.
+func redactSecret(s string) string {
+	if s == "" {
+		return ""
+	}
+	return "REDACTED"
+}
+
+func positive(name string, d time.Duration) error {
+	if d <= 0 {
+		return fmt.Errorf("%s: %s must be positive", name, d)
+	}
+	return nil
+}
+
+// splitList splits a comma-separated value, dropping empty entries.
+func splitList(v string) []string {
+	var out []string
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func envString(key string, dst *string) {
+	if v, ok := os.LookupEnv(key); ok && v != "" {
+		*dst = v
+	}
+}
+
+func envInt(key string, dst *int) error {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return fmt.Errorf("%s: %q is not an integer", key, v)
+	}
+	*dst = n
+	return nil
+}
+
+func envDuration(key string, dst *time.Duration) error {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return fmt.Errorf("%s: %q is not a duration (e.g. 30s, 5m)", key, v)
 	}
-	return fallback
+	*dst = d
+	return nil
 }
@@ -0,0 +1,154 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"m20-game/internal/obs"
+)
+
+// Reloader owns the live configuration and re-reads it on SIGHUP or when the
+// config file changes. Only fields listed in safeFields are applied live; a
+// reload that touches anything else is rejected and the old config kept.
+type Reloader struct {
+	mu    sync.RWMutex
+	cur   *Config
+	args  []string
+	apply func(*Config)
+}
+
+// safeFields are the knobs that can change without a restart.
+var safeFields = []string{"log_level", "ollama_model", "openai_model", "narration_style", "narration_memory", "narration_timeout", "riddle_timeout", "admin_token", "xp_split"}
+
+// NewReloader wraps an already-loaded config. apply is called with the new
+// config after every successful reload (and never concurrently).
+func NewReloader(cfg *Config, args []string, apply func(*Config)) *Reloader {
+	return &Reloader{cur: cfg, args: args, apply: apply}
+}
+
+// Current returns the active config. Callers must treat it as read-only.
+func (r *Reloader) Current() *Config {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cur
+}
+
+// Reload re-reads all sources, validates, and applies safe changes.
+func (r *Reloader) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	next, err := Load(r.args)
+	if err != nil {
+		obs.ConfigReloadsTotal.WithLabelValues("failure").Inc()
+		return err
+	}
+	if err := r.cur.CheckReload(next); err != nil {
+		obs.ConfigReloadsTotal.WithLabelValues("rejected").Inc()
+		return err
+	}
+	r.cur = next
+	if r.apply != nil {
+		r.apply(next)
+	}
+	obs.ConfigReloadsTotal.WithLabelValues("success").Inc()
+	return nil
+}
+
+// CheckReload returns an error naming every field that differs between c and
+// next but cannot be applied live.
+func (c *Config) CheckReload(next *Config) error {
+	unsafe := []struct {
+		name    string
+		changed bool
+	}{
+		{"port", c.Port != next.Port},
+		{"db_path", c.DBPath != next.DBPath},
+		{"read_timeout", c.ReadTimeout != next.ReadTimeout},
+		{"write_timeout", c.WriteTimeout != next.WriteTimeout},
+		{"ai_backend", c.AIBackend != next.AIBackend},
+		{"ollama_url", c.OllamaURL != next.OllamaURL},
+		{"openai_url", c.OpenAIURL != next.OpenAIURL},
+		{"openai_api_key", c.OpenAIAPIKey != next.OpenAIAPIKey},
+		{"prompt_dir", c.PromptDir != next.PromptDir},
+		{"narration_pool", c.NarrationPool != next.NarrationPool},
+		{"ai_max_in_flight", c.AIMaxInFlight != next.AIMaxInFlight},
+		{"ai_queue_size", c.AIQueueSize != next.AIQueueSize},
+		{"ai_breaker_failures", c.AIBreakerFailures != next.AIBreakerFailures},
+		{"ai_breaker_cooldown", c.AIBreakerCooldown != next.AIBreakerCooldown},
+		{"ollama_timeout", c.OllamaTimeout != next.OllamaTimeout},
+		{"content_dirs", !slices.Equal(c.ContentDirs, next.ContentDirs)},
+	}
+	var changed []string
+	for _, f := range unsafe {
+		if f.changed {
+			changed = append(changed, f.name)
+		}
+	}
+	if len(changed) > 0 {
+		return fmt.Errorf("reload rejected: %s require a restart (live-reloadable: %s)",
+			strings.Join(changed, ", "), strings.Join(safeFields, ", "))
+	}
+	return nil
+}
+
+// Watch reloads on SIGHUP and, if a config file is in use, whenever its
+// modification time changes (polled every interval). Blocks until ctx is done.
+func (r *Reloader) Watch(ctx context.Context, interval time.Duration) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	lastMod := r.fileModTime()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			r.reloadAndLog("sighup")
+			lastMod = r.fileModTime()
+		case <-ticker.C:
+			if mod := r.fileModTime(); !mod.Equal(lastMod) {
+				lastMod = mod
+				r.reloadAndLog("file_change")
+			}
+		}
+	}
+}
+
+func (r *Reloader) reloadAndLog(trigger string) {
+	if err := r.Reload(); err != nil {
+		slog.Error("config reload failed", "trigger", trigger, "error", err)
+		return
+	}
+	cfg := r.Current()
+	slog.Info("config reloaded", "trigger", trigger, "model", cfg.NarratorModel(),
+		"narration_timeout", cfg.NarrationTimeout.String(), "log_level", cfg.LogLevel)
+}
+
+func (r *Reloader) fileModTime() time.Time {
+	path := r.Current().File
+	if path == "" {
+		return time.Time{}
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			slog.Warn("config file stat failed", "path", path, "error", err)
+		}
+		return time.Time{}
+	}
+	return info.ModTime()
+}
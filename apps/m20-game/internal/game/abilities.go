@@ -0,0 +1,102 @@
+package game
+
+import (
+	"math/rand"
+
+	"m20-game/internal/resources"
+)
+
+// Modifiers are the rule changes a class's SpecialAbility grants.
+// The zero value means "no ability" and leaves every rule untouched.
+type Modifiers struct {
+	CritThresholdDelta   int `json:"crit_threshold_delta,omitempty"`   // Brawler: crits on 18+
+	InitiativeBonus      int `json:"initiative_bonus,omitempty"`       // Gunslinger: always acts first
+	FirstStrikeBonus     int `json:"first_strike_bonus,omitempty"`     // Gunslinger: bonus on first attack of a fight
+	CombatHeals          int `json:"combat_heals,omitempty"`           // Medic: self-heals per fight
+	RiddleBonus          int `json:"riddle_bonus,omitempty"`           // Conspiracy Theorist: added to riddle checks
+	MedicalCraftDiscount int `json:"medical_craft_discount,omitempty"` // Street Pharmacist: tiers below required level
+	ExtraScavengeFinds   int `json:"extra_scavenge_finds,omitempty"`   // Scavenger: extra items per successful scavenge
+	VehicleMaterialSaved int `json:"vehicle_material_saved,omitempty"` // Wrench Witch: materials waived on vehicle builds
+}
+
+// Hoarder's extra slots are applied by character.MaxInventorySlots.
+var classModifiers = map[string]Modifiers{
+	"Brawler":             {CritThresholdDelta: -2},
+	"Gunslinger":          {InitiativeBonus: 10, FirstStrikeBonus: 2},
+	"Medic":               {CombatHeals: 1},
+	"Conspiracy Theorist": {RiddleBonus: 3},
+	"Street Pharmacist":   {MedicalCraftDiscount: 1},
+	"Scavenger":           {ExtraScavengeFinds: 1},
+	"Wrench Witch":        {VehicleMaterialSaved: 1},
+}
+
+// ClassModifiers returns the rule modifiers for a class (zero value if none).
+func ClassModifiers(class string) Modifiers {
+	return classModifiers[class]
+}
+
+// CritThreshold applies the class delta to a base threshold, never going
+// below 2 (a natural 1 is always a critical failure).
+func (m Modifiers) CritThreshold(base int) int {
+	if base <= 0 {
+		base = 20
+	}
+	return max(2, base+m.CritThresholdDelta)
+}
+
+// medicHealDice is the heal a Medic's ability restores: 1d6 + this.
+const medicHealDice = 2
+
+// RequiredCraftingLevel is the crafting stat needed for item after class discounts.
+func (m Modifiers) RequiredCraftingLevel(item resources.CraftableItem) int {
+	if item.Category == resources.CategoryMedical {
+		return max(0, item.CraftingLevel-m.MedicalCraftDiscount)
+	}
+	return item.CraftingLevel
+}
+
+// MaterialsFor returns the materials item consumes after class discounts.
+// Waived materials come off the end of the recipe.
+func (m Modifiers) MaterialsFor(item resources.CraftableItem) []string {
+	mats := item.Materials
+	if item.Category == resources.CategoryVehicle && m.VehicleMaterialSaved > 0 {
+		keep := max(1, len(mats)-m.VehicleMaterialSaved)
+		mats = mats[:keep]
+	}
+	return append([]string(nil), mats...)
+}
+
+// CraftableWith filters CraftableItems to what materials and craftingLevel can
+// build once class discounts are applied. Returned items carry the discounted
+// level and materials.
+func CraftableWith(materials []string, craftingLevel int, m Modifiers) []resources.CraftableItem {
+	have := make(map[string]bool, len(materials))
+	for _, mat := range materials {
+		have[mat] = true
+	}
+	var result []resources.CraftableItem
+	for _, item := range resources.CraftableItems() {
+		if m.RequiredCraftingLevel(item) > craftingLevel {
+			continue
+		}
+		canMake := true
+		for _, required := range m.MaterialsFor(item) {
+			if !have[required] {
+				canMake = false
+				break
+			}
+		}
+		if canMake {
+			item.CraftingLevel = m.RequiredCraftingLevel(item)
+			item.Materials = m.MaterialsFor(item)
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// RiddleCheck rolls stat + the class riddle bonus against target (usually the
+// Sphinx's Defense).
+func RiddleCheck(rng *rand.Rand, stat, target int, m Modifiers) CombatRollResult {
+	return Roll(rng, CombatRollRequest{StatValue: stat, Bonus: m.RiddleBonus, Target: target}, 20)
+}
@@ -15,21 +15,21 @@ type BuildingExploreResult struct {
 }
 
 // ExploreBuilding enters a random building and determines loot and danger.
-func ExploreBuilding() BuildingExploreResult {
+func ExploreBuilding(rng *rand.Rand) BuildingExploreResult {
 	buildings := resources.Buildings()
-	b := buildings[rand.Intn(len(buildings))]
+	b := buildings[rng.Intn(len(buildings))]
 
 	// Danger level (1-5) determines chance of monster encounter.
-	hasMonster := rand.Intn(5)+1 <= b.Danger
+	hasMonster := rng.Intn(5)+1 <= b.Danger
 
 	// Loot: pick 1-3 items from the building's loot table.
-	count := rand.Intn(len(b.Loot)) + 1
+	count := rng.Intn(len(b.Loot)) + 1
 	if count > 3 {
 		count = 3
 	}
 	shuffled := make([]string, len(b.Loot))
 	copy(shuffled, b.Loot)
-	rand.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+	rng.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
 
 	desc := b.Description
 	if hasMonster {
@@ -18,31 +18,46 @@ const (
 	OutcomeCritFailure RollOutcome = "crit_failure" // natural 1
 )
 
+// DefaultTarget is the number a roll must meet when no defense is known.
+const DefaultTarget = 10
+
 // CombatRollRequest is the input to a combat roll.
 type CombatRollRequest struct {
-	StatValue int `json:"stat"`  // the relevant character stat
-	Bonus     int `json:"bonus"` // situational or class bonus
+	StatValue int    `json:"stat"`             // the relevant character stat
+	Bonus     int    `json:"bonus"`            // situational or class bonus
+	Target    int    `json:"target,omitempty"` // defense to meet; 0 = DefaultTarget
+	Class     string `json:"class,omitempty"`  // applies class modifiers (e.g. Brawler crits)
 }
 
 // CombatRollResult is the full result of a D20 roll.
 type CombatRollResult struct {
-	Roll       int         `json:"roll"`        // raw D20 result (1-20)
-	Total      int         `json:"total"`        // roll + stat + bonus
+	Roll       int         `json:"roll"`  // raw D20 result (1-20)
+	Total      int         `json:"total"` // roll + stat + bonus
 	Outcome    RollOutcome `json:"outcome"`
 	StatValue  int         `json:"stat_value"`
 	Bonus      int         `json:"bonus"`
 	CritThresh int         `json:"crit_threshold"` // roll >= this = crit success
+	Target     int         `json:"target"`         // total >= this = success
 }
 
-// Roll executes a D20 combat check.
+// Roll executes a D20 combat check against req.Target (a monster's Defense).
 // Critical success: natural 20. Critical failure: natural 1.
 // The critThreshold parameter lets class abilities lower the crit threshold.
-func Roll(req CombatRollRequest, critThreshold int) CombatRollResult {
+func Roll(rng *rand.Rand, req CombatRollRequest, critThreshold int) CombatRollResult {
 	if critThreshold <= 0 {
 		critThreshold = 20
 	}
+	// Class thresholds cap rather than stack, so a client that already sends
+	// 18 for a Brawler is not double-counted.
+	if req.Class != "" {
+		critThreshold = min(critThreshold, ClassModifiers(req.Class).CritThreshold(20))
+	}
+	target := req.Target
+	if target <= 0 {
+		target = DefaultTarget
+	}
 
-	roll := rand.Intn(20) + 1
+	roll := D20(rng)
 	total := roll + req.StatValue + req.Bonus
 
 	var outcome RollOutcome
@@ -51,7 +66,7 @@ func Roll(req CombatRollRequest, critThreshold int) CombatRollResult {
 		outcome = OutcomeCritFailure
 	case roll >= critThreshold:
 		outcome = OutcomeCritSuccess
-	case total >= 10:
+	case total >= target:
 		outcome = OutcomeSuccess
 	default:
 		outcome = OutcomeFailure
@@ -66,10 +81,11 @@ func Roll(req CombatRollRequest, critThreshold int) CombatRollResult {
 		StatValue:  req.StatValue,
 		Bonus:      req.Bonus,
 		CritThresh: critThreshold,
+		Target:     target,
 	}
 }
 
 // D20 returns a raw 1-20 roll with no modifiers.
-func D20() int {
-	return rand.Intn(20) + 1
+func D20(rng *rand.Rand) int {
+	return rng.Intn(20) + 1
 }
@@ -0,0 +1,480 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"m20-game/internal/character"
+	"m20-game/internal/obs"
+	"m20-game/internal/resources"
+)
+
+// EncounterStatus is the lifecycle state of a server-side encounter.
+type EncounterStatus string
+
+const (
+	EncounterActive  EncounterStatus = "active"
+	EncounterVictory EncounterStatus = "victory" // every monster down
+	EncounterDefeat  EncounterStatus = "defeat"  // every character down
+	EncounterFled    EncounterStatus = "fled"
+)
+
+// CombatantKind distinguishes party members from monsters in the turn order.
+type CombatantKind string
+
+const (
+	KindCharacter CombatantKind = "character"
+	KindMonster   CombatantKind = "monster"
+)
+
+// Encounter actions a character can take on their turn.
+const (
+	ActionAttack = "attack"
+	ActionHeal   = "heal" // class ability: Medic self-heal
+	ActionFlee   = "flee"
+)
+
+// MaxPartySize matches the four party slots in the UI.
+const MaxPartySize = character.MaxPartySize
+
+var (
+	ErrEncounterOver = errors.New("encounter is over")
+	ErrNotYourTurn   = errors.New("it is not this character's turn")
+	ErrUnknownAction = errors.New("unknown action")
+	ErrInvalidTarget = errors.New("target is not a living monster in this encounter")
+	ErrNoAbility     = errors.New("ability not available")
+
+	ErrAlreadyFighting = errors.New("character is already in an active encounter")
+)
+
+// Combatant is one entry in the initiative order.
+type Combatant struct {
+	ID            string        `json:"id"` // "c0".."c3" for the party, "m0".. for monsters
+	Kind          CombatantKind `json:"kind"`
+	Name          string        `json:"name"`
+	CharacterID   string        `json:"character_id,omitempty"`
+	Class         string        `json:"class,omitempty"`
+	HP            int           `json:"hp"`
+	SyncedHP      int           `json:"synced_hp,omitempty"` // characters: HP when the last action began (see HPChange)
+	MaxHP         int           `json:"max_hp"`
+	Attack        int           `json:"attack"`  // monsters: d20 bonus; characters: class attack stat
+	Defense       int           `json:"defense"` // number an attacker's total must meet
+	CritThreshold int           `json:"crit_threshold"`
+	XPReward      int           `json:"xp_reward,omitempty"`
+	KilledBy      string        `json:"killed_by,omitempty"` // monsters: combatant ID credited with the kill
+	Level         int           `json:"level,omitempty"`     // characters: weights the "level" XP split
+	Initiative    int           `json:"initiative"`
+	FirstStrike   int           `json:"first_strike,omitempty"` // bonus on this combatant's next attack, then cleared
+	HealsLeft     int           `json:"heals_left,omitempty"`
+	Riddle        bool          `json:"riddle,omitempty"`   // monster asks riddles (see ResolveRiddle)
+	Inflicts      string        `json:"inflicts,omitempty"` // monsters: status a solid hit leaves
+
+	Statuses []character.Status `json:"statuses,omitempty"` // tick at the top of each round (see tickStatuses)
+}
+
+// Alive reports whether the combatant can still act.
+func (c *Combatant) Alive() bool {
+	return c.HP > 0
+}
+
+// EncounterEvent is one resolved step of combat, in the order it happened.
+type EncounterEvent struct {
+	Round  int               `json:"round"`
+	Actor  string            `json:"actor"`            // combatant ID
+	Target string            `json:"target,omitempty"` // combatant ID
+	Type   string            `json:"type"`             // attack | counter | monster_attack | defeated | heal | item | status | flee | riddle | victory | reward | forfeit | defeat
+	Roll   *CombatRollResult `json:"roll,omitempty"`
+	Hit    bool              `json:"hit,omitempty"`
+	Damage int               `json:"damage,omitempty"` // negative for heals
+	XP     int               `json:"xp,omitempty"`     // awarded to Actor
+	Text   string            `json:"text"`
+}
+
+// Encounter is a server-authoritative fight between a party and a monster group.
+// All state lives here; the client only chooses actions.
+type Encounter struct {
+	ID        string           `json:"id"`
+	Group     string           `json:"group"`
+	Site      *EncounterSite   `json:"site,omitempty"`     // world building being fought over, if any
+	Seed      int64            `json:"seed"`               // with the same actions, replays the same rolls
+	Step      int              `json:"step"`               // RNG steps taken (see StepRNG): 1 for setup, +1 per action
+	XPSplit   string           `json:"xp_split"`           // how a victory's XP is shared (XPSplitEven, ...)
+	Loot      []string         `json:"loot,omitempty"`     // loot table rolled on victory (the building's)
+	Practice  bool             `json:"practice,omitempty"` // fight off the map: a victory pays no XP or loot
+	Rewards   *Rewards         `json:"rewards,omitempty"`
+	Status    EncounterStatus  `json:"status"`
+	Round     int              `json:"round"`
+	Turn      int              `json:"turn"` // index into Order of whoever acts next
+	Order     []*Combatant     `json:"order"`
+	Log       []EncounterEvent `json:"log"`
+	CreatedAt time.Time        `json:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
+
+// EncounterSite ties an encounter to a building on a persistent world map;
+// victory marks that building cleared.
+type EncounterSite struct {
+	WorldID  string `json:"world_id"`
+	TileID   string `json:"tile_id"`
+	Building int    `json:"building"` // index into the tile's buildings
+}
+
+// NewEncounter builds the turn order from a monster group and party, rolls
+// initiative, and resolves any monster turns that come before the first
+// character. Characters at 0 HP cannot join. All rolls come from seed.
+func NewEncounter(id string, seed int64, group resources.MonsterGroup, party []*character.Character) (*Encounter, error) {
+	if len(group.Monsters) == 0 {
+		return nil, fmt.Errorf("monster group %q has no monsters", group.Name)
+	}
+	if len(party) == 0 || len(party) > MaxPartySize {
+		return nil, fmt.Errorf("party must have 1-%d characters", MaxPartySize)
+	}
+
+	e := &Encounter{ID: id, Group: group.Name, Seed: seed, Status: EncounterActive, Round: 1, Log: []EncounterEvent{}}
+	rng := e.nextRNG()
+	for i, c := range party {
+		if c.HP <= 0 {
+			return nil, fmt.Errorf("%s is down and cannot fight", c.Name)
+		}
+		mods := ClassModifiers(c.Class)
+		stats := c.EffectiveStats()
+		e.Order = append(e.Order, &Combatant{
+			ID:            fmt.Sprintf("c%d", i),
+			Kind:          KindCharacter,
+			Name:          c.Name,
+			CharacterID:   c.ID,
+			Class:         c.Class,
+			Level:         c.Level,
+			HP:            c.HP,
+			SyncedHP:      c.HP,
+			MaxHP:         c.MaxHP,
+			Attack:        AttackStat(c.Class, stats),
+			Defense:       DefaultTarget,
+			CritThreshold: mods.CritThreshold(20),
+			Initiative:    D20(rng) + stats.Scouting + mods.InitiativeBonus,
+			FirstStrike:   mods.FirstStrikeBonus,
+			HealsLeft:     mods.CombatHeals,
+			Statuses:      append([]character.Status(nil), c.Statuses...),
+		})
+	}
+	for i, m := range group.Monsters {
+		e.Order = append(e.Order, &Combatant{
+			ID:            fmt.Sprintf("m%d", i),
+			Kind:          KindMonster,
+			Name:          m.Name,
+			HP:            m.HP,
+			MaxHP:         m.HP,
+			Attack:        m.Attack,
+			Defense:       m.Defense,
+			CritThreshold: 20,
+			XPReward:      m.XPReward,
+			Initiative:    D20(rng) + m.Attack,
+			Riddle:        m.HasRiddle,
+			Inflicts:      m.Inflicts,
+		})
+	}
+	// Highest initiative first; ties go to characters, then to listing order.
+	sort.SliceStable(e.Order, func(i, j int) bool {
+		a, b := e.Order[i], e.Order[j]
+		if a.Initiative != b.Initiative {
+			return a.Initiative > b.Initiative
+		}
+		return a.Kind == KindCharacter && b.Kind == KindMonster
+	})
+
+	now := time.Now().UTC()
+	e.CreatedAt, e.UpdatedAt = now, now
+	e.record(e.advance(rng)...)
+	return e, nil
+}
+
+// Current returns whoever acts next, or nil once the encounter is over.
+func (e *Encounter) Current() *Combatant {
+	if e.Status != EncounterActive || e.Turn >= len(e.Order) {
+		return nil
+	}
+	return e.Order[e.Turn]
+}
+
+// Combatant looks up a combatant by ID.
+func (e *Encounter) Combatant(id string) *Combatant {
+	for _, c := range e.Order {
+		if c.ID == id {
+			return c
+		}
+	}
+	return nil
+}
+
+// Fighter returns characterID's combatant, or nil if they aren't in the fight.
+func (e *Encounter) Fighter(characterID string) *Combatant {
+	for _, c := range e.Order {
+		if c.Kind == KindCharacter && c.CharacterID == characterID {
+			return c
+		}
+	}
+	return nil
+}
+
+// Act resolves characterID's action, then every monster turn up to the next
+// character's turn (or the end of the encounter). targetID is optional for
+// attacks; the first living monster is used when empty. The returned events
+// are also appended to e.Log.
+func (e *Encounter) Act(characterID, action, targetID string) ([]EncounterEvent, error) {
+	if e.Status != EncounterActive {
+		return nil, ErrEncounterOver
+	}
+	actor := e.Current()
+	if actor == nil || actor.Kind != KindCharacter || actor.CharacterID != characterID {
+		return nil, ErrNotYourTurn
+	}
+
+	e.beginAction()
+	rng := e.nextRNG()
+	var events []EncounterEvent
+	switch action {
+	case ActionAttack:
+		target, err := e.monsterTarget(targetID)
+		if err != nil {
+			return nil, err
+		}
+		events = e.characterAttack(rng, actor, target)
+	case ActionHeal:
+		if actor.HealsLeft <= 0 {
+			return nil, fmt.Errorf("%w: %s has no heals left this fight", ErrNoAbility, actor.Name)
+		}
+		if actor.HP >= actor.MaxHP {
+			return nil, fmt.Errorf("%w: %s is already at full HP", ErrNoAbility, actor.Name)
+		}
+		actor.HealsLeft--
+		healed := min(actor.MaxHP-actor.HP, rng.Intn(6)+1+medicHealDice)
+		actor.HP += healed
+		events = append(events, EncounterEvent{
+			Round: e.Round, Actor: actor.ID, Target: actor.ID, Type: "heal", Damage: -healed,
+			Text: fmt.Sprintf("%s patches up for %d. %s has %d/%d HP.", actor.Name, healed, actor.Name, actor.HP, actor.MaxHP),
+		})
+	case ActionFlee:
+		e.Status = EncounterFled
+		obs.EncountersFinishedTotal.WithLabelValues(string(EncounterFled)).Inc()
+		events = append(events, EncounterEvent{
+			Round: e.Round, Actor: actor.ID, Type: "flee",
+			Text: fmt.Sprintf("%s calls the retreat. The party flees.", actor.Name),
+		})
+	default:
+		return nil, fmt.Errorf("%w %q (want %s, %s, or %s)", ErrUnknownAction, action, ActionAttack, ActionHeal, ActionFlee)
+	}
+
+	e.Turn++
+	events = append(events, e.advance(rng)...)
+	e.record(events...)
+	return events, nil
+}
+
+// HPChange is how much a character combatant's HP moved since the last
+// action began. The server applies it to the character row rather than
+// copying HP across, so healing done outside the fight is not overwritten.
+func (c *Combatant) HPChange() int {
+	return c.HP - c.SyncedHP
+}
+
+// beginAction marks the party's current HP as synced; every action entry
+// point calls it once the action is known to be legal.
+func (e *Encounter) beginAction() {
+	for _, c := range e.Order {
+		if c.Kind == KindCharacter {
+			c.SyncedHP = c.HP
+		}
+	}
+}
+
+// nextRNG returns the generator for the current step and advances it.
+func (e *Encounter) nextRNG() *rand.Rand {
+	rng := StepRNG(e.Seed, e.Step)
+	e.Step++
+	return rng
+}
+
+func (e *Encounter) record(events ...EncounterEvent) {
+	e.Log = append(e.Log, events...)
+	e.UpdatedAt = time.Now().UTC()
+}
+
+func (e *Encounter) monsterTarget(id string) (*Combatant, error) {
+	if id == "" {
+		for _, c := range e.Order {
+			if c.Kind == KindMonster && c.Alive() {
+				return c, nil
+			}
+		}
+		return nil, ErrInvalidTarget
+	}
+	c := e.Combatant(id)
+	if c == nil || c.Kind != KindMonster || !c.Alive() {
+		return nil, ErrInvalidTarget
+	}
+	return c, nil
+}
+
+// characterAttack rolls against the monster's Defense. A hit deals 1d6
+// (2d6 on a crit); a miss draws a counterattack from the target.
+func (e *Encounter) characterAttack(rng *rand.Rand, actor, target *Combatant) []EncounterEvent {
+	roll := Roll(rng, CombatRollRequest{StatValue: actor.Attack, Bonus: actor.FirstStrike + statusAttackBonus(actor), Target: target.Defense}, actor.CritThreshold)
+	actor.FirstStrike = 0
+	hit := roll.Outcome == OutcomeSuccess || roll.Outcome == OutcomeCritSuccess
+
+	if !hit {
+		dmg := 1 + target.Attack/3
+		if roll.Outcome == OutcomeCritFailure {
+			dmg = max(2, target.Attack)
+		}
+		actor.HP = max(0, actor.HP-dmg)
+		return []EncounterEvent{
+			{Round: e.Round, Actor: actor.ID, Target: target.ID, Type: "attack", Roll: &roll,
+				Text: fmt.Sprintf("%s misses %s (%d vs defense %d).", actor.Name, target.Name, roll.Total, target.Defense)},
+			{Round: e.Round, Actor: target.ID, Target: actor.ID, Type: "counter", Hit: true, Damage: dmg,
+				Text: fmt.Sprintf("%s counters for %d. %s has %d/%d HP.", target.Name, dmg, actor.Name, actor.HP, actor.MaxHP)},
+		}
+	}
+
+	dmg := rng.Intn(6) + 1
+	if roll.Outcome == OutcomeCritSuccess {
+		dmg += rng.Intn(6) + 1
+	}
+	target.HP = max(0, target.HP-dmg)
+	events := []EncounterEvent{{
+		Round: e.Round, Actor: actor.ID, Target: target.ID, Type: "attack", Roll: &roll, Hit: true, Damage: dmg,
+		Text: fmt.Sprintf("%s hits %s for %d. %s has %d/%d HP.", actor.Name, target.Name, dmg, target.Name, target.HP, target.MaxHP),
+	}}
+	if !target.Alive() {
+		events = append(events, e.defeated(actor.ID, target))
+	}
+	return events
+}
+
+// monsterAttack targets the living character with the lowest HP:
+// d20 + Attack vs the character's Defense. A hit on a natural InflictRoll or
+// better leaves the monster's status, if it has one.
+func (e *Encounter) monsterAttack(rng *rand.Rand, m *Combatant) []EncounterEvent {
+	var target *Combatant
+	for _, c := range e.Order {
+		if c.Kind == KindCharacter && c.Alive() && (target == nil || c.HP < target.HP) {
+			target = c
+		}
+	}
+	if target == nil {
+		return nil
+	}
+	d20 := D20(rng)
+	total := d20 + m.Attack + statusAttackBonus(m)
+	if d20 == 1 || (d20 != 20 && total < target.Defense) {
+		return []EncounterEvent{{
+			Round: e.Round, Actor: m.ID, Target: target.ID, Type: "monster_attack",
+			Text: fmt.Sprintf("%s misses %s (%d vs defense %d).", m.Name, target.Name, total, target.Defense),
+		}}
+	}
+	dmg := 1 + m.Attack/3
+	if d20 == 20 {
+		dmg = max(2, m.Attack)
+	}
+	target.HP = max(0, target.HP-dmg)
+	text := fmt.Sprintf("%s hits %s for %d. %s has %d/%d HP.", m.Name, target.Name, dmg, target.Name, target.HP, target.MaxHP)
+	if !target.Alive() {
+		text += " " + target.Name + " is down!"
+	} else if m.Inflicts != "" && d20 >= InflictRoll {
+		target.Statuses = character.AddStatus(target.Statuses, character.Status{Name: m.Inflicts, Rounds: InflictRounds, Source: m.ID})
+		text += fmt.Sprintf(" %s is %s.", target.Name, m.Inflicts)
+	}
+	return []EncounterEvent{{
+		Round: e.Round, Actor: m.ID, Target: target.ID, Type: "monster_attack", Hit: true, Damage: dmg, Text: text,
+	}}
+}
+
+// advance runs monster turns until a living character is up or the fight ends.
+func (e *Encounter) advance(rng *rand.Rand) []EncounterEvent {
+	var events []EncounterEvent
+	for e.Status == EncounterActive {
+		if evs, over := e.checkEnd(rng); over {
+			events = append(events, evs...)
+			break
+		}
+		if e.Turn >= len(e.Order) {
+			e.Turn = 0
+			e.Round++
+			events = append(events, e.tickStatuses()...)
+			continue // statuses may have ended the fight
+		}
+		c := e.Order[e.Turn]
+		if !c.Alive() {
+			e.Turn++
+			continue
+		}
+		if c.Kind == KindCharacter {
+			break
+		}
+		events = append(events, e.monsterAttack(rng, c)...)
+		e.Turn++
+	}
+	return events
+}
+
+// checkEnd settles a finished fight: a victory pays out (see payOut), a
+// defeat leaves everyone on 1 HP.
+func (e *Encounter) checkEnd(rng *rand.Rand) ([]EncounterEvent, bool) {
+	var chars, monsters int
+	for _, c := range e.Order {
+		if !c.Alive() {
+			continue
+		}
+		if c.Kind == KindCharacter {
+			chars++
+		} else {
+			monsters++
+		}
+	}
+	switch {
+	case monsters == 0:
+		e.Status = EncounterVictory
+	case chars == 0:
+		e.Status = EncounterDefeat
+	default:
+		return nil, false
+	}
+	obs.EncountersFinishedTotal.WithLabelValues(string(e.Status)).Inc()
+	text := fmt.Sprintf("%s is cleared.", e.Group)
+	if e.Status == EncounterDefeat {
+		// Nobody dies outright: the party drags itself out on 1 HP.
+		for _, c := range e.Order {
+			if c.Kind == KindCharacter {
+				c.HP = 1
+			}
+		}
+		text = "The whole party is down. You crawl away, barely alive."
+	}
+	events := []EncounterEvent{{Round: e.Round, Type: string(e.Status), Text: text}}
+	if e.Status == EncounterVictory {
+		events = append(events, e.payOut(rng)...)
+	}
+	return events, true
+}
+
+// AttackStat picks the stat a class attacks with (mirrors the original client
+// rules). Pass effective stats so equipment and effects count.
+func AttackStat(class string, s character.CharacterStats) int {
+	switch class {
+	case "Gunslinger":
+		return s.Marksmanship
+	case "Medic":
+		return s.Stamina
+	case "Scavenger", "Conspiracy Theorist":
+		return s.Scouting
+	case "Wrench Witch", "Street Pharmacist":
+		return s.Crafting
+	case "Hoarder":
+		return s.Salvaging
+	default:
+		return s.Strength
+	}
+}
@@ -0,0 +1,123 @@
+package game
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// EncounterStore persists encounters as JSON rows in the game's SQLite database.
+type EncounterStore struct {
+	db *sql.DB
+	mu sync.Mutex // serialises Create and Update so two requests cannot race on one encounter or character
+}
+
+// NewEncounterStore creates the encounters table if needed.
+func NewEncounterStore(db *sql.DB) (*EncounterStore, error) {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS encounters (
+			id          TEXT PRIMARY KEY,
+			status      TEXT NOT NULL,
+			state_json  TEXT NOT NULL,
+			created_at  DATETIME NOT NULL,
+			updated_at  DATETIME NOT NULL
+		)
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("create encounters table: %w", err)
+	}
+	return &EncounterStore{db: db}, nil
+}
+
+// Save creates or updates an encounter (upsert).
+func (s *EncounterStore) Save(ctx context.Context, e *Encounter) error {
+	state, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("marshal encounter: %w", err)
+	}
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO encounters (id, status, state_json, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			status=excluded.status, state_json=excluded.state_json, updated_at=excluded.updated_at
+	`, e.ID, string(e.Status), string(state), e.CreatedAt, time.Now().UTC())
+	return err
+}
+
+// Create saves a new encounter unless one of its characters is already in
+// an active one. Each fight writes its HP and statuses back to the party, so
+// a character in two at once could spend one bandage in both.
+func (s *EncounterStore) Create(ctx context.Context, e *Encounter) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range e.Order {
+		if c.Kind != KindCharacter {
+			continue
+		}
+		fighting, err := s.ActiveFor(ctx, c.CharacterID)
+		if err != nil {
+			return err
+		}
+		if fighting != "" {
+			return fmt.Errorf("%w: %s is fighting in %s", ErrAlreadyFighting, c.Name, fighting)
+		}
+	}
+	return s.Save(ctx, e)
+}
+
+// Load retrieves an encounter by ID.
+// Returns (nil, nil) if not found.
+func (s *EncounterStore) Load(ctx context.Context, id string) (*Encounter, error) {
+	var state string
+	err := s.db.QueryRowContext(ctx, `SELECT state_json FROM encounters WHERE id = ?`, id).Scan(&state)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scan encounter: %w", err)
+	}
+	var e Encounter
+	if err := json.Unmarshal([]byte(state), &e); err != nil {
+		return nil, fmt.Errorf("unmarshal encounter: %w", err)
+	}
+	return &e, nil
+}
+
+// ActiveFor returns the ID of an active encounter characterID is fighting
+// in, or "" if there is none.
+func (s *EncounterStore) ActiveFor(ctx context.Context, characterID string) (string, error) {
+	var id string
+	err := s.db.QueryRowContext(ctx, `
+		SELECT e.id FROM encounters e, json_each(e.state_json, '$.order') o
+		WHERE e.status = ? AND json_extract(o.value, '$.character_id') = ?
+		LIMIT 1
+	`, string(EncounterActive), characterID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("find active encounter: %w", err)
+	}
+	return id, nil
+}
+
+// Update loads an encounter, applies fn, and saves it if fn succeeds.
+// Returns (nil, nil) if the encounter does not exist.
+func (s *EncounterStore) Update(ctx context.Context, id string, fn func(*Encounter) error) (*Encounter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, err := s.Load(ctx, id)
+	if err != nil || e == nil {
+		return nil, err
+	}
+	if err := fn(e); err != nil {
+		return nil, err
+	}
+	if err := s.Save(ctx, e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
@@ -0,0 +1,246 @@
+package game
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"m20-game/internal/character"
+	"m20-game/internal/resources"
+)
+
+func newParty(t *testing.T, classes ...string) []*character.Character {
+	t.Helper()
+	party := make([]*character.Character, 0, len(classes))
+	for i, class := range classes {
+		c, err := character.Generate(character.GenerateRequest{Name: class, Class: class})
+		if err != nil {
+			t.Fatalf("generate %s: %v", class, err)
+		}
+		c.ID = string(rune('a' + i))
+		party = append(party, c)
+	}
+	return party
+}
+
+func zombies(n int) resources.MonsterGroup {
+	g := resources.MonsterGroup{Name: "Test Zombies"}
+	for i := 0; i < n; i++ {
+		g.Monsters = append(g.Monsters, *resources.MonsterByName("Zombie"))
+	}
+	return g
+}
+
+// fight attacks the first living monster on every character turn until the
+// encounter ends.
+func fight(t *testing.T, e *Encounter) {
+	t.Helper()
+	for turns := 0; e.Status == EncounterActive; turns++ {
+		if turns > 200 {
+			t.Fatalf("Expected the fight to end, still going after %d turns", turns)
+		}
+		actor := e.Current()
+		if _, err := e.Act(actor.CharacterID, ActionAttack, ""); err != nil {
+			t.Fatalf("Act failed: %v", err)
+		}
+	}
+}
+
+func TestEncounter_ReplaysFromSeed(t *testing.T) {
+	run := func() *Encounter {
+		e, err := NewEncounter("e1", 42, zombies(2), newParty(t, "Brawler", "Gunslinger"))
+		if err != nil {
+			t.Fatalf("NewEncounter failed: %v", err)
+		}
+		e.XPSplit = XPSplitEven
+		e.Loot = []string{"Bandage", "Rope", "Duct Tape"}
+		fight(t, e)
+		return e
+	}
+	a, b := run(), run()
+
+	if a.Status == EncounterActive {
+		t.Fatal("Expected the fight to be over")
+	}
+	if a.Status != b.Status || a.Round != b.Round || a.Step != b.Step {
+		t.Errorf("Expected identical runs, got %s/round %d/step %d vs %s/round %d/step %d",
+			a.Status, a.Round, a.Step, b.Status, b.Round, b.Step)
+	}
+	if len(a.Log) != len(b.Log) {
+		t.Fatalf("Expected identical logs, got %d vs %d events", len(a.Log), len(b.Log))
+	}
+	for i := range a.Log {
+		if a.Log[i].Text != b.Log[i].Text {
+			t.Errorf("Event %d differs: %q vs %q", i, a.Log[i].Text, b.Log[i].Text)
+		}
+	}
+	if !reflect.DeepEqual(a.Rewards, b.Rewards) {
+		t.Errorf("Expected identical rewards, got %+v vs %+v", a.Rewards, b.Rewards)
+	}
+}
+
+func TestEncounter_ActErrors(t *testing.T) {
+	newFight := func() *Encounter {
+		e, err := NewEncounter("e1", 7, zombies(1), newParty(t, "Brawler"))
+		if err != nil {
+			t.Fatalf("NewEncounter failed: %v", err)
+		}
+		return e
+	}
+	over := newFight()
+	if _, err := over.Act("a", ActionFlee, ""); err != nil {
+		t.Fatalf("flee failed: %v", err)
+	}
+
+	cases := []struct {
+		name        string
+		e           *Encounter
+		characterID string
+		action      string
+		target      string
+		want        error
+	}{
+		{"someone else's turn", newFight(), "nobody", ActionAttack, "", ErrNotYourTurn},
+		{"unknown action", newFight(), "a", "dance", "", ErrUnknownAction},
+		{"unknown target", newFight(), "a", ActionAttack, "m9", ErrInvalidTarget},
+		{"party member as target", newFight(), "a", ActionAttack, "c0", ErrInvalidTarget},
+		{"heal without the ability", newFight(), "a", ActionHeal, "", ErrNoAbility},
+		{"fight already over", over, "a", ActionAttack, "", ErrEncounterOver},
+	}
+	for _, tc := range cases {
+		if _, err := tc.e.Act(tc.characterID, tc.action, tc.target); !errors.Is(err, tc.want) {
+			t.Errorf("%s: got error %v, want %v", tc.name, err, tc.want)
+		}
+	}
+	if over.Status != EncounterFled {
+		t.Errorf("Expected flee to end the fight as fled, got %s", over.Status)
+	}
+}
+
+func TestEncounter_HPChange(t *testing.T) {
+	e, err := NewEncounter("e1", 3, zombies(3), newParty(t, "Scavenger"))
+	if err != nil {
+		t.Fatalf("NewEncounter failed: %v", err)
+	}
+	c := e.Fighter("a")
+	if c == nil {
+		t.Fatal("Expected the character in the turn order")
+	}
+	before := c.HP
+	if got := c.HPChange(); got != c.HP-c.SyncedHP {
+		t.Errorf("HPChange() = %d, want %d", got, c.HP-c.SyncedHP)
+	}
+	for e.Status == EncounterActive && c.HP == before {
+		before = c.HP
+		if _, err := e.Act("a", ActionAttack, ""); err != nil {
+			t.Fatalf("Act failed: %v", err)
+		}
+		// Each action starts from the HP the last one left.
+		if got, want := c.HPChange(), c.HP-before; got != want {
+			t.Errorf("HPChange() = %d after one action, want %d", got, want)
+		}
+	}
+}
+
+func TestCheckEnd(t *testing.T) {
+	cases := []struct {
+		name       string
+		charHP     []int
+		monsterHP  []int
+		practice   bool
+		wantOver   bool
+		wantStatus EncounterStatus
+		wantXP     int // total XP paid out
+	}{
+		{"both sides standing", []int{5}, []int{3}, false, false, EncounterActive, 0},
+		{"every monster down", []int{5, 0}, []int{0, 0}, false, true, EncounterVictory, 100},
+		{"practice win pays nothing", []int{5}, []int{0}, true, true, EncounterVictory, 0},
+		{"every character down", []int{0, 0}, []int{4}, false, true, EncounterDefeat, 0},
+	}
+	for _, tc := range cases {
+		e := &Encounter{Group: "Test", Status: EncounterActive, XPSplit: XPSplitEven, Practice: tc.practice}
+		for i, hp := range tc.charHP {
+			e.Order = append(e.Order, &Combatant{ID: "c" + string(rune('0'+i)), Kind: KindCharacter, Name: "C", HP: hp, MaxHP: 10})
+		}
+		for i, hp := range tc.monsterHP {
+			e.Order = append(e.Order, &Combatant{ID: "m" + string(rune('0'+i)), Kind: KindMonster, Name: "M", HP: hp, MaxHP: 8, XPReward: 50})
+		}
+
+		events, over := e.checkEnd(NewRNG(1))
+		if over != tc.wantOver || e.Status != tc.wantStatus {
+			t.Errorf("%s: got over=%v status=%s, want over=%v status=%s", tc.name, over, e.Status, tc.wantOver, tc.wantStatus)
+			continue
+		}
+		xp := 0
+		for _, ev := range events {
+			xp += ev.XP
+		}
+		if xp != tc.wantXP {
+			t.Errorf("%s: paid %d XP, want %d", tc.name, xp, tc.wantXP)
+		}
+		if tc.wantStatus == EncounterVictory && e.Rewards == nil {
+			t.Errorf("%s: expected rewards recorded on a victory", tc.name)
+		}
+		if tc.wantStatus == EncounterDefeat {
+			for _, c := range e.Order {
+				if c.Kind == KindCharacter && c.HP != 1 {
+					t.Errorf("%s: expected a wiped party left on 1 HP, got %d", tc.name, c.HP)
+				}
+			}
+		}
+	}
+}
+
+func TestPayOut_XPSplit(t *testing.T) {
+	order := func() []*Combatant {
+		return []*Combatant{
+			{ID: "c0", Kind: KindCharacter, HP: 5, Level: 1},
+			{ID: "c1", Kind: KindCharacter, HP: 5, Level: 3},
+			{ID: "c2", Kind: KindCharacter, HP: 0, Level: 5}, // downed: no share
+			{ID: "m0", Kind: KindMonster, XPReward: 60, KilledBy: "c1"},
+			{ID: "m1", Kind: KindMonster, XPReward: 41, KilledBy: "c2"},
+		}
+	}
+	cases := []struct {
+		rule string
+		want map[string]int
+	}{
+		{XPSplitEven, map[string]int{"c0": 51, "c1": 50}},
+		{XPSplitKiller, map[string]int{"c0": 21, "c1": 80}},
+		{XPSplitLevel, map[string]int{"c0": 26, "c1": 75}},
+		{"bogus", map[string]int{"c0": 51, "c1": 50}}, // unknown rules fall back to even
+	}
+	for _, tc := range cases {
+		e := &Encounter{Order: order(), XPSplit: tc.rule, Loot: []string{"Bandage", "Rope"}}
+		events := e.payOut(NewRNG(5))
+		if !reflect.DeepEqual(e.Rewards.XP, tc.want) {
+			t.Errorf("%s: XP = %v, want %v", tc.rule, e.Rewards.XP, tc.want)
+		}
+		if len(events) != len(tc.want) {
+			t.Errorf("%s: expected one reward event per paid survivor, got %d", tc.rule, len(events))
+		}
+		if n := len(e.Rewards.Loot); n < 1 || n > 2 {
+			t.Errorf("%s: expected 1-2 loot items from a 2-item table, got %v", tc.rule, e.Rewards.Loot)
+		}
+	}
+}
+
+func TestRewards_LeaveBehind(t *testing.T) {
+	r := &Rewards{
+		Drops: []LootDrop{
+			{CharacterID: "a", Item: "Bandage"},
+			{CharacterID: "b", Item: "Bandage"},
+			{CharacterID: "a", Item: "Rope"},
+		},
+		LeftBehind: []string{"Wire"},
+	}
+	r.LeaveBehind("a", []string{"Bandage"})
+
+	wantDrops := []LootDrop{{CharacterID: "b", Item: "Bandage"}, {CharacterID: "a", Item: "Rope"}}
+	if !reflect.DeepEqual(r.Drops, wantDrops) {
+		t.Errorf("Drops = %+v, want %+v", r.Drops, wantDrops)
+	}
+	if !reflect.DeepEqual(r.LeftBehind, []string{"Wire", "Bandage"}) {
+		t.Errorf("LeftBehind = %v, want [Wire Bandage]", r.LeftBehind)
+	}
+}
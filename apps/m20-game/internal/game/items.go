@@ -0,0 +1,203 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"m20-game/internal/character"
+	"m20-game/internal/obs"
+	"m20-game/internal/resources"
+)
+
+const (
+	// InflictRoll is the natural d20 a monster's hit needs to leave its
+	// status (resources.Monster.Inflicts) on the target.
+	InflictRoll = 15
+	// InflictRounds is how long a monster-inflicted status lasts.
+	InflictRounds = 3
+)
+
+var (
+	ErrNotUsable   = errors.New("item cannot be used")
+	ErrNotInCombat = errors.New("thrown items only work in an encounter")
+	ErrNoEffect    = errors.New("item would do nothing right now")
+)
+
+// ItemUse is the outcome of using an item outside an encounter.
+type ItemUse struct {
+	Item    string   `json:"item"`
+	Healed  int      `json:"healed"`
+	Cured   []string `json:"cured,omitempty"`
+	Applied string   `json:"applied,omitempty"`
+}
+
+// UseItem applies a consumable to c outside combat. Thrown items are refused.
+// The caller removes the item from the inventory.
+func UseItem(c *character.Character, item string) (ItemUse, error) {
+	eff, ok := resources.ItemEffectFor(item)
+	if !ok {
+		return ItemUse{}, ErrNotUsable
+	}
+	if eff.Thrown {
+		return ItemUse{}, ErrNotInCombat
+	}
+	if !itemHelps(eff, c.HP, c.MaxHP, c.Statuses) {
+		return ItemUse{}, ErrNoEffect
+	}
+	use := ItemUse{Item: item, Healed: c.Heal(eff.Heal)}
+	c.Statuses, use.Cured = character.RemoveStatuses(c.Statuses, eff.Cures)
+	if eff.Applies != "" {
+		c.Statuses = character.AddStatus(c.Statuses, character.Status{Name: eff.Applies, Rounds: eff.Rounds})
+		use.Applied = eff.Applies
+	}
+	return use, nil
+}
+
+// itemHelps reports whether a non-thrown item changes anything: it heals a
+// wounded user, cures a status they have, or applies one.
+func itemHelps(eff resources.ItemEffect, hp, maxHP int, statuses []character.Status) bool {
+	if eff.Applies != "" || (eff.Heal > 0 && hp < maxHP) {
+		return true
+	}
+	for _, s := range statuses {
+		if slices.Contains(eff.Cures, s.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// UseItem spends characterID's turn on an item: a consumable on themselves,
+// or a thrown item at every living monster. The caller checks and removes the
+// inventory item. The returned events are also appended to e.Log.
+func (e *Encounter) UseItem(characterID, item string) ([]EncounterEvent, error) {
+	if e.Status != EncounterActive {
+		return nil, ErrEncounterOver
+	}
+	actor := e.Current()
+	if actor == nil || actor.Kind != KindCharacter || actor.CharacterID != characterID {
+		return nil, ErrNotYourTurn
+	}
+	eff, ok := resources.ItemEffectFor(item)
+	if !ok {
+		return nil, ErrNotUsable
+	}
+	if !eff.Thrown && !itemHelps(eff, actor.HP, actor.MaxHP, actor.Statuses) {
+		return nil, ErrNoEffect
+	}
+
+	e.beginAction()
+	rng := e.nextRNG()
+	var events []EncounterEvent
+	if eff.Thrown {
+		events = append(events, EncounterEvent{
+			Round: e.Round, Actor: actor.ID, Type: "item",
+			Text: fmt.Sprintf("%s throws a %s.", actor.Name, item),
+		})
+		for _, m := range e.Order {
+			if m.Kind != KindMonster || !m.Alive() {
+				continue
+			}
+			m.HP = max(0, m.HP-eff.Damage)
+			text := fmt.Sprintf("%s takes %d. %s has %d/%d HP.", m.Name, eff.Damage, m.Name, m.HP, m.MaxHP)
+			if eff.Inflicts != "" && m.Alive() {
+				m.Statuses = character.AddStatus(m.Statuses, character.Status{Name: eff.Inflicts, Rounds: eff.Rounds, Source: actor.ID})
+				text += fmt.Sprintf(" %s is %s.", m.Name, eff.Inflicts)
+			}
+			events = append(events, EncounterEvent{
+				Round: e.Round, Actor: actor.ID, Target: m.ID, Type: "item", Hit: true, Damage: eff.Damage, Text: text,
+			})
+			if !m.Alive() {
+				events = append(events, e.defeated(actor.ID, m))
+			}
+		}
+	} else {
+		healed := min(actor.MaxHP-actor.HP, eff.Heal)
+		actor.HP += healed
+		var cured []string
+		actor.Statuses, cured = character.RemoveStatuses(actor.Statuses, eff.Cures)
+		text := fmt.Sprintf("%s uses %s", actor.Name, item)
+		if healed > 0 {
+			text += fmt.Sprintf(" and heals %d (%d/%d HP)", healed, actor.HP, actor.MaxHP)
+		}
+		if len(cured) > 0 {
+			text += "; no longer " + strings.Join(cured, " or ")
+		}
+		if eff.Applies != "" {
+			actor.Statuses = character.AddStatus(actor.Statuses, character.Status{Name: eff.Applies, Rounds: eff.Rounds})
+			text += fmt.Sprintf("; %s for %d rounds", eff.Applies, eff.Rounds)
+		}
+		events = append(events, EncounterEvent{
+			Round: e.Round, Actor: actor.ID, Target: actor.ID, Type: "item", Damage: -healed, Text: text + ".",
+		})
+	}
+
+	e.Turn++
+	events = append(events, e.advance(rng)...)
+	e.record(events...)
+	return events, nil
+}
+
+// tickStatuses runs every living combatant's statuses at the top of a round:
+// damage over time first, then the countdown. A monster killed by a status
+// counts as a kill for whoever inflicted it.
+func (e *Encounter) tickStatuses() []EncounterEvent {
+	var events []EncounterEvent
+	for _, c := range e.Order {
+		if !c.Alive() || len(c.Statuses) == 0 {
+			continue
+		}
+		kept := c.Statuses[:0]
+		for _, s := range c.Statuses {
+			def := resources.StatusConditions()[s.Name]
+			if def.DamagePerRound > 0 && c.Alive() {
+				c.HP = max(0, c.HP-def.DamagePerRound)
+				text := fmt.Sprintf("%s takes %d from %s. %s has %d/%d HP.", c.Name, def.DamagePerRound, s.Name, c.Name, c.HP, c.MaxHP)
+				if !c.Alive() && c.Kind == KindCharacter {
+					text += " " + c.Name + " is down!"
+				}
+				events = append(events, EncounterEvent{
+					Round: e.Round, Actor: c.ID, Target: c.ID, Type: "status", Hit: true, Damage: def.DamagePerRound, Text: text,
+				})
+				if !c.Alive() && c.Kind == KindMonster {
+					events = append(events, e.defeated(s.Source, c))
+				}
+			}
+			if s.Rounds--; s.Rounds > 0 {
+				kept = append(kept, s)
+			} else if c.Alive() {
+				events = append(events, EncounterEvent{
+					Round: e.Round, Actor: c.ID, Target: c.ID, Type: "status",
+					Text: fmt.Sprintf("%s is no longer %s.", c.Name, s.Name),
+				})
+			}
+		}
+		c.Statuses = kept
+	}
+	return events
+}
+
+// statusAttackBonus sums the attack modifiers of c's statuses.
+func statusAttackBonus(c *Combatant) int {
+	bonus := 0
+	for _, s := range c.Statuses {
+		bonus += resources.StatusConditions()[s.Name].AttackBonus
+	}
+	return bonus
+}
+
+// defeated records a monster going down, credited to killerID (a character
+// combatant ID, or "" for nobody). XP is paid when the fight is won.
+func (e *Encounter) defeated(killerID string, m *Combatant) EncounterEvent {
+	obs.MonstersDefeatedTotal.WithLabelValues(m.Name).Inc()
+	killer := e.Combatant(killerID)
+	if killer == nil || killer.Kind != KindCharacter {
+		return EncounterEvent{Round: e.Round, Actor: m.ID, Target: m.ID, Type: "defeated",
+			Text: fmt.Sprintf("%s is down!", m.Name)}
+	}
+	m.KilledBy = killer.ID
+	return EncounterEvent{Round: e.Round, Actor: killer.ID, Target: m.ID, Type: "defeated",
+		Text: fmt.Sprintf("%s is down! %s gets the kill.", m.Name, killer.Name)}
+}
@@ -4,4 +4,5 @@ package game
 type Land struct {
 	Tiles     []Tile `json:"tiles"`
 	TileCount int    `json:"tile_count"`
+	Seed      int64  `json:"seed"` // GenerateLand(seed, tile_count) rebuilds this land
 }
@@ -14,9 +14,9 @@ type MonsterEncounter struct {
 }
 
 // RandomEncounter picks a random monster for a standard encounter.
-func RandomEncounter(tileID string) MonsterEncounter {
+func RandomEncounter(rng *rand.Rand, tileID string) MonsterEncounter {
 	monsters := resources.Monsters()
-	m := monsters[rand.Intn(len(monsters))]
+	m := monsters[rng.Intn(len(monsters))]
 	return MonsterEncounter{
 		Monster:   m,
 		HasRiddle: m.HasRiddle,
@@ -0,0 +1,230 @@
+package game
+
+import (
+	"container/heap"
+	"errors"
+	"math/rand"
+	"time"
+
+	"m20-game/internal/resources"
+)
+
+// GridWidth is the width of the map grid; tiles fill it row by row.
+const GridWidth = 5
+
+// MoveBudget is the most movement points one move can spend. Entering a tile
+// costs its terrain's MoveCost.
+const MoveBudget = 6
+
+var (
+	ErrNoPath     = errors.New("no path to that tile")
+	ErrTooFar     = errors.New("tile is too far for one move")
+	ErrPartySplit = errors.New("party members must start on the same tile")
+)
+
+// GridPos returns the (x, y) of the tile placed in slot.
+func GridPos(slot int) (x, y int) {
+	return slot % GridWidth, slot / GridWidth
+}
+
+// Adjacent reports whether two tiles share an edge (no diagonals).
+func (t Tile) Adjacent(o Tile) bool {
+	dx, dy := t.X-o.X, t.Y-o.Y
+	return dx*dx+dy*dy == 1
+}
+
+// MoveCost is the movement points needed to enter the tile.
+func (t Tile) MoveCost() int {
+	return max(1, t.Type.MoveCost)
+}
+
+// Safe reports whether travel through the tile can be ambushed: once every
+// building is cleared, nothing is left to jump the party.
+func (t Tile) Safe() bool {
+	for _, b := range t.Buildings {
+		if !b.Cleared {
+			return false
+		}
+	}
+	return len(t.Buildings) > 0
+}
+
+// Path finds the cheapest route between two placed tiles (Dijkstra over
+// edge-adjacent tiles). The result starts at from and ends at to; cost counts
+// every tile entered after from.
+func (w *World) Path(from, to string) ([]string, int, error) {
+	start, goal := w.Tile(from), w.Tile(to)
+	if start == nil || goal == nil {
+		return nil, 0, ErrUnknownTile
+	}
+	dist := map[string]int{from: 0}
+	prev := map[string]string{}
+	pq := &pathQueue{{id: from}}
+	for pq.Len() > 0 {
+		cur := heap.Pop(pq).(pathNode)
+		if cur.id == to {
+			break
+		}
+		if cur.cost > dist[cur.id] {
+			continue // stale entry
+		}
+		t := w.Tile(cur.id)
+		for _, n := range w.Tiles {
+			if !t.Adjacent(*n) {
+				continue
+			}
+			cost := cur.cost + n.MoveCost()
+			if d, seen := dist[n.ID]; seen && d <= cost {
+				continue
+			}
+			dist[n.ID] = cost
+			prev[n.ID] = cur.id
+			heap.Push(pq, pathNode{id: n.ID, cost: cost})
+		}
+	}
+	cost, ok := dist[to]
+	if !ok {
+		return nil, 0, ErrNoPath
+	}
+	path := []string{to}
+	for id := to; id != from; {
+		id = prev[id]
+		path = append([]string{id}, path...)
+	}
+	return path, cost, nil
+}
+
+// MoveResult describes a party move.
+type MoveResult struct {
+	From    string                  `json:"from"`
+	To      string                  `json:"to"` // where the party ended up (the ambush tile if ambushed)
+	Path    []string                `json:"path"`
+	Cost    int                     `json:"cost"`
+	Budget  int                     `json:"budget"`
+	Vehicle string                  `json:"vehicle,omitempty"` // what the party drove, if anything
+	Ambush  *resources.MonsterGroup `json:"ambush,omitempty"`  // travel encounter that stopped the move
+
+	AmbushSeed int64 `json:"ambush_seed,omitempty"` // seeds the ambush encounter
+}
+
+// Move walks characterIDs together to tileID along the cheapest path.
+// Characters not yet on the map join at the first tile, or wherever the
+// rest of the party stands. Every tile entered on the way may spring a
+// travel encounter (1-in-20 per point of Danger), which ends the move there.
+func (w *World) Move(characterIDs []string, tileID string) (MoveResult, error) {
+	return w.travel(characterIDs, tileID, MoveBudget, "")
+}
+
+// Drive is Move in a vehicle: the budget is VehicleBudget and the whole party
+// has to fit. Fuel and condition are the caller's to check (see CanDrive);
+// ambushes still stop the vehicle.
+func (w *World) Drive(characterIDs []string, tileID string, v resources.Vehicle) (MoveResult, error) {
+	if len(characterIDs) > v.Capacity {
+		return MoveResult{}, ErrTooManyPassengers
+	}
+	return w.travel(characterIDs, tileID, VehicleBudget(v), v.Name)
+}
+
+func (w *World) travel(characterIDs []string, tileID string, budget int, vehicle string) (MoveResult, error) {
+	if len(w.Tiles) == 0 {
+		return MoveResult{}, ErrUnknownTile
+	}
+	if w.Tile(tileID) == nil {
+		return MoveResult{}, ErrUnknownTile
+	}
+	from, err := w.PartyTile(characterIDs)
+	if err != nil {
+		return MoveResult{}, err
+	}
+
+	path, cost, err := w.Path(from, tileID)
+	if err != nil {
+		return MoveResult{}, err
+	}
+	if cost > budget {
+		return MoveResult{}, ErrTooFar
+	}
+
+	rng := w.nextRNG()
+	res := MoveResult{From: from, To: from, Path: path[:1], Budget: budget, Vehicle: vehicle}
+	enter := func(id string) {
+		for _, c := range characterIDs {
+			w.Enter(c, id)
+		}
+	}
+	enter(from)
+	for _, id := range path[1:] {
+		t := w.Tile(id)
+		res.Path = append(res.Path, id)
+		res.Cost += t.MoveCost()
+		res.To = id
+		enter(id)
+		if !t.Safe() && D20(rng) <= t.Type.Danger {
+			g := AmbushGroup(rng, t.Type.Danger)
+			res.Ambush, res.AmbushSeed = &g, rng.Int63()
+			break
+		}
+	}
+	w.UpdatedAt = time.Now().UTC()
+	return res, nil
+}
+
+// PartyTile is where characterIDs stand together: the shared tile of those
+// already on the map, or the first tile if none are. It fails with
+// ErrPartySplit if they stand apart.
+func (w *World) PartyTile(characterIDs []string) (string, error) {
+	from := ""
+	for _, id := range characterIDs {
+		pos, ok := w.Positions[id]
+		if !ok {
+			continue
+		}
+		if from != "" && pos != from {
+			return "", ErrPartySplit
+		}
+		from = pos
+	}
+	if from == "" {
+		from = w.Tiles[0].ID
+	}
+	return from, nil
+}
+
+// AmbushGroup is a lone wandering monster scaled to danger: the pool is the
+// weakest danger+1 monsters, never a riddle-keeper.
+func AmbushGroup(rng *rand.Rand, danger int) resources.MonsterGroup {
+	var pool []resources.Monster
+	for _, m := range resources.Monsters() {
+		if !m.HasRiddle && len(pool) <= danger {
+			pool = append(pool, m)
+		}
+	}
+	m := pool[rng.Intn(len(pool))]
+	g := resources.MonsterGroup{
+		Name:        "Ambush",
+		Description: "Something was waiting on the road.",
+		Monsters:    []resources.Monster{m},
+		Difficulty:  max(1, danger),
+	}
+	g.TotalXP = g.ComputeTotalXP()
+	return g
+}
+
+type pathNode struct {
+	id   string
+	cost int
+}
+
+// pathQueue is a min-heap of pathNodes by cost.
+type pathQueue []pathNode
+
+func (q pathQueue) Len() int           { return len(q) }
+func (q pathQueue) Less(i, j int) bool { return q[i].cost < q[j].cost }
+func (q pathQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *pathQueue) Push(x any)        { *q = append(*q, x.(pathNode)) }
+func (q *pathQueue) Pop() any {
+	old := *q
+	n := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return n
+}
@@ -0,0 +1,203 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+
+	"m20-game/internal/character"
+)
+
+// XP split rules for a won encounter (config xp_split). Only characters still
+// standing at the end share; downed characters get nothing.
+const (
+	XPSplitEven   = "even"   // the group's total XP shared equally
+	XPSplitKiller = "killer" // each monster's XP to whoever downed it, the rest shared equally
+	XPSplitLevel  = "level"  // the total shared in proportion to level
+)
+
+// MaxLootDrops caps how many items a cleared building gives up.
+const MaxLootDrops = 3
+
+// Rewards is what a won encounter paid out. The server rolls and hands it
+// out; the client only shows it.
+type Rewards struct {
+	XPSplit    string         `json:"xp_split"`
+	XP         map[string]int `json:"xp"`          // combatant ID → XP
+	Loot       []string       `json:"loot"`        // rolled from the building's loot table
+	Drops      []LootDrop     `json:"drops"`       // who carries what (see DistributeLoot)
+	LeftBehind []string       `json:"left_behind"` // nobody had room
+	Forfeited  bool           `json:"forfeited,omitempty"`
+}
+
+// LootDrop is one looted item and the character who took it.
+type LootDrop struct {
+	CharacterID string `json:"character_id"`
+	Name        string `json:"name"`
+	Item        string `json:"item"`
+}
+
+// ValidXPSplit reports whether rule is a known XP split rule.
+func ValidXPSplit(rule string) bool {
+	switch rule {
+	case XPSplitEven, XPSplitKiller, XPSplitLevel:
+		return true
+	}
+	return false
+}
+
+// payOut rolls loot and splits XP once the party has won, returning one
+// "reward" event per survivor who earned XP (XP is awarded to Actor). A
+// practice fight records empty rewards and returns no events.
+func (e *Encounter) payOut(rng *rand.Rand) []EncounterEvent {
+	var survivors []*Combatant
+	for _, c := range e.Order {
+		if c.Kind == KindCharacter && c.Alive() {
+			survivors = append(survivors, c)
+		}
+	}
+	rule := e.XPSplit
+	if !ValidXPSplit(rule) {
+		rule = XPSplitEven
+	}
+	e.Rewards = &Rewards{
+		XPSplit:    rule,
+		XP:         map[string]int{},
+		Loot:       []string{},
+		Drops:      []LootDrop{},
+		LeftBehind: []string{},
+	}
+	if e.Practice {
+		return nil
+	}
+	e.Rewards.XP, e.Rewards.Loot = splitXP(rule, e.Order, survivors), RollLoot(rng, e.Loot)
+
+	var events []EncounterEvent
+	for _, c := range survivors {
+		if xp := e.Rewards.XP[c.ID]; xp > 0 {
+			events = append(events, EncounterEvent{Round: e.Round, Actor: c.ID, Type: "reward", XP: xp,
+				Text: fmt.Sprintf("+%d XP to %s.", xp, c.Name)})
+		}
+	}
+	return events
+}
+
+// splitXP divides the defeated monsters' XP among survivors by rule.
+// Remainders go to the earliest survivors in turn order.
+func splitXP(rule string, order, survivors []*Combatant) map[string]int {
+	xp := make(map[string]int, len(survivors))
+	if len(survivors) == 0 {
+		return xp
+	}
+	standing := make(map[string]bool, len(survivors))
+	for _, c := range survivors {
+		standing[c.ID] = true
+	}
+	pool := 0
+	for _, m := range order {
+		if m.Kind != KindMonster || m.Alive() {
+			continue
+		}
+		if rule == XPSplitKiller && standing[m.KilledBy] {
+			xp[m.KilledBy] += m.XPReward
+			continue
+		}
+		pool += m.XPReward
+	}
+
+	weights := make([]int, len(survivors))
+	total := 0
+	for i, c := range survivors {
+		weights[i] = 1
+		if rule == XPSplitLevel {
+			weights[i] = max(1, c.Level)
+		}
+		total += weights[i]
+	}
+	given := 0
+	for i, c := range survivors {
+		share := pool * weights[i] / total
+		xp[c.ID] += share
+		given += share
+	}
+	for i := 0; given < pool; i = (i + 1) % len(survivors) {
+		xp[survivors[i].ID]++
+		given++
+	}
+	return xp
+}
+
+// RollLoot picks 1-MaxLootDrops different items from a loot table, or none
+// if the table is empty.
+func RollLoot(rng *rand.Rand, table []string) []string {
+	if len(table) == 0 {
+		return []string{}
+	}
+	shuffled := append([]string(nil), table...)
+	rng.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+	return shuffled[:min(rng.Intn(len(table))+1, MaxLootDrops)]
+}
+
+// DistributeLoot hands items out round-robin to characters, in order,
+// skipping anyone at MaxInventorySlots. Items nobody can carry are left
+// behind. Inventories are modified in place.
+func DistributeLoot(items []string, chars []*character.Character) (drops []LootDrop, leftBehind []string) {
+	drops, leftBehind = []LootDrop{}, []string{}
+	next := 0
+	for _, item := range items {
+		taken := false
+		for tries := 0; tries < len(chars) && !taken; tries++ {
+			c := chars[(next+tries)%len(chars)]
+			if len(c.Inventory) >= c.MaxInventorySlots() {
+				continue
+			}
+			c.Inventory = append(c.Inventory, item)
+			drops = append(drops, LootDrop{CharacterID: c.ID, Name: c.Name, Item: item})
+			next = (next + tries + 1) % len(chars)
+			taken = true
+		}
+		if !taken {
+			leftBehind = append(leftBehind, item)
+		}
+	}
+	return drops, leftBehind
+}
+
+// LeaveBehind moves items out of characterID's drops and into LeftBehind,
+// for loot that no longer fit when it reached their pack.
+func (r *Rewards) LeaveBehind(characterID string, items []string) {
+	for _, item := range items {
+		for i := len(r.Drops) - 1; i >= 0; i-- {
+			if r.Drops[i].CharacterID == characterID && r.Drops[i].Item == item {
+				r.Drops = append(r.Drops[:i], r.Drops[i+1:]...)
+				break
+			}
+		}
+		r.LeftBehind = append(r.LeftBehind, item)
+	}
+}
+
+// ForfeitRewards voids a victory's payout, e.g. when someone else cleared the
+// site first: the reward events leave the log and a "forfeit" event is
+// recorded instead and returned.
+func (e *Encounter) ForfeitRewards(reason string) EncounterEvent {
+	if e.Rewards != nil {
+		e.Rewards.XP = map[string]int{}
+		e.Rewards.Loot = []string{}
+		e.Rewards.Forfeited = true
+	}
+	e.Log = WithoutRewards(e.Log)
+	ev := EncounterEvent{Round: e.Round, Type: "forfeit", Text: reason}
+	e.record(ev)
+	return ev
+}
+
+// WithoutRewards returns events minus any "reward" events.
+func WithoutRewards(events []EncounterEvent) []EncounterEvent {
+	kept := make([]EncounterEvent, 0, len(events))
+	for _, ev := range events {
+		if ev.Type != "reward" {
+			kept = append(kept, ev)
+		}
+	}
+	return kept
+}
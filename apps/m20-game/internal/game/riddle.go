@@ -0,0 +1,218 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// RiddleAttempts is how many guesses a riddle session allows before the
+// riddle-keeper loses patience. A successful insight roll adds one.
+const RiddleAttempts = 3
+
+// RiddleStatus is the lifecycle of a riddle session.
+type RiddleStatus string
+
+const (
+	RiddlePending RiddleStatus = "pending"
+	RiddleSolved  RiddleStatus = "solved"
+	RiddleFailed  RiddleStatus = "failed"
+)
+
+var (
+	ErrRiddleOver     = errors.New("riddle is already over")
+	ErrNoRiddleKeeper = errors.New("no living riddle-keeper in this encounter")
+	ErrNotFighting    = errors.New("character is not standing in this encounter")
+)
+
+// RiddleSession is one character's attempt at a riddle. The answer never
+// leaves the server while the riddle is pending.
+type RiddleSession struct {
+	ID           string           `json:"id"`
+	CharacterID  string           `json:"character_id"`
+	EncounterID  string           `json:"encounter_id,omitempty"` // fight the riddle settles, if any
+	Riddle       string           `json:"riddle"`
+	Answer       string           `json:"-"`
+	Revealed     string           `json:"answer,omitempty"` // the answer, once the riddle is over
+	Insight      CombatRollResult `json:"insight"`          // scouting + class riddle bonus vs the keeper's Defense
+	Hint         string           `json:"hint,omitempty"`   // earned by a successful insight roll
+	AttemptsLeft int              `json:"attempts_left"`
+	Guesses      []string         `json:"guesses"`
+	Status       RiddleStatus     `json:"status"`
+	Fallback     bool             `json:"fallback,omitempty"` // riddle came from the built-in list
+	Seed         int64            `json:"seed"`
+	CreatedAt    time.Time        `json:"created_at"`
+	UpdatedAt    time.Time        `json:"updated_at"`
+}
+
+// NewRiddleSession starts a riddle for a character. The insight roll is a
+// RiddleCheck (scouting + the class riddle bonus against defense); success
+// earns a hint and one extra attempt.
+func NewRiddleSession(id, characterID, riddle, answer string, seed int64, scouting, defense int, mods Modifiers) *RiddleSession {
+	now := time.Now().UTC()
+	s := &RiddleSession{
+		ID:           id,
+		CharacterID:  characterID,
+		Riddle:       riddle,
+		Answer:       answer,
+		Insight:      RiddleCheck(NewRNG(seed), scouting, defense, mods),
+		AttemptsLeft: RiddleAttempts,
+		Guesses:      []string{},
+		Status:       RiddlePending,
+		Seed:         seed,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if s.Insight.Outcome == OutcomeSuccess || s.Insight.Outcome == OutcomeCritSuccess {
+		s.AttemptsLeft++
+		s.Hint = riddleHint(answer)
+	}
+	return s
+}
+
+// Guess checks a guess against the answer. A correct guess solves the
+// riddle; running out of attempts fails it. Either way the answer is then
+// revealed.
+func (s *RiddleSession) Guess(guess string) (bool, error) {
+	if s.Status != RiddlePending {
+		return false, ErrRiddleOver
+	}
+	s.Guesses = append(s.Guesses, guess)
+	s.AttemptsLeft--
+	s.UpdatedAt = time.Now().UTC()
+	correct := MatchesAnswer(guess, s.Answer)
+	switch {
+	case correct:
+		s.Status = RiddleSolved
+	case s.AttemptsLeft <= 0:
+		s.Status = RiddleFailed
+	default:
+		return false, nil
+	}
+	s.Revealed = s.Answer
+	return correct, nil
+}
+
+// MatchesAnswer compares a guess to an answer forgivingly: case, punctuation,
+// a leading article, a trailing plural "s", and small typos (one edit for
+// answers of six or more letters, two for eight or more) are all ignored.
+// Shorter answers must be exact, or "wire" would pass for "fire".
+func MatchesAnswer(guess, answer string) bool {
+	g, a := normalizeAnswer(guess), normalizeAnswer(answer)
+	if g == "" || a == "" {
+		return false
+	}
+	if g == a || strings.TrimSuffix(g, "s") == strings.TrimSuffix(a, "s") {
+		return true
+	}
+	allowed := 0
+	switch {
+	case len(a) >= 8:
+		allowed = 2
+	case len(a) >= 6:
+		allowed = 1
+	}
+	return editDistance(g, a) <= allowed
+}
+
+func normalizeAnswer(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	for _, article := range []string{"a ", "an ", "the "} {
+		s = strings.TrimPrefix(s, article)
+	}
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, s)
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+func riddleHint(answer string) string {
+	a := normalizeAnswer(answer)
+	if a == "" {
+		return ""
+	}
+	return fmt.Sprintf("%d letters, starting with %q", len([]rune(a)), strings.ToUpper(string([]rune(a)[:1])))
+}
+
+// RiddleDamage is what a failed riddle costs: the keeper's full attack, as on
+// a natural 20.
+func RiddleDamage(attack int) int {
+	return max(2, attack)
+}
+
+// RiddleKeeper returns the living monster in the encounter that asks riddles.
+func (e *Encounter) RiddleKeeper() *Combatant {
+	for _, c := range e.Order {
+		if c.Kind == KindMonster && c.Riddle && c.Alive() {
+			return c
+		}
+	}
+	return nil
+}
+
+// ResolveRiddle settles a riddle inside the fight, outside the turn order.
+// Solved: the keeper is defeated, credited to the solver. Failed: the
+// keeper hits the solver for RiddleDamage. The returned events are also
+// appended to e.Log.
+func (e *Encounter) ResolveRiddle(characterID string, solved bool) ([]EncounterEvent, error) {
+	if e.Status != EncounterActive {
+		return nil, ErrEncounterOver
+	}
+	keeper := e.RiddleKeeper()
+	if keeper == nil {
+		return nil, ErrNoRiddleKeeper
+	}
+	solver := e.Fighter(characterID)
+	if solver == nil || !solver.Alive() {
+		return nil, ErrNotFighting
+	}
+
+	e.beginAction()
+	var events []EncounterEvent
+	if solved {
+		keeper.HP = 0
+		events = append(events,
+			EncounterEvent{Round: e.Round, Actor: solver.ID, Target: keeper.ID, Type: "riddle", Hit: true,
+				Text: fmt.Sprintf("%s answers the riddle. %s is undone by its own rules.", solver.Name, keeper.Name)},
+			e.defeated(solver.ID, keeper),
+		)
+	} else {
+		dmg := RiddleDamage(keeper.Attack)
+		solver.HP = max(0, solver.HP-dmg)
+		events = append(events, EncounterEvent{
+			Round: e.Round, Actor: keeper.ID, Target: solver.ID, Type: "riddle", Damage: dmg,
+			Text: fmt.Sprintf("%s gets it wrong. %s punishes them for %d. %s has %d/%d HP.", solver.Name, keeper.Name, dmg, solver.Name, solver.HP, solver.MaxHP),
+		})
+	}
+	if evs, over := e.checkEnd(e.nextRNG()); over {
+		events = append(events, evs...)
+	}
+	e.record(events...)
+	return events, nil
+}
@@ -0,0 +1,138 @@
+package game
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RiddleStore persists riddle sessions in the game's SQLite database. The
+// answer lives in its own column because RiddleSession never marshals it.
+type RiddleStore struct {
+	db *sql.DB
+	mu sync.Mutex // serialises Update so two guesses cannot race on one riddle
+}
+
+// NewRiddleStore creates the riddles table if needed, and adds the character
+// and encounter columns (filled from state_json) to databases created before
+// pending riddles were looked up by them.
+func NewRiddleStore(db *sql.DB) (*RiddleStore, error) {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS riddles (
+			id          TEXT PRIMARY KEY,
+			status      TEXT NOT NULL,
+			answer      TEXT NOT NULL,
+			state_json  TEXT NOT NULL,
+			created_at  DATETIME NOT NULL,
+			updated_at  DATETIME NOT NULL
+		)
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("create riddles table: %w", err)
+	}
+	for _, col := range []string{"character_id TEXT NOT NULL DEFAULT ''", "encounter_id TEXT NOT NULL DEFAULT ''"} {
+		_, err := db.Exec(`ALTER TABLE riddles ADD COLUMN ` + col)
+		if err != nil && !strings.Contains(err.Error(), "duplicate column name") {
+			return nil, fmt.Errorf("add riddles column %q: %w", col, err)
+		}
+	}
+	_, err = db.Exec(`
+		UPDATE riddles SET
+			character_id = COALESCE(json_extract(state_json, '$.character_id'), ''),
+			encounter_id = COALESCE(json_extract(state_json, '$.encounter_id'), '')
+		WHERE character_id = '';
+		CREATE INDEX IF NOT EXISTS idx_riddles_pending ON riddles(character_id, encounter_id, status);
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("index riddles: %w", err)
+	}
+	return &RiddleStore{db: db}, nil
+}
+
+// Save creates or updates a riddle session (upsert).
+func (s *RiddleStore) Save(ctx context.Context, r *RiddleSession) error {
+	state, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("marshal riddle: %w", err)
+	}
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO riddles (id, character_id, encounter_id, status, answer, state_json, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			status=excluded.status, state_json=excluded.state_json, updated_at=excluded.updated_at
+	`, r.ID, r.CharacterID, r.EncounterID, string(r.Status), r.Answer, string(state), r.CreatedAt, time.Now().UTC())
+	return err
+}
+
+// Pending returns the character's pending riddle for encounterID ("" for a
+// standalone riddle), or nil if there is none.
+func (s *RiddleStore) Pending(ctx context.Context, characterID, encounterID string) (*RiddleSession, error) {
+	var id string
+	err := s.db.QueryRowContext(ctx, `
+		SELECT id FROM riddles WHERE character_id = ? AND encounter_id = ? AND status = ? LIMIT 1
+	`, characterID, encounterID, string(RiddlePending)).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find pending riddle: %w", err)
+	}
+	return s.Load(ctx, id)
+}
+
+// Create saves a new session unless the character already has a pending
+// riddle for the same encounter, in which case it returns that one and
+// created=false. A riddle can't be rerolled until it is solved or failed.
+func (s *RiddleStore) Create(ctx context.Context, r *RiddleSession) (session *RiddleSession, created bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	existing, err := s.Pending(ctx, r.CharacterID, r.EncounterID)
+	if err != nil || existing != nil {
+		return existing, false, err
+	}
+	if err := s.Save(ctx, r); err != nil {
+		return nil, false, err
+	}
+	return r, true, nil
+}
+
+// Load retrieves a riddle session by ID.
+// Returns (nil, nil) if not found.
+func (s *RiddleStore) Load(ctx context.Context, id string) (*RiddleSession, error) {
+	var answer, state string
+	err := s.db.QueryRowContext(ctx, `SELECT answer, state_json FROM riddles WHERE id = ?`, id).Scan(&answer, &state)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scan riddle: %w", err)
+	}
+	var r RiddleSession
+	if err := json.Unmarshal([]byte(state), &r); err != nil {
+		return nil, fmt.Errorf("unmarshal riddle: %w", err)
+	}
+	r.Answer = answer
+	return &r, nil
+}
+
+// Update loads a riddle session, applies fn, and saves it if fn succeeds.
+// Returns (nil, nil) if the session does not exist.
+func (s *RiddleStore) Update(ctx context.Context, id string, fn func(*RiddleSession) error) (*RiddleSession, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, err := s.Load(ctx, id)
+	if err != nil || r == nil {
+		return nil, err
+	}
+	if err := fn(r); err != nil {
+		return nil, err
+	}
+	if err := s.Save(ctx, r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
@@ -0,0 +1,109 @@
+package game
+
+import "testing"
+
+func TestMatchesAnswer(t *testing.T) {
+	cases := []struct {
+		guess, answer string
+		want          bool
+	}{
+		{"echo", "echo", true},
+		{"  ECHO! ", "echo", true},
+		{"an echo", "echo", true},
+		{"the footsteps", "footstep", true}, // article and plural
+		{"candles", "a candle", true},       // plural on the guess side
+		{"shadw", "shadow", true},           // one typo at six letters
+		{"tomorow", "tomorrow", true},       // one typo at eight
+		{"tomorw", "tomorrow", true},        // two typos at eight
+		{"shdw", "shadow", false},           // two typos at six
+		{"wire", "fire", false},             // short answers must be exact
+		{"", "echo", false},                 // nothing to compare
+		{"!!!", "echo", false},              // punctuation alone is nothing
+		{"keyboard", "a keyboard", true},
+	}
+	for _, tc := range cases {
+		if got := MatchesAnswer(tc.guess, tc.answer); got != tc.want {
+			t.Errorf("MatchesAnswer(%q, %q) = %v, want %v", tc.guess, tc.answer, got, tc.want)
+		}
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"", "abc", 3},
+		{"kitten", "sitting", 3},
+		{"flaw", "lawn", 2},
+		{"shadow", "shadow", 0},
+		{"naïve", "naive", 1}, // runes, not bytes
+	}
+	for _, tc := range cases {
+		if got := editDistance(tc.a, tc.b); got != tc.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
+
+func TestRiddleSession_Guess(t *testing.T) {
+	s := &RiddleSession{Answer: "echo", AttemptsLeft: 2, Guesses: []string{}, Status: RiddlePending}
+
+	if correct, err := s.Guess("wind"); err != nil || correct {
+		t.Fatalf("Expected a wrong guess with no error, got correct=%v err=%v", correct, err)
+	}
+	if s.Revealed != "" {
+		t.Errorf("Expected the answer hidden while pending, got %q", s.Revealed)
+	}
+	if correct, err := s.Guess("silence"); err != nil || correct {
+		t.Fatalf("Expected a wrong last guess with no error, got correct=%v err=%v", correct, err)
+	}
+	if s.Status != RiddleFailed || s.Revealed != "echo" {
+		t.Errorf("Expected failed with the answer revealed, got %s / %q", s.Status, s.Revealed)
+	}
+	if _, err := s.Guess("echo"); err != ErrRiddleOver {
+		t.Errorf("Expected ErrRiddleOver after the last attempt, got %v", err)
+	}
+}
+
+func TestResolveRiddle(t *testing.T) {
+	newFight := func() *Encounter {
+		return &Encounter{
+			Group:  "Sphinx Chamber",
+			Status: EncounterActive,
+			Order: []*Combatant{
+				{ID: "c0", Kind: KindCharacter, CharacterID: "a", Name: "A", HP: 10, MaxHP: 10},
+				{ID: "c1", Kind: KindCharacter, CharacterID: "b", Name: "B", HP: 0, MaxHP: 10},
+				{ID: "m0", Kind: KindMonster, Name: "Sphinx", HP: 20, MaxHP: 20, Attack: 6, Riddle: true, XPReward: 80},
+			},
+		}
+	}
+	cases := []struct {
+		name        string
+		characterID string
+		solved      bool
+		wantErr     error
+		wantStatus  EncounterStatus
+		wantHP      int // A's HP afterwards
+	}{
+		{"solved defeats the keeper", "a", true, nil, EncounterVictory, 10},
+		{"failed costs the keeper's attack", "a", false, nil, EncounterActive, 4},
+		{"downed characters can't answer", "b", true, ErrNotFighting, EncounterActive, 10},
+		{"strangers can't answer", "z", true, ErrNotFighting, EncounterActive, 10},
+	}
+	for _, tc := range cases {
+		e := newFight()
+		_, err := e.ResolveRiddle(tc.characterID, tc.solved)
+		if err != tc.wantErr {
+			t.Errorf("%s: got error %v, want %v", tc.name, err, tc.wantErr)
+		}
+		if e.Status != tc.wantStatus {
+			t.Errorf("%s: status %s, want %s", tc.name, e.Status, tc.wantStatus)
+		}
+		if hp := e.Fighter("a").HP; hp != tc.wantHP {
+			t.Errorf("%s: A has %d HP, want %d", tc.name, hp, tc.wantHP)
+		}
+	}
+}
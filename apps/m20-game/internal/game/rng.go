@@ -0,0 +1,27 @@
+package game
+
+import "math/rand"
+
+// Every rule that rolls dice takes an explicit *rand.Rand so a seed can
+// reproduce a map, a loot roll, or a whole fight. A *rand.Rand is not safe
+// for concurrent use: make one per request (or per step) with NewRNG.
+
+// NewSeed returns a fresh seed for callers that did not supply one.
+func NewSeed() int64 {
+	return rand.Int63()
+}
+
+// NewRNG returns a generator seeded with seed.
+func NewRNG(seed int64) *rand.Rand {
+	return rand.New(rand.NewSource(seed))
+}
+
+// StepRNG returns the generator for step n of a long-lived seeded object
+// (a world's draws and moves, an encounter's turns). Each request advances
+// the object's step counter, so replaying the same requests against the same
+// seed replays the same rolls even though they happen across many requests.
+func StepRNG(seed int64, step int) *rand.Rand {
+	// Spread steps with the 64-bit golden ratio so seed s step 1 does not
+	// collide with seed s+1 step 0.
+	return NewRNG(int64(uint64(seed) ^ uint64(step)*0x9E3779B97F4A7C15))
+}
@@ -16,9 +16,10 @@ type ScavengeResult struct {
 
 // Scavenge performs a scavenging check and returns found supplies.
 // scoutLevel: the character's scouting stat. Higher = more finds.
-func Scavenge(scoutLevel int) ScavengeResult {
+// mods adds class extras (Scavenger finds one more item).
+func Scavenge(rng *rand.Rand, scoutLevel int, mods Modifiers) ScavengeResult {
 	all := resources.Supplies()
-	roll := rand.Intn(20) + 1
+	roll := D20(rng)
 	total := roll + scoutLevel
 
 	// Filter by rarity: higher roll = access to rarer items.
@@ -39,13 +40,14 @@ func Scavenge(scoutLevel int) ScavengeResult {
 	} else if total >= 10 {
 		count = 2
 	}
+	count += mods.ExtraScavengeFinds
 
 	if count > len(eligible) {
 		count = len(eligible)
 	}
 
 	// Shuffle and take first N.
-	rand.Shuffle(len(eligible), func(i, j int) { eligible[i], eligible[j] = eligible[j], eligible[i] })
+	rng.Shuffle(len(eligible), func(i, j int) { eligible[i], eligible[j] = eligible[j], eligible[i] })
 	found := eligible[:count]
 
 	desc := "You poke through the rubble and find a few things."
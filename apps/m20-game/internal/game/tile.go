@@ -19,14 +19,20 @@ type BuildingInstance struct {
 type Tile struct {
 	ID        string             `json:"id"`
 	Type      resources.TileType `json:"type"`
+	X         int                `json:"x"` // grid column (see GridPos)
+	Y         int                `json:"y"` // grid row
 	Buildings []BuildingInstance `json:"buildings"`
-	Explored  bool               `json:"explored"`
+	Explored  bool               `json:"explored"`       // entered by at least one character
+	Exit      bool               `json:"exit,omitempty"` // the way out (see ExitTile)
+
+	VehicleSearched bool `json:"vehicle_searched,omitempty"` // see World.SearchTile
+	Scavenged       bool `json:"scavenged,omitempty"`        // see World.ScavengeTile
 }
 
 // GenerateTile picks a random tile type and populates it with buildings and monster groups.
-func GenerateTile(id string) Tile {
+func GenerateTile(rng *rand.Rand, id string) Tile {
 	allTiles := resources.Tiles()
-	t := allTiles[rand.Intn(len(allTiles))]
+	t := allTiles[rng.Intn(len(allTiles))]
 
 	// Danger 1-2 → 2 buildings; danger 3 → 3 buildings; danger 4-5 → 4 buildings.
 	buildingCount := 2
@@ -39,7 +45,7 @@ func GenerateTile(id string) Tile {
 
 	allBuildings := resources.Buildings()
 	// Shuffle and pick N (with possible repeats only if fewer buildings than count).
-	rand.Shuffle(len(allBuildings), func(i, j int) { allBuildings[i], allBuildings[j] = allBuildings[j], allBuildings[i] })
+	rng.Shuffle(len(allBuildings), func(i, j int) { allBuildings[i], allBuildings[j] = allBuildings[j], allBuildings[i] })
 	if buildingCount > len(allBuildings) {
 		buildingCount = len(allBuildings)
 	}
@@ -47,7 +53,7 @@ func GenerateTile(id string) Tile {
 	instances := make([]BuildingInstance, buildingCount)
 	for i := 0; i < buildingCount; i++ {
 		b := allBuildings[i]
-		g := resources.RandomGroupForBuilding(b.Name)
+		g := resources.RandomGroupForBuilding(rng, b.Name)
 		g.TotalXP = g.ComputeTotalXP()
 		instances[i] = BuildingInstance{
 			Building:     b,
@@ -68,7 +74,7 @@ func GenerateTile(id string) Tile {
 
 // GenerateSingleBuilding generates one building encounter by name (for /api/building/enter).
 // If buildingName is empty, picks at random.
-func GenerateSingleBuilding(buildingName string) BuildingInstance {
+func GenerateSingleBuilding(rng *rand.Rand, buildingName string) BuildingInstance {
 	allBuildings := resources.Buildings()
 
 	var b resources.Building
@@ -81,10 +87,10 @@ func GenerateSingleBuilding(buildingName string) BuildingInstance {
 		}
 	}
 	if b.Name == "" {
-		b = allBuildings[rand.Intn(len(allBuildings))]
+		b = allBuildings[rng.Intn(len(allBuildings))]
 	}
 
-	g := resources.RandomGroupForBuilding(b.Name)
+	g := resources.RandomGroupForBuilding(rng, b.Name)
 	g.TotalXP = g.ComputeTotalXP()
 
 	return BuildingInstance{
@@ -95,7 +101,9 @@ func GenerateSingleBuilding(buildingName string) BuildingInstance {
 }
 
 // GenerateLand creates a map with tileCount tiles, each fully populated.
-func GenerateLand(tileCount int) Land {
+// The same seed always generates the same land.
+func GenerateLand(seed int64, tileCount int) Land {
+	rng := NewRNG(seed)
 	if tileCount < 1 {
 		tileCount = 1
 	}
@@ -104,7 +112,8 @@ func GenerateLand(tileCount int) Land {
 	}
 	tiles := make([]Tile, tileCount)
 	for i := range tiles {
-		tiles[i] = GenerateTile(fmt.Sprintf("tile-%02d", i+1))
+		tiles[i] = GenerateTile(rng, fmt.Sprintf("tile-%02d", i+1))
+		tiles[i].X, tiles[i].Y = GridPos(i)
 	}
-	return Land{Tiles: tiles, TileCount: tileCount}
+	return Land{Tiles: tiles, TileCount: tileCount, Seed: seed}
 }
@@ -1,8 +1,12 @@
 package game
 
 import (
+	"errors"
+	"fmt"
 	"math/rand"
+	"slices"
 
+	"m20-game/internal/character"
 	"m20-game/internal/resources"
 )
 
@@ -14,9 +18,9 @@ type VehicleFindResult struct {
 }
 
 // FindVehicle discovers a random vehicle in the world.
-func FindVehicle() VehicleFindResult {
+func FindVehicle(rng *rand.Rand) VehicleFindResult {
 	vehicles := resources.Vehicles()
-	v := vehicles[rand.Intn(len(vehicles))]
+	v := vehicles[rng.Intn(len(vehicles))]
 
 	needsRepair := v.Condition == "damaged" || v.Condition == "wreck"
 	desc := "You find a " + v.Name + ". " + v.Description
@@ -32,3 +36,192 @@ func FindVehicle() VehicleFindResult {
 		Description: desc,
 	}
 }
+
+// Vehicle conditions, best first. A repair moves a vehicle one step up.
+const (
+	ConditionOperational = "operational"
+	ConditionDamaged     = "damaged"
+	ConditionWreck       = "wreck"
+)
+
+const (
+	// VehicleSearchTarget is what d20 + scouting must meet to turn up a vehicle.
+	VehicleSearchTarget = 12
+	// FuelPerCan is how many drives one "Fuel" item buys.
+	FuelPerCan = 3
+	// StartingFuel is what's left in the tank of a vehicle when it's found.
+	StartingFuel = 1
+	// RepairKit stands in for every other repair material.
+	RepairKit = "Vehicle Repair Kit"
+)
+
+var (
+	ErrNoVehicleHere     = errors.New("this tile has nothing with wheels")
+	ErrAlreadySearched   = errors.New("this tile has already been searched for vehicles")
+	ErrGarageFull        = errors.New("no room for another vehicle")
+	ErrUnknownVehicle    = errors.New("character does not own that vehicle")
+	ErrNothingToRepair   = errors.New("vehicle is already operational")
+	ErrMissingMaterials  = errors.New("missing repair materials")
+	ErrVehicleBroken     = errors.New("vehicle needs repair before it can drive")
+	ErrNoFuel            = errors.New("vehicle is out of fuel")
+	ErrTooManyPassengers = errors.New("party does not fit in the vehicle")
+	ErrFuelNotNeeded     = errors.New("vehicle does not take fuel")
+	ErrNoFuelInInventory = errors.New("no Fuel in inventory")
+)
+
+// repairs lists, per condition, the materials and the d20 + max(salvaging,
+// crafting) target for one repair step.
+var repairs = map[string]struct {
+	Materials []string
+	Target    int
+}{
+	ConditionDamaged: {[]string{"Engine Parts", "Duct Tape"}, 10},
+	ConditionWreck:   {[]string{"Engine Parts", "Scrap Metal", "Tools", "Duct Tape"}, 14},
+}
+
+// VehicleSearch is the result of searching a tile for a vehicle.
+type VehicleSearch struct {
+	Roll  CombatRollResult   `json:"roll"`
+	Found *VehicleFindResult `json:"found,omitempty"`
+	Text  string             `json:"text"`
+}
+
+// SearchTile looks for a vehicle on a tile whose terrain lists the "vehicle"
+// encounter. Each tile can be searched once, win or lose.
+func (w *World) SearchTile(tileID string, scouting int) (VehicleSearch, error) {
+	t := w.Tile(tileID)
+	if t == nil {
+		return VehicleSearch{}, ErrUnknownTile
+	}
+	if !slices.Contains(t.Type.Encounters, "vehicle") {
+		return VehicleSearch{}, ErrNoVehicleHere
+	}
+	if t.VehicleSearched {
+		return VehicleSearch{}, ErrAlreadySearched
+	}
+	rng := w.nextRNG()
+	t.VehicleSearched = true
+
+	s := VehicleSearch{Roll: Roll(rng, CombatRollRequest{StatValue: scouting, Target: VehicleSearchTarget}, 20)}
+	if s.Roll.Outcome == OutcomeSuccess || s.Roll.Outcome == OutcomeCritSuccess {
+		found := FindVehicle(rng)
+		s.Found, s.Text = &found, found.Description
+	} else {
+		s.Text = "You check every lot and ditch on " + t.Type.Name + ". Nothing with wheels left."
+	}
+	return s, nil
+}
+
+// ClaimVehicle adds a found vehicle to the character's garage.
+func ClaimVehicle(c *character.Character, id string, v resources.Vehicle) (*character.OwnedVehicle, error) {
+	if len(c.Vehicles) >= character.MaxVehicles {
+		return nil, ErrGarageFull
+	}
+	fuel := 0
+	if v.FuelNeeded {
+		fuel = StartingFuel
+	}
+	c.Vehicles = append(c.Vehicles, character.OwnedVehicle{ID: id, Vehicle: v, Fuel: fuel})
+	return &c.Vehicles[len(c.Vehicles)-1], nil
+}
+
+// RepairResult is the outcome of one repair attempt.
+type RepairResult struct {
+	Roll      CombatRollResult `json:"roll"`
+	Consumed  []string         `json:"consumed"`
+	Repaired  bool             `json:"repaired"`
+	Condition string           `json:"condition"`
+	Text      string           `json:"text"`
+}
+
+// RepairMaterials returns what repairing v one step will consume from
+// inventory: a Vehicle Repair Kit if there is one, otherwise the condition's
+// materials less any the class waives.
+func RepairMaterials(v *character.OwnedVehicle, inventory []string, mods Modifiers) ([]string, error) {
+	plan, ok := repairs[v.Condition]
+	if !ok {
+		return nil, ErrNothingToRepair
+	}
+	if slices.Contains(inventory, RepairKit) {
+		return []string{RepairKit}, nil
+	}
+	return mods.MaterialsFor(resources.CraftableItem{Materials: plan.Materials, Category: resources.CategoryVehicle}), nil
+}
+
+// RepairVehicle spends the repair materials and rolls the better of effective
+// salvaging and crafting against the condition's target. Materials are used
+// up whether or not the roll succeeds; success moves the vehicle one
+// condition up.
+func RepairVehicle(rng *rand.Rand, c *character.Character, vehicleID string) (RepairResult, error) {
+	v := c.Vehicle(vehicleID)
+	if v == nil {
+		return RepairResult{}, ErrUnknownVehicle
+	}
+	mats, err := RepairMaterials(v, c.Inventory, ClassModifiers(c.Class))
+	if err != nil {
+		return RepairResult{}, err
+	}
+	if !c.HasItems(mats) {
+		return RepairResult{}, fmt.Errorf("%w: need %v", ErrMissingMaterials, mats)
+	}
+	for _, m := range mats {
+		c.RemoveFirstItem(m)
+		if !slices.Contains(c.Inventory, m) {
+			c.Unequip(m)
+		}
+	}
+
+	stats := c.EffectiveStats()
+	res := RepairResult{
+		Roll:      Roll(rng, CombatRollRequest{StatValue: max(stats.Salvaging, stats.Crafting), Target: repairs[v.Condition].Target}, 20),
+		Consumed:  mats,
+		Condition: v.Condition,
+	}
+	if res.Roll.Outcome == OutcomeSuccess || res.Roll.Outcome == OutcomeCritSuccess {
+		if v.Condition == ConditionWreck {
+			v.Condition = ConditionDamaged
+		} else {
+			v.Condition = ConditionOperational
+		}
+		res.Repaired, res.Condition = true, v.Condition
+		res.Text = fmt.Sprintf("The %s is now %s.", v.Name, v.Condition)
+	} else {
+		res.Text = fmt.Sprintf("The %s coughs, sputters, and eats your parts. Still %s.", v.Name, v.Condition)
+	}
+	return res, nil
+}
+
+// Refuel pours one Fuel from the character's inventory into the vehicle.
+func Refuel(c *character.Character, vehicleID string) (*character.OwnedVehicle, error) {
+	v := c.Vehicle(vehicleID)
+	if v == nil {
+		return nil, ErrUnknownVehicle
+	}
+	if !v.FuelNeeded {
+		return nil, ErrFuelNotNeeded
+	}
+	if !c.RemoveFirstItem("Fuel") {
+		return nil, ErrNoFuelInInventory
+	}
+	v.Fuel += FuelPerCan
+	return v, nil
+}
+
+// VehicleBudget is the movement points one drive can spend: twice the speed,
+// so even a bicycle outpaces walking (MoveBudget).
+func VehicleBudget(v resources.Vehicle) int {
+	return 2 * v.Speed
+}
+
+// CanDrive checks that v can carry passengers anywhere right now.
+func CanDrive(v *character.OwnedVehicle, passengers int) error {
+	switch {
+	case v.Condition != ConditionOperational:
+		return ErrVehicleBroken
+	case v.FuelNeeded && v.Fuel <= 0:
+		return ErrNoFuel
+	case passengers > v.Capacity:
+		return ErrTooManyPassengers
+	}
+	return nil
+}
@@ -0,0 +1,311 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+	"time"
+
+	"m20-game/internal/resources"
+)
+
+// MaxWorldTiles is the size of the 5×5 map grid.
+const MaxWorldTiles = 25
+
+// handSize is how many tiles a draw offers; the party keeps one.
+const handSize = 2
+
+// The exit can turn up in a draw once this many tiles are placed, with a
+// 1-in-exitTileOdds chance per draw until it has been placed.
+const (
+	exitTileMinPlaced = 5
+	exitTileOdds      = 4
+)
+
+var (
+	ErrWorldFull        = errors.New("the map is full")
+	ErrNoHand           = errors.New("no tiles drawn")
+	ErrBadChoice        = errors.New("choice is not a tile in the hand")
+	ErrUnknownTile      = errors.New("tile is not on this map")
+	ErrUnknownBuilding  = errors.New("building is not on this tile")
+	ErrAlreadyCleared   = errors.New("building is already cleared")
+	ErrNotOnTile        = errors.New("character is not on that tile")
+	ErrCharacterNotHere = errors.New("character has not entered this world")
+	ErrAlreadyScavenged = errors.New("this tile has already been scavenged")
+)
+
+// World is a persistent map shared by a party. Tiles are placed one at a time
+// (draw two, keep one) up to MaxWorldTiles; every unplaced slot is fog.
+type World struct {
+	ID        string              `json:"id"`
+	Name      string              `json:"name"`
+	Seed      int64               `json:"seed"`
+	Step      int                 `json:"step"`      // RNG steps taken: one for setup, one per draw and move
+	Tiles     []*Tile             `json:"tiles"`     // placed tiles, in placement order
+	Hand      []Tile              `json:"hand"`      // drawn candidates awaiting Place
+	Positions map[string]string   `json:"positions"` // character ID → tile ID
+	Explored  map[string][]string `json:"explored"`  // character ID → tile IDs they have entered
+	CreatedAt time.Time           `json:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at"`
+}
+
+// NewWorld creates a world with tileCount tiles already placed
+// (0 for an empty map that grows by drawing). Every draw and move rolls from
+// seed, so replaying the same requests rebuilds the same world.
+func NewWorld(id, name string, seed int64, tileCount int) *World {
+	now := time.Now().UTC()
+	w := &World{
+		ID:        id,
+		Name:      name,
+		Seed:      seed,
+		Tiles:     []*Tile{},
+		Hand:      []Tile{},
+		Positions: map[string]string{},
+		Explored:  map[string][]string{},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	rng := w.nextRNG()
+	for range min(max(tileCount, 0), MaxWorldTiles) {
+		t := GenerateTile(rng, "")
+		w.place(t)
+	}
+	return w
+}
+
+// Tile returns a placed tile by ID, or nil.
+func (w *World) Tile(id string) *Tile {
+	for _, t := range w.Tiles {
+		if t.ID == id {
+			return t
+		}
+	}
+	return nil
+}
+
+// HasExit reports whether the exit tile has been placed.
+func (w *World) HasExit() bool {
+	return slices.ContainsFunc(w.Tiles, func(t *Tile) bool { return t.Exit })
+}
+
+// Draw deals the hand the party chooses from. Drawing again before placing
+// returns the same hand, so a bad draw cannot be rerolled.
+func (w *World) Draw() ([]Tile, error) {
+	if len(w.Tiles) >= MaxWorldTiles {
+		return nil, ErrWorldFull
+	}
+	if len(w.Hand) > 0 {
+		return w.Hand, nil
+	}
+	rng := w.nextRNG()
+	for range handSize {
+		w.Hand = append(w.Hand, GenerateTile(rng, ""))
+	}
+	if len(w.Tiles) >= exitTileMinPlaced && !w.HasExit() && rng.Intn(exitTileOdds) == 0 {
+		w.Hand[handSize-1] = ExitTile()
+	}
+	w.UpdatedAt = time.Now().UTC()
+	return w.Hand, nil
+}
+
+// Place puts Hand[choice] on the map and discards the rest of the hand.
+func (w *World) Place(choice int) (*Tile, error) {
+	if len(w.Hand) == 0 {
+		return nil, ErrNoHand
+	}
+	if choice < 0 || choice >= len(w.Hand) {
+		return nil, ErrBadChoice
+	}
+	t := w.place(w.Hand[choice])
+	w.Hand = []Tile{}
+	return t, nil
+}
+
+func (w *World) place(t Tile) *Tile {
+	t.ID = fmt.Sprintf("tile-%02d", len(w.Tiles)+1)
+	t.X, t.Y = GridPos(len(w.Tiles))
+	w.Tiles = append(w.Tiles, &t)
+	w.UpdatedAt = time.Now().UTC()
+	return &t
+}
+
+// nextRNG returns the generator for the current step and advances it.
+func (w *World) nextRNG() *rand.Rand {
+	rng := StepRNG(w.Seed, w.Step)
+	w.Step++
+	return rng
+}
+
+// Enter moves a character onto a placed tile and marks it explored,
+// revealing its buildings to that character.
+func (w *World) Enter(characterID, tileID string) (*Tile, error) {
+	t := w.Tile(tileID)
+	if t == nil {
+		return nil, ErrUnknownTile
+	}
+	w.Positions[characterID] = tileID
+	t.Explored = true
+	if !slices.Contains(w.Explored[characterID], tileID) {
+		w.Explored[characterID] = append(w.Explored[characterID], tileID)
+	}
+	w.UpdatedAt = time.Now().UTC()
+	return t, nil
+}
+
+// Building returns building idx on tileID, checking that characterID is
+// standing on that tile.
+func (w *World) Building(characterID, tileID string, idx int) (*BuildingInstance, error) {
+	pos, ok := w.Positions[characterID]
+	if !ok {
+		return nil, ErrCharacterNotHere
+	}
+	if pos != tileID {
+		return nil, ErrNotOnTile
+	}
+	t := w.Tile(tileID)
+	if t == nil {
+		return nil, ErrUnknownTile
+	}
+	if idx < 0 || idx >= len(t.Buildings) {
+		return nil, ErrUnknownBuilding
+	}
+	return &t.Buildings[idx], nil
+}
+
+// ClearBuilding marks building idx on tileID as cleared.
+func (w *World) ClearBuilding(tileID string, idx int) error {
+	t := w.Tile(tileID)
+	if t == nil {
+		return ErrUnknownTile
+	}
+	if idx < 0 || idx >= len(t.Buildings) {
+		return ErrUnknownBuilding
+	}
+	if t.Buildings[idx].Cleared {
+		return ErrAlreadyCleared
+	}
+	t.Buildings[idx].Cleared = true
+	w.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// ScavengeTile marks the tile characterID stands on as scavenged and returns
+// it. Each tile can be scavenged once, by anyone, so supplies can't be farmed;
+// the caller rolls the find.
+func (w *World) ScavengeTile(characterID string) (*Tile, error) {
+	pos, ok := w.Positions[characterID]
+	if !ok {
+		return nil, ErrCharacterNotHere
+	}
+	t := w.Tile(pos)
+	if t == nil {
+		return nil, ErrUnknownTile
+	}
+	if t.Scavenged {
+		return nil, ErrAlreadyScavenged
+	}
+	t.Scavenged = true
+	w.UpdatedAt = time.Now().UTC()
+	return t, nil
+}
+
+// WorldView is a world as one character sees it: placed tiles show their
+// terrain, but buildings stay hidden until the character has entered the tile.
+type WorldView struct {
+	ID        string            `json:"id"`
+	Name      string            `json:"name"`
+	Seed      int64             `json:"seed"`
+	Size      int               `json:"size"`
+	Fog       int               `json:"fog"` // unplaced slots
+	Tiles     []TileView        `json:"tiles"`
+	Hand      []TileView        `json:"hand"`
+	Positions map[string]string `json:"positions"`
+	ExitFound bool              `json:"exit_found"`
+	Viewer    string            `json:"viewer,omitempty"` // character ID; "" = whole party
+}
+
+// TileView is one tile in a WorldView.
+type TileView struct {
+	ID            string             `json:"id"`
+	Type          resources.TileType `json:"type"`
+	X             int                `json:"x"`
+	Y             int                `json:"y"`
+	Exit          bool               `json:"exit,omitempty"`
+	Explored      bool               `json:"explored"` // by the viewer
+	BuildingCount int                `json:"building_count"`
+	Cleared       int                `json:"cleared"`
+	Buildings     []BuildingInstance `json:"buildings,omitempty"` // only once explored
+
+	VehicleSearched bool `json:"vehicle_searched,omitempty"`
+	Scavenged       bool `json:"scavenged,omitempty"`
+}
+
+// ViewFor renders the world as characterID sees it. An empty characterID
+// gives the party view: anything any character has explored is revealed.
+func (w *World) ViewFor(characterID string) WorldView {
+	v := WorldView{
+		ID:        w.ID,
+		Name:      w.Name,
+		Seed:      w.Seed,
+		Size:      MaxWorldTiles,
+		Fog:       MaxWorldTiles - len(w.Tiles),
+		Tiles:     make([]TileView, 0, len(w.Tiles)),
+		Hand:      make([]TileView, 0, len(w.Hand)),
+		Positions: w.Positions,
+		ExitFound: w.HasExit(),
+		Viewer:    characterID,
+	}
+	for _, t := range w.Tiles {
+		explored := t.Explored
+		if characterID != "" {
+			explored = slices.Contains(w.Explored[characterID], t.ID)
+		}
+		v.Tiles = append(v.Tiles, t.view(explored))
+	}
+	for _, t := range w.Hand {
+		v.Hand = append(v.Hand, t.view(false))
+	}
+	return v
+}
+
+func (t Tile) view(explored bool) TileView {
+	tv := TileView{
+		ID:            t.ID,
+		Type:          t.Type,
+		X:             t.X,
+		Y:             t.Y,
+		Exit:          t.Exit,
+		Explored:      explored,
+		BuildingCount: len(t.Buildings),
+	}
+	for _, b := range t.Buildings {
+		if b.Cleared {
+			tv.Cleared++
+		}
+	}
+	if explored {
+		tv.Buildings = t.Buildings
+		tv.VehicleSearched = t.VehicleSearched
+		tv.Scavenged = t.Scavenged
+	}
+	return tv
+}
+
+// ExitTile is the way out: a single door guarded by the Windego Den.
+func ExitTile() Tile {
+	g := resources.SpecialGroups()["Windego Den"]
+	g.TotalXP = g.ComputeTotalXP()
+	return Tile{
+		Type: resources.TileType{
+			Name:        "Exit Door",
+			Description: "There's a door here. Something very big is standing in front of it.",
+			Danger:      5,
+		},
+		Buildings: []BuildingInstance{{
+			Building:     resources.Building{Name: "Exit Door", Description: "The way out. Heavily guarded.", Danger: 5},
+			MonsterGroup: g,
+		}},
+		Exit: true,
+	}
+}
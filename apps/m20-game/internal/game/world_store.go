@@ -0,0 +1,209 @@
+package game
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WorldStore persists worlds in the game's SQLite database: one row per world,
+// per placed tile (buildings and cleared flags ride in tile_json), per
+// character position, and per tile a character has explored.
+type WorldStore struct {
+	db *sql.DB
+	mu sync.Mutex // serialises Update so two requests cannot race on one world
+}
+
+// NewWorldStore creates the world tables if needed, and adds the seed columns
+// to databases created before worlds were seeded.
+func NewWorldStore(db *sql.DB) (*WorldStore, error) {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS worlds (
+			id          TEXT PRIMARY KEY,
+			name        TEXT NOT NULL,
+			hand_json   TEXT NOT NULL DEFAULT '[]',
+			created_at  DATETIME NOT NULL,
+			updated_at  DATETIME NOT NULL
+		);
+		CREATE TABLE IF NOT EXISTS world_tiles (
+			world_id    TEXT NOT NULL,
+			tile_id     TEXT NOT NULL,
+			slot        INTEGER NOT NULL,
+			tile_json   TEXT NOT NULL,
+			explored    INTEGER NOT NULL DEFAULT 0,
+			PRIMARY KEY (world_id, tile_id)
+		);
+		CREATE TABLE IF NOT EXISTS world_positions (
+			world_id     TEXT NOT NULL,
+			character_id TEXT NOT NULL,
+			tile_id      TEXT NOT NULL,
+			PRIMARY KEY (world_id, character_id)
+		);
+		CREATE TABLE IF NOT EXISTS world_explored (
+			world_id     TEXT NOT NULL,
+			character_id TEXT NOT NULL,
+			tile_id      TEXT NOT NULL,
+			PRIMARY KEY (world_id, character_id, tile_id)
+		)
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("create world tables: %w", err)
+	}
+	for _, col := range []string{"seed INTEGER NOT NULL DEFAULT 0", "step INTEGER NOT NULL DEFAULT 0"} {
+		_, err := db.Exec(`ALTER TABLE worlds ADD COLUMN ` + col)
+		if err != nil && !strings.Contains(err.Error(), "duplicate column name") {
+			return nil, fmt.Errorf("add worlds column %q: %w", col, err)
+		}
+	}
+	return &WorldStore{db: db}, nil
+}
+
+// Save writes the whole world in one transaction (upsert).
+func (s *WorldStore) Save(ctx context.Context, w *World) error {
+	hand, err := json.Marshal(w.Hand)
+	if err != nil {
+		return fmt.Errorf("marshal hand: %w", err)
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO worlds (id, name, seed, step, hand_json, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			name=excluded.name, step=excluded.step, hand_json=excluded.hand_json, updated_at=excluded.updated_at
+	`, w.ID, w.Name, w.Seed, w.Step, string(hand), w.CreatedAt, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("save world: %w", err)
+	}
+	for slot, t := range w.Tiles {
+		tile, err := json.Marshal(t)
+		if err != nil {
+			return fmt.Errorf("marshal tile %s: %w", t.ID, err)
+		}
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO world_tiles (world_id, tile_id, slot, tile_json, explored)
+			VALUES (?, ?, ?, ?, ?)
+			ON CONFLICT(world_id, tile_id) DO UPDATE SET
+				slot=excluded.slot, tile_json=excluded.tile_json, explored=excluded.explored
+		`, w.ID, t.ID, slot, string(tile), t.Explored)
+		if err != nil {
+			return fmt.Errorf("save tile %s: %w", t.ID, err)
+		}
+	}
+	for charID, tileID := range w.Positions {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO world_positions (world_id, character_id, tile_id)
+			VALUES (?, ?, ?)
+			ON CONFLICT(world_id, character_id) DO UPDATE SET tile_id=excluded.tile_id
+		`, w.ID, charID, tileID)
+		if err != nil {
+			return fmt.Errorf("save position: %w", err)
+		}
+	}
+	for charID, tiles := range w.Explored {
+		for _, tileID := range tiles {
+			_, err = tx.ExecContext(ctx, `
+				INSERT OR IGNORE INTO world_explored (world_id, character_id, tile_id) VALUES (?, ?, ?)
+			`, w.ID, charID, tileID)
+			if err != nil {
+				return fmt.Errorf("save explored: %w", err)
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+// Load retrieves a world by ID.
+// Returns (nil, nil) if not found.
+func (s *WorldStore) Load(ctx context.Context, id string) (*World, error) {
+	w := &World{ID: id, Positions: map[string]string{}, Explored: map[string][]string{}}
+	var hand string
+	err := s.db.QueryRowContext(ctx, `
+		SELECT name, seed, step, hand_json, created_at, updated_at FROM worlds WHERE id = ?
+	`, id).Scan(&w.Name, &w.Seed, &w.Step, &hand, &w.CreatedAt, &w.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scan world: %w", err)
+	}
+	if err := json.Unmarshal([]byte(hand), &w.Hand); err != nil {
+		return nil, fmt.Errorf("unmarshal hand: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `SELECT slot, tile_json FROM world_tiles WHERE world_id = ? ORDER BY slot`, id)
+	if err != nil {
+		return nil, fmt.Errorf("query tiles: %w", err)
+	}
+	defer rows.Close()
+	w.Tiles = []*Tile{}
+	for rows.Next() {
+		var slot int
+		var raw string
+		if err := rows.Scan(&slot, &raw); err != nil {
+			return nil, fmt.Errorf("scan tile: %w", err)
+		}
+		var t Tile
+		if err := json.Unmarshal([]byte(raw), &t); err != nil {
+			return nil, fmt.Errorf("unmarshal tile: %w", err)
+		}
+		t.X, t.Y = GridPos(slot) // the slot is authoritative; older rows predate coordinates
+		w.Tiles = append(w.Tiles, &t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	pairs := func(query string, fn func(charID, tileID string)) error {
+		rows, err := s.db.QueryContext(ctx, query, id)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var charID, tileID string
+			if err := rows.Scan(&charID, &tileID); err != nil {
+				return err
+			}
+			fn(charID, tileID)
+		}
+		return rows.Err()
+	}
+	if err := pairs(`SELECT character_id, tile_id FROM world_positions WHERE world_id = ?`, func(c, t string) {
+		w.Positions[c] = t
+	}); err != nil {
+		return nil, fmt.Errorf("load positions: %w", err)
+	}
+	if err := pairs(`SELECT character_id, tile_id FROM world_explored WHERE world_id = ? ORDER BY rowid`, func(c, t string) {
+		w.Explored[c] = append(w.Explored[c], t)
+	}); err != nil {
+		return nil, fmt.Errorf("load explored: %w", err)
+	}
+	return w, nil
+}
+
+// Update loads a world, applies fn, and saves it if fn succeeds.
+// Returns (nil, nil) if the world does not exist.
+func (s *WorldStore) Update(ctx context.Context, id string, fn func(*World) error) (*World, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	w, err := s.Load(ctx, id)
+	if err != nil || w == nil {
+		return nil, err
+	}
+	if err := fn(w); err != nil {
+		return nil, err
+	}
+	if err := s.Save(ctx, w); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
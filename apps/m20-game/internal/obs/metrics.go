@@ -36,6 +36,11 @@ var (
 		Help: "Total monsters defeated by type.",
 	}, []string{"monster_name"})
 
+	EncountersFinishedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "m20_encounters_finished_total",
+		Help: "Total server-side encounters finished by result.",
+	}, []string{"result"}) // victory | defeat | fled
+
 	CharactersCreatedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
 		Name: "m20_characters_created_total",
 		Help: "Total characters created by class.",
@@ -55,11 +60,47 @@ var (
 	AIRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
 		Name: "m20_ai_requests_total",
 		Help: "Total Ollama AI requests by type and status.",
-	}, []string{"type", "status"}) // type: riddle|dialogue  status: success|timeout|error
+	}, []string{"type", "status"}) // type: riddle|dialogue  status: success|timeout|canceled (client left a stream)|rejected (breaker open or queue full/expired)|invalid (reply failed validation)|error
 
 	AIRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
 		Name:    "m20_ai_request_duration_seconds",
 		Help:    "Ollama request latency in seconds.",
 		Buckets: []float64{0.1, 0.5, 1, 2, 5, 10, 30},
 	}, []string{"type"})
+
+	AIOutputRejectedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "m20_ai_output_rejected_total",
+		Help: "AI replies that failed validation and were replaced by a fallback, by type and reason.",
+	}, []string{"type", "reason"}) // reason: empty|format|echo|blocked|refusal
+
+	AIBreakerState = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "m20_ai_breaker_state",
+		Help: "AI circuit breaker state: 1 for the current state, 0 for the others.",
+	}, []string{"state"}) // closed | half_open | open
+
+	AIBreakerTransitionsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "m20_ai_breaker_transitions_total",
+		Help: "Total AI circuit breaker state changes by the state entered.",
+	}, []string{"to"})
+
+	AIInFlight = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "m20_ai_in_flight",
+		Help: "AI generations currently running.",
+	})
+
+	AIQueueDepth = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "m20_ai_queue_depth",
+		Help: "Callers waiting for an AI generation slot.",
+	})
+
+	NarrationPoolTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "m20_narration_pool_total",
+		Help: "Total pooled narration lookups by type and where the line came from.",
+	}, []string{"type", "result"}) // result: pool (pre-generated) | cache (repeated recent line) | miss (generated on request)
+
+	// Config subsystem.
+	ConfigReloadsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "m20_config_reloads_total",
+		Help: "Total config reload attempts by result.",
+	}, []string{"result"}) // success | failure (invalid config) | rejected (restart-only change)
 )
@@ -1,14 +1,11 @@
 package resources
 
-// EquipBonuses maps equippable item names to the per-stat bonuses they grant.
-// Using map[string]int per item avoids an import cycle between resources ↔ character.
-// Keys match the CharacterStats JSON field names (lowercase).
-var EquipBonuses = map[string]map[string]int{
-	"Reinforced Bat":     {"strength": 2},
-	"Improvised Armor":   {"stamina": 3},
-	"Medkit":             {"stamina": 1},
-	"Radio Beacon":       {"scouting": 2},
-	"Vehicle Repair Kit": {"salvaging": 2, "crafting": 1},
+// EquipBonuses maps equippable item names to the per-stat bonuses they grant,
+// from the active content pack. Using map[string]int per item avoids an
+// import cycle between resources ↔ character. Keys match the CharacterStats
+// JSON field names (lowercase). Treat the map as read-only.
+func EquipBonuses() map[string]map[string]int {
+	return Current().File.EquipBonuses
 }
 
 // EquipSlots returns the three valid equipment slot names.
@@ -18,8 +15,9 @@ func EquipSlots() []string {
 
 // EquippableItems returns all item names that can be equipped.
 func EquippableItems() []string {
-	names := make([]string, 0, len(EquipBonuses))
-	for name := range EquipBonuses {
+	bonuses := EquipBonuses()
+	names := make([]string, 0, len(bonuses))
+	for name := range bonuses {
 		names = append(names, name)
 	}
 	return names
@@ -27,6 +25,6 @@ func EquippableItems() []string {
 
 // IsEquippable returns true if the named item can be equipped.
 func IsEquippable(itemName string) bool {
-	_, ok := EquipBonuses[itemName]
+	_, ok := EquipBonuses()[itemName]
 	return ok
 }
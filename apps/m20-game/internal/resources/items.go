@@ -0,0 +1,52 @@
+package resources
+
+// Status conditions. Each lasts a number of encounter rounds and ticks at the
+// start of every round (see StatusConditions). The names are the keys the
+// default content pack uses; packs may add more.
+const (
+	StatusBleeding = "bleeding"
+	StatusPoisoned = "poisoned"
+	StatusBurning  = "burning"
+	StatusBuffed   = "buffed"
+)
+
+// StatusCondition is what a status does while it lasts.
+type StatusCondition struct {
+	Name           string `json:"name"`
+	Description    string `json:"description"`
+	DamagePerRound int    `json:"damage_per_round,omitempty"`
+	AttackBonus    int    `json:"attack_bonus,omitempty"` // added to the bearer's attack rolls
+}
+
+// StatusConditions lists every status in the active content pack by name.
+// Treat the map as read-only.
+func StatusConditions() map[string]StatusCondition {
+	return Current().File.Statuses
+}
+
+// ItemEffect is what using a consumable does. Using one consumes it
+// (see POST /api/character/:id/item/use).
+type ItemEffect struct {
+	Heal    int      `json:"heal,omitempty"`    // HP restored to the user
+	Cures   []string `json:"cures,omitempty"`   // statuses removed from the user
+	Applies string   `json:"applies,omitempty"` // status put on the user
+	Rounds  int      `json:"rounds,omitempty"`  // how long Applies or Inflicts lasts
+
+	// Thrown items only work in an encounter and hit every living monster.
+	Thrown   bool   `json:"thrown,omitempty"`
+	Damage   int    `json:"damage,omitempty"`
+	Inflicts string `json:"inflicts,omitempty"` // status put on each monster hit
+}
+
+// ItemEffects maps usable items to their effects, from the active content
+// pack. Treat the map as read-only.
+func ItemEffects() map[string]ItemEffect {
+	return Current().File.ItemEffects
+}
+
+// ItemEffectFor returns the effect of using itemName; ok is false if it
+// cannot be used.
+func ItemEffectFor(itemName string) (ItemEffect, bool) {
+	e, ok := ItemEffects()[itemName]
+	return e, ok
+}
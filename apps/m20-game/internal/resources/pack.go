@@ -0,0 +1,306 @@
+package resources
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// DefaultPackFile is the embedded content pack every load starts from.
+const DefaultPackFile = "content/default.json"
+
+//go:embed content/default.json
+var defaultFS embed.FS
+
+// PackFile is the on-disk shape of a content pack (JSON or YAML, snake_case
+// keys). Every section is optional in an override pack: list entries replace
+// the base entry with the same name or are appended, map entries replace by
+// key, and building_groups replace a building's whole list.
+type PackFile struct {
+	Version        string                     `json:"version"`
+	BaseStats      map[string]int             `json:"base_stats,omitempty"` // every class starts here, plus its bonus_stats
+	Classes        []CharacterClass           `json:"classes,omitempty"`
+	Monsters       []Monster                  `json:"monsters,omitempty"`
+	Buildings      []Building                 `json:"buildings,omitempty"`
+	BuildingGroups map[string][]GroupDef      `json:"building_groups,omitempty"`
+	SpecialGroups  []GroupDef                 `json:"special_groups,omitempty"`
+	FallbackGroup  *GroupDef                  `json:"fallback_group,omitempty"` // for buildings without groups
+	Tiles          []TileType                 `json:"tiles,omitempty"`
+	Supplies       []Supply                   `json:"supplies,omitempty"`
+	Recipes        []CraftableItem            `json:"recipes,omitempty"`
+	Vehicles       []Vehicle                  `json:"vehicles,omitempty"`
+	EquipBonuses   map[string]map[string]int  `json:"equip_bonuses,omitempty"`
+	ItemEffects    map[string]ItemEffect      `json:"item_effects,omitempty"`
+	Statuses       map[string]StatusCondition `json:"statuses,omitempty"`
+}
+
+// GroupDef is a monster group as written in a pack: monsters by name.
+type GroupDef struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Monsters    []string `json:"monsters"` // ordered: weakest first, boss last
+	Difficulty  int      `json:"difficulty"`
+	MinLevel    int      `json:"min_level"`
+}
+
+// PackSource is one file that went into the loaded pack.
+type PackSource struct {
+	Source  string `json:"source"`
+	Version string `json:"version,omitempty"`
+}
+
+// PackInfo describes the loaded pack for /api/items. Version is the last
+// declared version among its sources.
+type PackInfo struct {
+	Version string       `json:"version"`
+	Sources []PackSource `json:"sources"`
+}
+
+// Pack is a content pack. Once resolved (LoadPack, or ReadPack then Resolve)
+// treat it as read-only.
+type Pack struct {
+	Info PackInfo
+	File PackFile // merged file contents, before resolution
+
+	classes        []CharacterClass
+	buildingGroups map[string][]MonsterGroup
+	specialGroups  map[string]MonsterGroup
+	fallbackGroup  MonsterGroup
+}
+
+var active atomic.Pointer[Pack]
+
+func init() {
+	p, err := LoadPack(nil)
+	if err != nil {
+		panic("resources: embedded default pack: " + err.Error())
+	}
+	Use(p)
+}
+
+// Use makes p the pack every accessor in this package reads from.
+func Use(p *Pack) {
+	active.Store(p)
+}
+
+// Current returns the active pack.
+func Current() *Pack {
+	return active.Load()
+}
+
+// LoadPack reads a pack with ReadPack and resolves it. Resolve stops at the
+// first broken group, so to report every problem, ReadPack, Validate, and
+// then Resolve instead.
+func LoadPack(dirs []string) (*Pack, error) {
+	p, err := ReadPack(dirs)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.Resolve(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// ReadPack reads the embedded default pack, then every *.json, *.yaml, and
+// *.yml file in each override directory (in name order) on top of it. Unknown
+// keys are an error, so a typo in a pack fails loudly instead of silently
+// doing nothing. The pack is not usable until Resolve succeeds.
+func ReadPack(dirs []string) (*Pack, error) {
+	data, err := defaultFS.ReadFile(DefaultPackFile)
+	if err != nil {
+		return nil, err
+	}
+	merged, err := decodePack(DefaultPackFile, data)
+	if err != nil {
+		return nil, err
+	}
+	info := PackInfo{Version: merged.Version, Sources: []PackSource{{Source: "embedded:" + DefaultPackFile, Version: merged.Version}}}
+
+	for _, dir := range dirs {
+		files, err := packFiles(dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range files {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("read content pack: %w", err)
+			}
+			over, err := decodePack(path, data)
+			if err != nil {
+				return nil, err
+			}
+			merged.merge(over)
+			info.Sources = append(info.Sources, PackSource{Source: path, Version: over.Version})
+			if over.Version != "" {
+				info.Version = over.Version
+			}
+		}
+	}
+	merged.Version = info.Version
+	return &Pack{Info: info, File: merged}, nil
+}
+
+// packFiles lists the pack files in dir, sorted by name.
+func packFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read content dir: %w", err)
+	}
+	var files []string
+	for _, e := range entries {
+		switch strings.ToLower(filepath.Ext(e.Name())) {
+		case ".json", ".yaml", ".yml":
+			if !e.IsDir() {
+				files = append(files, filepath.Join(dir, e.Name()))
+			}
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// decodePack parses a JSON or YAML pack. YAML is converted to JSON first so
+// both formats share the json tags and the unknown-field check.
+func decodePack(name string, data []byte) (PackFile, error) {
+	if ext := strings.ToLower(filepath.Ext(name)); ext == ".yaml" || ext == ".yml" {
+		var doc interface{}
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return PackFile{}, fmt.Errorf("parse content pack %s: %w", name, err)
+		}
+		var err error
+		if data, err = json.Marshal(doc); err != nil {
+			return PackFile{}, fmt.Errorf("parse content pack %s: %w", name, err)
+		}
+	}
+	var f PackFile
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&f); err != nil {
+		return PackFile{}, fmt.Errorf("parse content pack %s: %w", name, err)
+	}
+	return f, nil
+}
+
+// merge lays over on top of f.
+func (f *PackFile) merge(over PackFile) {
+	if over.BaseStats != nil {
+		f.BaseStats = over.BaseStats
+	}
+	f.Classes = mergeByName(f.Classes, over.Classes, func(c CharacterClass) string { return c.Name })
+	f.Monsters = mergeByName(f.Monsters, over.Monsters, func(m Monster) string { return m.Name })
+	f.Buildings = mergeByName(f.Buildings, over.Buildings, func(b Building) string { return b.Name })
+	f.SpecialGroups = mergeByName(f.SpecialGroups, over.SpecialGroups, func(g GroupDef) string { return g.Name })
+	f.Tiles = mergeByName(f.Tiles, over.Tiles, func(t TileType) string { return t.Name })
+	f.Supplies = mergeByName(f.Supplies, over.Supplies, func(s Supply) string { return s.Name })
+	f.Recipes = mergeByName(f.Recipes, over.Recipes, func(c CraftableItem) string { return c.Name })
+	f.Vehicles = mergeByName(f.Vehicles, over.Vehicles, func(v Vehicle) string { return v.Name })
+	if over.FallbackGroup != nil {
+		f.FallbackGroup = over.FallbackGroup
+	}
+	f.BuildingGroups = mergeMap(f.BuildingGroups, over.BuildingGroups)
+	f.EquipBonuses = mergeMap(f.EquipBonuses, over.EquipBonuses)
+	f.ItemEffects = mergeMap(f.ItemEffects, over.ItemEffects)
+	f.Statuses = mergeMap(f.Statuses, over.Statuses)
+}
+
+func mergeByName[T any](base, over []T, name func(T) string) []T {
+	out := append([]T(nil), base...)
+	for _, o := range over {
+		replaced := false
+		for i := range out {
+			if name(out[i]) == name(o) {
+				out[i], replaced = o, true
+				break
+			}
+		}
+		if !replaced {
+			out = append(out, o)
+		}
+	}
+	return out
+}
+
+func mergeMap[V any](base, over map[string]V) map[string]V {
+	out := make(map[string]V, len(base)+len(over))
+	for k, v := range base {
+		out[k] = v
+	}
+	for k, v := range over {
+		out[k] = v
+	}
+	return out
+}
+
+// Resolve turns group definitions into monster groups and applies class
+// bonus stats on top of the base stats. It fails on the first group with an
+// unknown monster; Validate lists them all.
+func (p *Pack) Resolve() error {
+	f := &p.File
+	byName := make(map[string]Monster, len(f.Monsters))
+	for _, m := range f.Monsters {
+		byName[m.Name] = m
+	}
+	group := func(d GroupDef) (MonsterGroup, error) {
+		g := MonsterGroup{Name: d.Name, Description: d.Description, Difficulty: d.Difficulty, MinLevel: d.MinLevel}
+		if len(d.Monsters) == 0 {
+			return g, fmt.Errorf("monster group %q: no monsters", d.Name)
+		}
+		for _, name := range d.Monsters {
+			m, ok := byName[name]
+			if !ok {
+				return g, fmt.Errorf("monster group %q: unknown monster %q", d.Name, name)
+			}
+			g.Monsters = append(g.Monsters, m)
+		}
+		return g, nil
+	}
+
+	p.buildingGroups = make(map[string][]MonsterGroup, len(f.BuildingGroups))
+	for building, defs := range f.BuildingGroups {
+		for _, d := range defs {
+			g, err := group(d)
+			if err != nil {
+				return fmt.Errorf("building %q: %w", building, err)
+			}
+			p.buildingGroups[building] = append(p.buildingGroups[building], g)
+		}
+	}
+	p.specialGroups = make(map[string]MonsterGroup, len(f.SpecialGroups))
+	for _, d := range f.SpecialGroups {
+		g, err := group(d)
+		if err != nil {
+			return err
+		}
+		p.specialGroups[d.Name] = g
+	}
+	if f.FallbackGroup == nil {
+		return fmt.Errorf("content pack has no fallback_group")
+	}
+	var err error
+	if p.fallbackGroup, err = group(*f.FallbackGroup); err != nil {
+		return fmt.Errorf("fallback_group: %w", err)
+	}
+
+	p.classes = make([]CharacterClass, len(f.Classes))
+	for i, c := range f.Classes {
+		c.BaseStats = make(map[string]int, len(f.BaseStats))
+		for stat, v := range f.BaseStats {
+			c.BaseStats[stat] = v
+		}
+		for stat, bonus := range c.BonusStats {
+			c.BaseStats[stat] += bonus
+		}
+		p.classes[i] = c
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package resources
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// statNames mirrors character.StatNames, which this package can't import.
+var statNames = []string{"strength", "stamina", "marksmanship", "scouting", "scavenging", "crafting", "salvaging"}
+
+func writePackFile(t *testing.T, dir, name, body string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0o600); err != nil {
+		t.Fatalf("write pack file: %v", err)
+	}
+}
+
+func TestDefaultPack_Validates(t *testing.T) {
+	p, err := ReadPack(nil)
+	if err != nil {
+		t.Fatalf("ReadPack failed: %v", err)
+	}
+	if err := p.Validate(statNames); err != nil {
+		t.Errorf("Expected the embedded pack to validate, got: %v", err)
+	}
+	if err := p.Resolve(); err != nil {
+		t.Errorf("Expected the embedded pack to resolve, got: %v", err)
+	}
+}
+
+func TestReadPack_Overrides(t *testing.T) {
+	dir := t.TempDir()
+	// Files apply in name order, so the YAML file lands on top of the JSON one.
+	writePackFile(t, dir, "10-monsters.json", `{
+		"version": "1.1.0",
+		"monsters": [
+			{"name": "Zombie", "hp": 99, "attack": 1, "defense": 8, "xp_reward": 50},
+			{"name": "Mall Cop", "hp": 10, "attack": 2, "defense": 9, "xp_reward": 40}
+		],
+		"statuses": {"dazed": {"name": "dazed", "description": "Seeing stars."}}
+	}`)
+	writePackFile(t, dir, "20-groups.yaml", `
+special_groups:
+  - name: Security Detail
+    monsters: [Mall Cop, Zombie]
+    difficulty: 2
+    min_level: 1
+`)
+	writePackFile(t, dir, "notes.txt", "not a pack")
+
+	p, err := LoadPack([]string{dir})
+	if err != nil {
+		t.Fatalf("LoadPack failed: %v", err)
+	}
+	if p.Info.Version != "1.1.0" {
+		t.Errorf("Expected version 1.1.0 from the override, got '%s'", p.Info.Version)
+	}
+	if len(p.Info.Sources) != 3 {
+		t.Errorf("Expected 3 sources (embedded + 2 files), got %d: %+v", len(p.Info.Sources), p.Info.Sources)
+	}
+
+	base, _ := ReadPack(nil)
+	if len(p.File.Monsters) != len(base.File.Monsters)+1 {
+		t.Errorf("Expected Zombie replaced and Mall Cop appended, got %d monsters (base %d)", len(p.File.Monsters), len(base.File.Monsters))
+	}
+	for _, m := range p.File.Monsters {
+		if m.Name == "Zombie" && m.HP != 99 {
+			t.Errorf("Expected the override Zombie (99 HP), got %d HP", m.HP)
+		}
+	}
+	if _, ok := p.File.Statuses["bleeding"]; !ok {
+		t.Error("Expected base statuses kept when an override adds one")
+	}
+	if _, ok := p.File.Statuses["dazed"]; !ok {
+		t.Error("Expected the override status added")
+	}
+	g, ok := p.specialGroups["Security Detail"]
+	if !ok || len(g.Monsters) != 2 || g.Monsters[0].Name != "Mall Cop" {
+		t.Errorf("Expected the YAML group resolved to Mall Cop + Zombie, got %+v", g)
+	}
+}
+
+func TestReadPack_UnknownField(t *testing.T) {
+	dir := t.TempDir()
+	writePackFile(t, dir, "typo.json", `{"monstres": []}`)
+	if _, err := ReadPack([]string{dir}); err == nil || !strings.Contains(err.Error(), "monstres") {
+		t.Errorf("Expected an unknown field error naming 'monstres', got: %v", err)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	base := PackFile{
+		BaseStats: map[string]int{"strength": 3},
+		Monsters:  []Monster{{Name: "Zombie", HP: 8}, {Name: "Ghoul", HP: 10}},
+		EquipBonuses: map[string]map[string]int{
+			"Reinforced Bat": {"strength": 2},
+		},
+	}
+	base.merge(PackFile{
+		Monsters:     []Monster{{Name: "Ghoul", HP: 12}, {Name: "Wraith", HP: 6}},
+		EquipBonuses: map[string]map[string]int{"Improvised Armor": {"stamina": 2}},
+	})
+
+	cases := []struct {
+		what string
+		got  any
+		want any
+	}{
+		{"monster count", len(base.Monsters), 3},
+		{"Zombie kept", base.Monsters[0].HP, 8},
+		{"Ghoul replaced in place", base.Monsters[1].HP, 12},
+		{"Wraith appended", base.Monsters[2].Name, "Wraith"},
+		{"base stats kept without an override", base.BaseStats["strength"], 3},
+		{"equip bonus kept", base.EquipBonuses["Reinforced Bat"]["strength"], 2},
+		{"equip bonus added", base.EquipBonuses["Improvised Armor"]["stamina"], 2},
+	}
+	for _, tc := range cases {
+		if tc.got != tc.want {
+			t.Errorf("%s: got %v, want %v", tc.what, tc.got, tc.want)
+		}
+	}
+}
+
+func TestValidate_ReportsEveryProblem(t *testing.T) {
+	cases := []struct {
+		name   string
+		mutate func(f *PackFile)
+		want   []string
+	}{
+		{
+			name: "unknown group monster",
+			mutate: func(f *PackFile) {
+				f.SpecialGroups = append(f.SpecialGroups, GroupDef{Name: "Bad", Monsters: []string{"Dragon"}})
+			},
+			want: []string{`special group "Bad": unknown monster "Dragon"`},
+		},
+		{
+			name: "loot, materials, and equip bonuses",
+			mutate: func(f *PackFile) {
+				f.Buildings[0].Loot = append(f.Buildings[0].Loot, "Gold Bar")
+				f.Recipes[0].Materials = append(f.Recipes[0].Materials, "Moon Rock")
+				f.EquipBonuses["Bandage"] = map[string]int{"charisma": 1}
+			},
+			want: []string{
+				`loot "Gold Bar" is not a supply or recipe`,
+				`material "Moon Rock" is not a supply or recipe`,
+				`equip_bonuses: "Bandage" is not an equippable recipe`,
+				`equip_bonuses "Bandage": unknown stat "charisma"`,
+			},
+		},
+		{
+			name: "duplicates and statuses",
+			mutate: func(f *PackFile) {
+				f.Monsters = append(f.Monsters, f.Monsters[0])
+				f.Monsters[1].Inflicts = "cursed"
+				f.FallbackGroup = nil
+			},
+			want: []string{
+				`duplicate monster "Zombie"`,
+				`unknown status "cursed"`,
+				"no fallback_group",
+			},
+		},
+	}
+	for _, tc := range cases {
+		p, err := ReadPack(nil)
+		if err != nil {
+			t.Fatalf("ReadPack failed: %v", err)
+		}
+		tc.mutate(&p.File)
+		err = p.Validate(statNames)
+		if err == nil {
+			t.Errorf("%s: expected errors, got nil", tc.name)
+			continue
+		}
+		for _, want := range tc.want {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("%s: expected %q among:\n%v", tc.name, want, err)
+			}
+		}
+	}
+}
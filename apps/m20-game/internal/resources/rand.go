@@ -2,10 +2,10 @@ package resources
 
 import "math/rand"
 
-// randN returns a random int in [0, n). Panics if n <= 0.
-func randN(n int) int {
+// randN returns a random int in [0, n) from rng, or 0 if n <= 0.
+func randN(rng *rand.Rand, n int) int {
 	if n <= 0 {
 		return 0
 	}
-	return rand.Intn(n)
+	return rng.Intn(n)
 }
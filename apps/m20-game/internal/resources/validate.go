@@ -0,0 +1,158 @@
+package resources
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Validate checks every cross-reference in the pack: group monsters and
+// buildings, building loot and recipe materials against known items, equip
+// bonuses against equippable recipes, stat keys against statNames, and status
+// names against the pack's statuses. It reports every problem at once,
+// joined into one error, or nil if the pack is consistent.
+//
+// statNames is passed in because the stat fields live in the character
+// package, which imports this one (see character.StatNames).
+func (p *Pack) Validate(statNames []string) error {
+	f := &p.File
+	var errs []error
+	bad := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	stats := set(statNames)
+	monsters := names(f.Monsters, func(m Monster) string { return m.Name }, "monster", bad)
+	buildings := names(f.Buildings, func(b Building) string { return b.Name }, "building", bad)
+	supplies := names(f.Supplies, func(s Supply) string { return s.Name }, "supply", bad)
+	recipes := names(f.Recipes, func(c CraftableItem) string { return c.Name }, "recipe", bad)
+	names(f.Classes, func(c CharacterClass) string { return c.Name }, "class", bad)
+	names(f.Tiles, func(t TileType) string { return t.Name }, "tile", bad)
+	names(f.Vehicles, func(v Vehicle) string { return v.Name }, "vehicle", bad)
+	names(f.SpecialGroups, func(g GroupDef) string { return g.Name }, "special group", bad)
+	item := func(name string) bool { return supplies[name] || recipes[name] }
+
+	checkGroup := func(where string, d GroupDef) {
+		if len(d.Monsters) == 0 {
+			bad("%s group %q: no monsters", where, d.Name)
+		}
+		for _, m := range d.Monsters {
+			if !monsters[m] {
+				bad("%s group %q: unknown monster %q", where, d.Name, m)
+			}
+		}
+	}
+	for _, building := range sortedKeys(f.BuildingGroups) {
+		if !buildings[building] {
+			bad("building_groups: %q is not a building", building)
+		}
+		for _, d := range f.BuildingGroups[building] {
+			checkGroup(fmt.Sprintf("building %q", building), d)
+		}
+	}
+	for _, d := range f.SpecialGroups {
+		checkGroup("special", d)
+	}
+	if f.FallbackGroup != nil {
+		checkGroup("fallback", *f.FallbackGroup)
+	} else {
+		bad("content pack has no fallback_group")
+	}
+
+	for _, b := range f.Buildings {
+		for _, loot := range b.Loot {
+			if !item(loot) {
+				bad("building %q: loot %q is not a supply or recipe", b.Name, loot)
+			}
+		}
+	}
+	for _, c := range f.Recipes {
+		for _, m := range c.Materials {
+			if !item(m) {
+				bad("recipe %q: material %q is not a supply or recipe", c.Name, m)
+			}
+		}
+	}
+
+	equippable := make(map[string]bool, len(f.Recipes))
+	for _, c := range f.Recipes {
+		equippable[c.Name] = c.Equippable
+	}
+	for _, name := range sortedKeys(f.EquipBonuses) {
+		if !equippable[name] {
+			bad("equip_bonuses: %q is not an equippable recipe", name)
+		}
+		for _, stat := range sortedKeys(f.EquipBonuses[name]) {
+			if !stats[stat] {
+				bad("equip_bonuses %q: unknown stat %q", name, stat)
+			}
+		}
+	}
+	for _, stat := range sortedKeys(f.BaseStats) {
+		if !stats[stat] {
+			bad("base_stats: unknown stat %q", stat)
+		}
+	}
+	for _, c := range f.Classes {
+		for _, stat := range sortedKeys(c.BonusStats) {
+			if !stats[stat] {
+				bad("class %q: unknown stat %q", c.Name, stat)
+			}
+		}
+	}
+
+	status := func(where, name string) {
+		if _, ok := f.Statuses[name]; name != "" && !ok {
+			bad("%s: unknown status %q", where, name)
+		}
+	}
+	for _, m := range f.Monsters {
+		status(fmt.Sprintf("monster %q inflicts", m.Name), m.Inflicts)
+	}
+	for _, name := range sortedKeys(f.ItemEffects) {
+		e := f.ItemEffects[name]
+		if !item(name) {
+			bad("item_effects: %q is not a supply or recipe", name)
+		}
+		for _, c := range e.Cures {
+			status(fmt.Sprintf("item %q cures", name), c)
+		}
+		status(fmt.Sprintf("item %q applies", name), e.Applies)
+		status(fmt.Sprintf("item %q inflicts", name), e.Inflicts)
+	}
+	return errors.Join(errs...)
+}
+
+// names indexes list by name, reporting blank and duplicate names.
+func names[T any](list []T, name func(T) string, kind string, bad func(string, ...any)) map[string]bool {
+	seen := make(map[string]bool, len(list))
+	for _, v := range list {
+		n := name(v)
+		switch {
+		case n == "":
+			bad("%s with no name", kind)
+		case seen[n]:
+			bad("duplicate %s %q", kind, n)
+		}
+		seen[n] = true
+	}
+	return seen
+}
+
+func set(list []string) map[string]bool {
+	s := make(map[string]bool, len(list))
+	for _, v := range list {
+		s[v] = true
+	}
+	return s
+}
+
+// sortedKeys returns m's keys in order so problems are reported stably.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
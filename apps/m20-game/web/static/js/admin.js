@@ -25,6 +25,8 @@
     bindTest('#test-craft-item',        testCraftItem);
     bindTest('#test-equip-item',        testEquipItem);
     bindTest('#test-drop-item',         testDropItem);
+    bindTest('#test-use-item',          testUseItem);
+    bindTest('#test-char-scavenge',     testCharScavenge);
     bindTest('#test-levelup',           testLevelUp);
     bindTest('#test-items',             testItems);
     bindTest('#load-metrics',           loadMetrics);
@@ -173,6 +175,7 @@
     if (loc)         payload.location = loc;
     put('/api/character/' + id,
       payload,
+      $('#upd-token').val(),
       function (d) { showResult('#out-update-char', d); },
       function (x) { showErr('#out-update-char', x); }
     );
@@ -209,6 +212,25 @@
     );
   }
 
+  function testUseItem() {
+    var id   = charID(); if (!id) return;
+    var name = $('#use-item-name').val().trim() || 'Bandage';
+    post('/api/character/' + id + '/item/use',
+      { item_name: name },
+      function (d) { showResult('#out-use-item', d); },
+      function (x) { showErr('#out-use-item', x); }
+    );
+  }
+
+  function testCharScavenge() {
+    var id = charID(); if (!id) return;
+    post('/api/character/' + id + '/scavenge',
+      { world_id: $('#scavenge-world').val().trim() },
+      function (d) { showResult('#out-char-action', d); },
+      function (x) { showErr('#out-char-action', x); }
+    );
+  }
+
   function testLevelUp() {
     var id = charID(); if (!id) return;
     post('/api/character/' + id + '/levelup',
@@ -240,10 +262,11 @@
     }).done(onDone).fail(onFail);
   }
 
-  function put(url, data, onDone, onFail) {
+  function put(url, data, token, onDone, onFail) {
     $.ajax({
       url:         url,
       method:      'PUT',
+      headers:     { 'X-Admin-Token': token },
       contentType: 'application/json',
       data:        JSON.stringify(data),
       dataType:    'json',
@@ -14,42 +14,34 @@
     // Core character / party
     character:        null,    // active character (primary party member)
     party:            [],      // array of Character objects (up to 4)
+    partyID:          null,    // server party (/api/party) — members, leader, stash
     activePartyIdx:   0,       // which party member is acting in combat
 
     // Map & exploration
-    map:              null,
-    placedTiles:      [],      // tiles placed on map (in order)
-    tileHand:         [],      // 2 tile options drawn for selection
-    exitTileIdx:      -1,      // index in placedTiles of exit tile (-1 = not yet)
+    world:            null,    // server world view {id, tiles, hand, fog, positions}
     currentTile:      null,
 
-    // Combat
-    encounter:        null,
-    combatQueue:      [],
-    activeMonster:    null,
-    clearedBuildings: new Set(),
-    firstStrike:      false,
-
-    // Initiative system
-    initiativeOrder:  [],      // [{type, name, idx, initiative, ref?, hp?, maxHp?}]
-    initiativeTurn:   0,       // index into initiativeOrder
+    // Combat — the server owns initiative, HP, and XP (/api/encounter)
+    encounter:        null,    // building being explored
+    fight:            null,    // server encounter {id, status, turn, order, log}
+    riddle:           null,    // server riddle session {id, riddle, attempts_left, status}
+    narration:        null,    // open EventSource for streamed narration
 
     // Shared game data from /api/items
     equipBonuses:     {},
     specialGroups:    {},
+    itemEffects:      {},
   };
 
   const API = {
-    tile:          '/api/tile',
-    land:          '/api/land',
-    scavenge:      '/api/scavenge',
     items:         '/api/items',
     craft:         '/api/craft',
-    combat:        '/api/combat/roll',
-    encounter:     '/api/combat/encounter',
+    fight:         '/api/encounter',
+    world:         '/api/world',
     buildingEnter: '/api/building/enter',
-    riddle:        '/api/ai/riddle',
+    riddle:        '/api/riddle',
     character:     '/api/character',
+    party:         '/api/party',
   };
 
   // Class → emoji mapping
@@ -90,6 +82,8 @@
     $('#draw-tile-btn').on('click', drawTileHand);
     $('#scavenge-btn').on('click', doScavenge);
     $('#riddle-btn').on('click', doRiddle);
+    $('#riddle-guess-btn').on('click', doRiddleGuess);
+    $('#riddle-guess').on('keydown', function (e) { if (e.key === 'Enter') doRiddleGuess(); });
     $('#craft-check-btn').on('click', doCraftCheck);
     $('#back-to-map-btn').on('click', hideTilePanel);
     $('#back-to-tile-btn').on('click', hideBuildingPanel);
@@ -108,12 +102,33 @@
     get(API.items).done(function (data) {
       state.equipBonuses  = data.equip_bonuses  || {};
       state.specialGroups = data.special_groups || {};
+      state.itemEffects   = data.item_effects   || {};
     });
   }
 
   // ── Session restore ───────────────────────────────────────────────────
   function restoreSession() {
-    // Restore all party members from localStorage
+    // The party lives on the server; localStorage only remembers its ID.
+    const partyID = localStorage.getItem('m20_party_id');
+    if (partyID) {
+      $.ajax({ url: API.party + '/' + partyID, method: 'GET', dataType: 'json' })
+        .done(function (data) {
+          state.partyID = data.party.id;
+          data.members.forEach(function (c) { addToParty(c, true); });
+          if (!state.party.length) return;
+          showGame();
+          log('Welcome back! Party of ' + state.party.length + ' restored.', 'success');
+          loadWorld();
+        })
+        .fail(function () { localStorage.removeItem('m20_party_id'); });
+      return;
+    }
+    restoreLegacyParty();
+  }
+
+  // restoreLegacyParty migrates a party saved by older builds as a list of
+  // character IDs into a server party.
+  function restoreLegacyParty() {
     const ids = JSON.parse(localStorage.getItem('m20_party_ids') || '[]');
     if (!ids.length) {
       const single = localStorage.getItem('m20_char_id');
@@ -132,40 +147,60 @@
             const valid = results.filter(Boolean);
             if (valid.length > 0) {
               valid.forEach(function (c) { addToParty(c, true); });
+              createServerParty().done(function () {
+                localStorage.removeItem('m20_party_ids');
+              });
               showGame();
               log('Welcome back! Party of ' + valid.length + ' restored.', 'success');
-              drawTileHand();
+              loadWorld();
             }
           }
         });
     });
   }
 
-  function savePartyIDs() {
+  function createServerParty() {
     const ids = state.party.map(function (c) { return c.id; });
-    localStorage.setItem('m20_party_ids', JSON.stringify(ids));
-    if (ids.length > 0) {
-      localStorage.setItem('m20_char_id', ids[0]);
+    return post(API.party, {
+      leader_id:  ids[0],
+      member_ids: ids.slice(1),
+    }).done(function (data) { savePartyID(data.party.id); });
+  }
+
+  // joinServerParty adds a freshly created character to the party, creating
+  // the party for the first survivor.
+  function joinServerParty(c) {
+    if (!state.partyID) return createServerParty();
+    return post(API.party + '/' + state.partyID + '/join', { character_id: c.id });
+  }
+
+  function savePartyID(id) {
+    state.partyID = id;
+    localStorage.setItem('m20_party_id', id);
+    if (state.party.length > 0) {
+      localStorage.setItem('m20_char_id', state.party[0].id);
     }
   }
 
-  // ── Persist character to server ───────────────────────────────────────
-  function saveCharacter(char) {
-    if (!char || !char.id) return;
-    return put(API.character + '/' + char.id, {
-      hp:        char.hp,
-      xp:        char.xp,
-      level:     char.level,
-      inventory: char.inventory,
-      equipment: char.equipment || {},
-      location:  char.location || 'tile-01',
-    }).done(function (saved) {
-      // Sync saved state back (server may adjust HP bounds etc.)
-      const idx = state.party.findIndex(function (c) { return c.id === saved.id; });
-      if (idx !== -1) {
-        state.party[idx] = saved;
-        if (idx === 0) state.character = saved;
-      }
+  // ── Reload party from server ──────────────────────────────────────────
+  // Characters only change through server actions; after a fight, pull the
+  // saved HP/XP back and level up anyone who earned it.
+  function refreshParty() {
+    state.party.forEach(function (c) {
+      get(API.character + '/' + c.id).done(function (fresh) {
+        syncCharacter(fresh);
+        if (fresh.xp >= fresh.level * 100) {
+          post(API.character + '/' + fresh.id + '/levelup', {}).done(function (leveled) {
+            syncCharacter(leveled);
+            log('✨ ' + leveled.name + ' LEVEL UP! Now level ' + leveled.level + '. Max HP +4!', 'success');
+            renderStats();
+            renderPartyBar();
+          });
+        }
+        renderStats();
+        renderPartyBar();
+        renderInventoryGrid(state.character);
+      });
     });
   }
 
@@ -205,10 +240,10 @@
       class: $('#char-class').val() || '',
     }).done(function (c) {
       addToParty(c, false);
-      savePartyIDs();
+      joinServerParty(c);
       showGame();
       log(c.name + ' the ' + c.class + ' joins the party! HP: ' + c.hp + '/' + c.max_hp, 'success');
-      if (state.placedTiles.length === 0) drawTileHand();
+      if (!state.world) loadWorld();
     });
   }
 
@@ -288,7 +323,8 @@
 
     $('#char-name-display').text(c.name);
     $('#char-class-display').text('[' + c.class + ']');
-    $('#hp-display').text('HP ' + c.hp + '/' + c.max_hp);
+    $('#hp-display').text('HP ' + c.hp + '/' + c.max_hp +
+      (c.statuses || []).map(function (st) { return ' · ' + st.name; }).join(''));
 
     const stats  = c.stats;
     const labels = {
@@ -351,7 +387,7 @@
   function showItemMenu($slot, itemName, char) {
     $('.item-menu').remove();
     const isEquippable = !!state.equipBonuses[itemName];
-    const isMedical    = ['Bandage', 'Painkillers', 'First Aid Kit', 'Medkit'].includes(itemName);
+    const effect       = state.itemEffects[itemName];
     const equip        = char.equipment || {};
     const isEquipped   = equip.weapon === itemName || equip.armor === itemName || equip.accessory === itemName;
 
@@ -364,9 +400,12 @@
     if (isEquipped) {
       menuHTML += '<button class="item-menu-btn" data-action="unequip">↩ Unequip</button>';
     }
-    if (isMedical) {
+    if (effect && !effect.thrown) {
       menuHTML += '<button class="item-menu-btn" data-action="use">💊 Use</button>';
     }
+    if (effect && effect.thrown && state.fight) {
+      menuHTML += '<button class="item-menu-btn" data-action="use">🔥 Throw</button>';
+    }
     menuHTML += '<button class="item-menu-btn danger" data-action="drop">🗑 Drop</button>';
     menuHTML += '</div>';
 
@@ -517,6 +556,26 @@
       const c        = state.party.find(function (x) { return x.id === charId; }) || state.character;
       doCraftItem(c, itemName);
     });
+
+    // Vehicles
+    let vehHTML = '';
+    (char.vehicles || []).forEach(function (v) {
+      vehHTML += '<div class="craft-item">' +
+                 '<strong>' + v.name + '</strong> · ' + v.condition +
+                 ' · speed ' + v.speed + ' · seats ' + v.capacity +
+                 (v.fuel_needed ? ' · fuel ' + v.fuel : '') +
+                 '<br>' +
+                 (v.condition !== 'operational' ? '<button class="btn-sm vehicle-btn" data-action="repair" data-vid="' + v.id + '" data-char-id="' + char.id + '">Repair</button> ' : '') +
+                 (v.fuel_needed ? '<button class="btn-sm vehicle-btn" data-action="refuel" data-vid="' + v.id + '" data-char-id="' + char.id + '">Refuel</button>' : '') +
+                 '</div>';
+    });
+    $('#csh-vehicles').html(vehHTML || '<p style="font-size:12px;color:var(--muted)">No vehicles. Search highways and gas stations.</p>');
+
+    $('#csh-vehicles').off('click', '.vehicle-btn').on('click', '.vehicle-btn', function () {
+      const charId = $(this).data('char-id');
+      const c      = state.party.find(function (x) { return x.id === charId; }) || state.character;
+      doVehicleAction(c, $(this).data('vid'), $(this).data('action'));
+    });
   }
 
   // ── Inventory actions ─────────────────────────────────────────────────
@@ -553,15 +612,29 @@
   }
 
   function doUseItem(char, itemName) {
-    // Use a medical item: restore HP then drop
-    const healing = { 'Bandage': 3, 'Painkillers': 2, 'First Aid Kit': 6, 'Medkit': 10 };
-    const amount  = healing[itemName] || 2;
-    const newHP   = Math.min(char.hp + amount, char.max_hp);
-    char.hp = newHP;
-    log('Used ' + itemName + ': +' + amount + ' HP → ' + char.hp + '/' + char.max_hp, 'success');
-    doDropItem(char, itemName);
-    renderStats();
-    renderPartyBar();
+    // The server applies the effect and consumes the item. Mid-fight it costs
+    // the character's turn.
+    const body = { item_name: itemName };
+    if (state.fight) body.encounter_id = state.fight.id;
+    post(API.character + '/' + char.id + '/item/use', body)
+      .done(function (data) {
+        const c = data.character;
+        syncCharacter(c);
+        if (data.encounter) {
+          renderInventoryGrid(state.character);
+          if ($('#char-sheet-modal').is(':not(.hidden)')) renderCharSheet(c);
+          applyFight(data.encounter, data.events);
+          return;
+        }
+        let msg = 'Used ' + itemName + ': +' + data.healed + ' HP → ' + c.hp + '/' + c.max_hp;
+        if (data.cured && data.cured.length) msg += ', no longer ' + data.cured.join(' or ');
+        if (data.applied) msg += ', ' + data.applied;
+        log(msg, 'success');
+        renderStats();
+        renderPartyBar();
+        renderInventoryGrid(state.character);
+        if ($('#char-sheet-modal').is(':not(.hidden)')) renderCharSheet(c);
+      });
   }
 
   function doCraftItem(char, itemName) {
@@ -574,36 +647,81 @@
       });
   }
 
+  function doVehicleAction(char, vehicleID, action) {
+    post(API.character + '/' + char.id + '/vehicles/' + vehicleID + '/' + action, {})
+      .done(function (data) {
+        syncCharacter(data.character);
+        if (data.repair) {
+          log('🔧 Repair ' + data.repair.roll.total + ' vs ' + data.repair.roll.target + ' (used ' + data.repair.consumed.join(', ') + '): ' + data.repair.text,
+              data.repair.repaired ? 'success' : 'warning');
+        } else {
+          log('⛽ ' + data.vehicle.name + ' refuelled: ' + data.vehicle.fuel + ' drives left.', 'success');
+        }
+        renderInventoryGrid(state.character);
+        renderCharSheet(data.character);
+      });
+  }
+
   function syncCharacter(updated) {
     const idx = state.party.findIndex(function (c) { return c.id === updated.id; });
     if (idx !== -1) {
       state.party[idx] = updated;
-      if (idx === 0) {
+      if (idx === 0 || (state.character && state.character.id === updated.id)) {
         state.character = updated;
       }
     }
   }
 
+  // ── World — persistent map, fog of war is server-side ─────────────────
+  function loadWorld() {
+    const id = localStorage.getItem('m20_world_id');
+    if (!id) { newWorld(0); return; }
+    // Plain $.ajax: a missing world (fresh DB) just starts a new one, no banner.
+    $.ajax({ url: API.world + '/' + id, method: 'GET', dataType: 'json' })
+      .done(function (world) {
+        setWorld(world);
+        if (world.hand.length) renderTileHand();
+        else if (!world.tiles.length) drawTileHand();
+      })
+      .fail(function () { newWorld(0); });
+  }
+
+  function newWorld(tileCount) {
+    return post(API.world, {
+      tile_count:    tileCount,
+      character_ids: state.party.map(function (c) { return c.id; }),
+    }).done(function (world) {
+      localStorage.setItem('m20_world_id', world.id);
+      setWorld(world);
+      if (!world.tiles.length) drawTileHand();
+    });
+  }
+
+  function setWorld(world) {
+    state.world = world;
+    renderFogMap();
+  }
+
   // ── Map — fog grid ────────────────────────────────────────────────────
   function renderFogMap() {
     // 5×5 grid with fog for unplaced slots
-    const total = 25;
+    const world = state.world;
+    const tiles = world ? world.tiles : [];
+    const total = world ? world.size : 25;
+    const here  = {};
+    $.each(world ? world.positions : {}, function (_, tileID) { here[tileID] = true; });
     let html = '';
     for (let i = 0; i < total; i++) {
-      if (i < state.placedTiles.length) {
-        const tile = state.placedTiles[i];
+      if (i < tiles.length) {
+        const tile = tiles[i];
         const icon = TILE_ICONS[tile.type.name] || '❓';
-        const allCleared = tile.buildings && tile.buildings.length > 0 &&
-          tile.buildings.every(function (b, idx) {
-            return state.clearedBuildings.has(tile.id + '::' + idx);
-          });
-        const isExit = i === state.exitTileIdx;
+        const allCleared = tile.building_count > 0 && tile.cleared === tile.building_count;
         html += '<div class="map-tile danger-' + tile.type.danger +
                 (allCleared ? ' explored' : '') +
-                (isExit     ? ' exit-tile-placed' : '') +
+                (tile.exit  ? ' exit-tile-placed' : '') +
                 '" data-tile-id="' + tile.id + '">' +
-                '<span class="tile-icon">' + (isExit ? '🚪' : icon) + '</span>' +
-                '<span class="tile-name">' + tile.id.slice(-4) + '</span>' +
+                '<span class="tile-icon">' + (tile.exit ? '🚪' : icon) + '</span>' +
+                '<span class="tile-name">' + (here[tile.id] ? '🧍 ' : '') + tile.id.slice(-4) + '</span>' +
                 '<span class="tile-danger">' + '☠'.repeat(tile.type.danger) + '</span>' +
                 '</div>';
       } else {
@@ -614,72 +732,36 @@
 
     $('#map-grid').off('click', '.map-tile:not(.fog)').on('click', '.map-tile:not(.fog)', function () {
       const tileID = $(this).data('tile-id');
-      const tile   = state.placedTiles.find(function (t) { return t.id === tileID; });
+      const tile   = tiles.find(function (t) { return t.id === tileID; });
       if (tile) showTilePanel(tile);
     });
   }
 
   // ── Tile Draw Mechanic ────────────────────────────────────────────────
+  // The server deals the hand (including the exit tile, once it's due) and
+  // keeps it until a tile is placed, so redrawing can't fish for better tiles.
   function drawTileHand() {
-    if (state.placedTiles.length >= 25) {
+    if (!state.world) return;
+    if (state.world.fog === 0) {
       log('The map is full. Nowhere left to go.', 'warning');
       return;
     }
 
     $('#draw-tile-btn').prop('disabled', true).text('Drawing…');
-    state.tileHand = [];
-
-    // Draw 2 tiles in parallel
-    const p1 = get(API.tile);
-    const p2 = get(API.tile);
-
-    $.when(p1, p2).done(function (r1, r2) {
-      const t1 = r1[0];
-      const t2 = r2[0];
-
-      // After 5 tiles placed: 25% chance one tile is the exit tile
-      const maybeExit = state.placedTiles.length >= 5 && state.exitTileIdx === -1 && Math.random() < 0.25;
-      if (maybeExit) {
-        state.tileHand = [t1, makeExitTile()];
-      } else {
-        state.tileHand = [t1, t2];
-      }
-
+    post(API.world + '/' + state.world.id + '/draw', {}).done(function (world) {
+      setWorld(world);
       renderTileHand();
     }).always(function () {
       $('#draw-tile-btn').prop('disabled', false).text('Draw Tiles');
     });
   }
 
-  function makeExitTile() {
-    // Build a fake tile object representing the exit door with the Windego Den
-    return {
-      id:       'exit-' + Date.now().toString(36),
-      isExit:   true,
-      type: {
-        name:        'Exit Door',
-        description: "There's a door here. Something very big is standing in front of it.",
-        danger:       5,
-      },
-      buildings: [{
-        building: { name: 'Exit Door', description: 'The way out. Heavily guarded.' },
-        monster_group: {
-          name:        'Windego Den',
-          description: 'Bones. So many bones. Something vast and wrong unfolds itself from the darkness.',
-          difficulty:   5,
-          monsters:     [{ name: 'Windego', hp: 30, attack: 8, defense: 17, xp_reward: 500, description: 'It was once human. That was a long time ago.' }],
-        },
-        cleared: false,
-      }],
-    };
-  }
-
   function renderTileHand() {
     let html = '';
-    $.each(state.tileHand, function (idx, tile) {
-      const icon     = tile.isExit ? '🚪' : (TILE_ICONS[tile.type.name] || '❓');
-      const bldgCount = tile.buildings ? tile.buildings.length : 0;
-      const isExit   = tile.isExit;
+    $.each(state.world.hand, function (idx, tile) {
+      const icon      = tile.exit ? '🚪' : (TILE_ICONS[tile.type.name] || '❓');
+      const bldgCount = tile.building_count;
+      const isExit    = tile.exit;
 
       html += '<div class="tile-hand-card' + (isExit ? ' exit-tile' : '') + '" data-hand-idx="' + idx + '">' +
               (isExit ? '<div class="exit-badge">🚪 EXIT TILE</div>' : '') +
@@ -697,56 +779,106 @@
     $('#tile-hand').removeClass('hidden');
 
     $('#tile-hand-cards').off('click', '.tile-hand-card').on('click', '.tile-hand-card', function () {
-      const idx = parseInt($(this).data('hand-idx'), 10);
-      placeTile(state.tileHand[idx]);
+      placeTile(parseInt($(this).data('hand-idx'), 10));
     });
   }
 
-  function placeTile(tile) {
-    // Place the tile on the map
-    const mapIdx = state.placedTiles.length;
-    if (tile.isExit) {
-      state.exitTileIdx = mapIdx;
-    }
-    state.placedTiles.push(tile);
-
-    $('#tile-hand').addClass('hidden');
-    state.tileHand = [];
+  function placeTile(choice) {
+    post(API.world + '/' + state.world.id + '/place', { choice: choice }).done(function (world) {
+      const tile = world.tiles[world.tiles.length - 1];
+      $('#tile-hand').addClass('hidden');
+      setWorld(world);
+      hideTilePanel();
 
-    log('Placed: ' + tile.type.name + (tile.isExit ? ' 🚪 EXIT TILE' : '') + ' (position ' + (mapIdx + 1) + '/25)', 'success');
-    renderFogMap();
-    hideTilePanel();
+      log('Placed: ' + tile.type.name + (tile.exit ? ' 🚪 EXIT TILE' : '') + ' (position ' + world.tiles.length + '/' + world.size + ')', 'success');
 
-    // Auto-draw 2 more if map isn't full
-    if (state.placedTiles.length < 25) {
-      setTimeout(drawTileHand, 500);
-    }
+      // Auto-draw 2 more if map isn't full
+      if (world.fog > 0) {
+        setTimeout(drawTileHand, 500);
+      }
+    });
   }
 
   function generateClassicMap() {
     hideTilePanel();
     hideBuildingPanel();
-    post(API.land, { tileCount: 9 }).done(function (data) {
-      state.placedTiles = data.tiles || [];
-      state.exitTileIdx = -1;
-      state.clearedBuildings.clear();
-      renderFogMap();
+    $('#tile-hand').addClass('hidden');
+    newWorld(9).done(function () {
       log('Classic 9-tile map generated.', 'warning');
     });
   }
 
   // ── Tile Panel — building list for selected tile ───────────────────────
+  // Opening a tile walks the standing party there along the cheapest path;
+  // the server only reveals a tile's buildings to characters who have entered
+  // it, and may stop the party early with a travel ambush. Anything farther
+  // than next door is driven if someone has a working, fuelled vehicle that
+  // fits everyone.
   function showTilePanel(tile) {
-    state.currentTile = tile;
+    const walkers = state.party.filter(function (c) { return c.hp > 0; });
+    if (!walkers.length) { showError('Everyone is down. Heal up before moving.'); return; }
+    if (state.fight) { showError('Finish the fight first.'); return; }
     hideBuildingPanel();
 
-    $('#tile-panel-name').text(tile.type.name + (tile.isExit ? ' 🚪' : ''));
+    const body = { tile_id: tile.id };
+    if (state.partyID) body.party_id = state.partyID;
+    else body.character_ids = walkers.map(function (c) { return c.id; });
+    const here = state.world.tiles.find(function (t) { return t.id === walkers[0].location; });
+    const ride = partyVehicle(walkers);
+    if (ride && here && Math.abs(here.x - tile.x) + Math.abs(here.y - tile.y) > 1) {
+      body.driver_id  = ride.driver.id;
+      body.vehicle_id = ride.vehicle.id;
+    }
+
+    post(API.world + '/' + state.world.id + '/move', body).done(function (data) {
+      const move = data.move;
+      walkers.forEach(function (c) { c.location = move.to; });
+      if (move.vehicle && ride.vehicle.fuel_needed) ride.vehicle.fuel--;
+      setWorld(data.world);
+      if (move.path.length > 1) {
+        log((move.vehicle ? '🚗 ' : '🥾 ') + move.path.join(' → ') + ' (' + move.cost + '/' + move.budget + ' MP' +
+            (move.vehicle ? ', ' + move.vehicle : '') + ')');
+      }
+      renderTilePanel(data.world.tiles.find(function (t) { return t.id === move.to; }));
+      if (data.encounter) onAmbush(move, data.encounter);
+    });
+  }
+
+  // partyVehicle finds a vehicle some walker owns that can carry them all now.
+  function partyVehicle(walkers) {
+    for (const c of walkers) {
+      const v = (c.vehicles || []).find(function (v) {
+        return v.condition === 'operational' && (!v.fuel_needed || v.fuel > 0) && v.capacity >= walkers.length;
+      });
+      if (v) return { driver: c, vehicle: v };
+    }
+    return null;
+  }
+
+  // onAmbush drops straight into a travel encounter; it has no building, so
+  // winning clears nothing on the map.
+  function onAmbush(move, fight) {
+    log('⚠ Ambushed on ' + move.to + '! ' + move.ambush.monsters[0].name + ' blocks the way.', 'warning');
+    state.encounter = {
+      tileID:        move.to,
+      buildingIdx:   null,
+      building:      { name: 'The road', description: 'You never made it where you were going.' },
+      monster_group: move.ambush,
+    };
+    showBuildingPanel(state.encounter);
+    $('#fight-section').hide();
+    applyFight(fight, fight.log);
+  }
+
+  function renderTilePanel(tile) {
+    state.currentTile = tile;
+
+    $('#tile-panel-name').text(tile.type.name + (tile.exit ? ' 🚪' : ''));
     $('#tile-panel-desc').text(tile.type.description || '');
 
     let html = '';
-    $.each(tile.buildings, function (idx, bi) {
-      const key     = tile.id + '::' + idx;
-      const cleared = state.clearedBuildings.has(key);
+    $.each(tile.buildings || [], function (idx, bi) {
+      const cleared = bi.cleared;
       const diff    = bi.monster_group.difficulty || 1;
       html += '<div class="building-row' + (cleared ? ' cleared' : '') + '">' +
               '<div class="building-row-info">' +
@@ -756,18 +888,35 @@
               '</div>' +
               (cleared
                 ? '<span class="cleared-badge">CLEARED</span>'
-                : '<button class="btn-enter btn-sm" data-tile-id="' + tile.id + '" data-idx="' + idx + '">Enter →</button>'
+                : '<button class="btn-enter btn-sm" data-idx="' + idx + '">Enter →</button>'
               ) +
               '</div>';
     });
+    if ((tile.type.encounters || []).includes('vehicle')) {
+      html += tile.vehicle_searched
+        ? '<p class="sub-panel-desc">Already picked over for vehicles.</p>'
+        : '<button id="vehicle-search-btn" class="btn-sm">🔍 Search for vehicles</button>';
+    }
     $('#building-list').html(html);
     $('#tile-panel').removeClass('hidden');
 
     $('#building-list').off('click', '.btn-enter').on('click', '.btn-enter', function () {
-      const idx    = parseInt($(this).data('idx'), 10);
-      const tileID = $(this).data('tile-id');
-      const t      = state.placedTiles.find(function (t) { return t.id === tileID; });
-      if (t) enterBuilding(t, idx);
+      enterBuilding(tile, parseInt($(this).data('idx'), 10));
+    });
+    $('#vehicle-search-btn').off('click').on('click', searchForVehicle);
+  }
+
+  // searchForVehicle spends the tile's one vehicle search on the active
+  // character's scouting; a find goes into their garage.
+  function searchForVehicle() {
+    const char = activeChar();
+    if (!char) return;
+    post(API.world + '/' + state.world.id + '/search', { character_id: char.id }).done(function (data) {
+      const roll = data.search.roll;
+      log('🔍 Scouting ' + roll.total + ' vs ' + roll.target + ': ' + data.search.text, data.vehicle ? 'success' : '');
+      syncCharacter(data.character);
+      setWorld(data.world);
+      renderTilePanel(data.world.tiles.find(function (t) { return t.id === state.currentTile.id; }));
     });
   }
 
@@ -787,6 +936,8 @@
     post(API.buildingEnter, {
       building:        bi.building.name,
       character_class: charClass,
+      character_id:    char ? char.id : '',
+      stream:          canStream(),
     }).done(function (data) {
       state.encounter = {
         tileID:          tile.id,
@@ -797,6 +948,11 @@
         leader_dialogue: data.leader_dialogue,
       };
       showBuildingPanel(state.encounter);
+      if (data.narration_stream) {
+        streamNarration(data.narration_stream, '#bldg-flavor', function (text) {
+          if (state.encounter) state.encounter.flavor_text = text;
+        });
+      }
     });
   }
 
@@ -811,8 +967,9 @@
     $('#bldg-leader-dialogue').text(leader_dialogue ? '"' + leader_dialogue + '"' : '');
 
     let html = '';
-    $.each(monster_group.monsters, function (_, m) {
-      html += '<div class="monster-card" data-name="' + m.name + '">' +
+    $.each(monster_group.monsters, function (i, m) {
+      // Server encounters number monsters m0, m1, … in listing order.
+      html += '<div class="monster-card" data-monster-id="m' + i + '">' +
               '<div class="monster-card-info">' +
                 '<span class="monster-name">' + m.name + '</span>' +
                 '<span class="monster-desc">' + (m.description || '') + '</span>' +
@@ -833,7 +990,7 @@
       '<button id="fight-btn" class="btn-primary fight-btn">⚔ Fight! (' + monster_group.monsters.length + ' enemies)</button>'
     );
     $('#fight-btn').off('click').on('click', function () {
-      startCombat(monster_group.monsters);
+      startCombat();
     });
 
     $('#building-panel').removeClass('hidden');
@@ -844,498 +1001,311 @@
     $('#building-panel').addClass('hidden');
     $('#initiative-tracker').addClass('hidden');
     hideCombatSection();
-    state.encounter     = null;
-    state.combatQueue   = [];
-    state.activeMonster = null;
-    state.firstStrike   = false;
+    state.encounter = null;
+    state.fight     = null;
   }
 
-  // ── Combat + Initiative ────────────────────────────────────────────────
-  function startCombat(monsters) {
-    state.combatQueue = monsters.map(function (m) {
-      return Object.assign({}, m, { currentHP: m.hp });
-    });
-
-    // Roll initiative for all party members + all monsters
-    state.initiativeOrder = rollInitiative(state.party, state.combatQueue);
-    state.initiativeTurn  = 0;
+  // ── Combat + Initiative (server-authoritative — /api/encounter) ─────────
+  function startCombat() {
+    const fighters = state.party.filter(function (c) { return c.hp > 0; });
+    if (!fighters.length) { showError('Everyone is down. Heal up before fighting.'); return; }
 
-    state.firstStrike = false; // used only for Gunslinger
     $('#fight-section').hide();
-    renderInitiativeTracker();
-    processNextTurn();
+    const enc = state.encounter;
+    const body = { site: { world_id: state.world.id, tile_id: enc.tileID, building: enc.buildingIdx } };
+    if (state.partyID) body.party_id = state.partyID;
+    else body.character_ids = fighters.map(function (c) { return c.id; });
+    post(API.fight, body).done(function (fight) {
+      log('⚡ Initiative rolled! Order: ' + fight.order.map(function (cb) {
+        return cb.name + '(' + cb.initiative + ')';
+      }).join(', '), 'warning');
+      applyFight(fight, fight.log);
+    }).fail(function () {
+      $('#fight-section').show();
+    });
   }
 
-  function rollInitiative(party, monsters) {
-    const order = [];
+  // applyFight renders the server's encounter after it changes. The server
+  // has already saved party HP and XP; we only mirror it locally.
+  function applyFight(fight, events) {
+    state.fight = fight;
+    logFightEvents(fight, events || []);
 
-    // Party members: D20 + scouting; Gunslinger gets +10 (always first)
-    party.forEach(function (char, idx) {
-      if (char.hp <= 0) return; // dead party members skip
-      const d20  = Math.floor(Math.random() * 20) + 1;
-      const stat = char.stats ? char.stats.scouting : 0;
-      const bonus = char.class === 'Gunslinger' ? 10 : 0;
-      const roll  = d20 + stat + bonus;
-      order.push({ type: 'character', name: char.name, idx: idx, initiative: roll });
+    fight.order.forEach(function (cb) {
+      if (cb.kind !== 'character') return;
+      const char = state.party.find(function (c) { return c.id === cb.character_id; });
+      if (char) { char.hp = cb.hp; char.statuses = cb.statuses || []; }
     });
+    renderStats();
+    renderPartyBar();
 
-    // Monsters: D20 + attack bonus
-    monsters.forEach(function (m, idx) {
-      const d20 = Math.floor(Math.random() * 20) + 1;
-      const roll = d20 + m.attack;
-      order.push({ type: 'monster', name: m.name, idx: idx, initiative: roll });
-    });
+    if (fight.status !== 'active') {
+      finishFight(fight);
+      return;
+    }
 
-    // Sort descending (ties: characters win)
-    order.sort(function (a, b) {
-      if (b.initiative !== a.initiative) return b.initiative - a.initiative;
-      return a.type === 'character' ? -1 : 1;
-    });
+    // It's a character's turn — highlight and wait for Roll Attack button
+    const cur = fight.order[fight.turn];
+    const idx = state.party.findIndex(function (c) { return c.id === cur.character_id; });
+    if (idx !== -1) {
+      state.activePartyIdx = idx;
+      state.character      = state.party[idx];
+    }
+    renderPartyBar();
+    renderStats();
+    renderInitiativeTracker();
+    showCombatPanel(fight.order.find(function (cb) { return cb.kind === 'monster' && cb.hp > 0; }));
+    log('→ ' + cur.name + '\'s turn (initiative ' + cur.initiative + ').', 'warning');
+  }
 
-    log('⚡ Initiative rolled! Order: ' + order.map(function (e) {
-      return e.name + '(' + e.initiative + ')';
-    }).join(', '), 'warning');
+  function logFightEvents(fight, events) {
+    const byID = {};
+    fight.order.forEach(function (cb) { byID[cb.id] = cb; });
+
+    events.forEach(function (ev) {
+      const actor  = byID[ev.actor]  || {};
+      const target = byID[ev.target] || {};
+      if (ev.roll) {
+        log('[' + actor.name + ' vs ' + target.name + '] d20:' + ev.roll.roll +
+            ' +stat:' + ev.roll.stat_value + ' +bonus:' + ev.roll.bonus +
+            ' = ' + ev.roll.total + ' → ' + ev.roll.outcome.toUpperCase(), 'combat');
+      }
+      let type = 'combat';
+      switch (ev.type) {
+        case 'attack':         type = ev.hit ? 'success' : 'combat'; break;
+        case 'monster_attack': type = ev.hit ? 'combat' : 'success'; break;
+        case 'riddle':         type = ev.hit ? 'success' : 'combat'; break;
+        case 'defeated':
+        case 'heal':
+        case 'item':
+        case 'reward':
+        case 'victory':        type = 'success'; break;
+        case 'forfeit':        type = 'warning'; break;
+        case 'status':         type = ev.damage ? 'combat' : 'warning'; break;
+        case 'flee':           type = 'warning'; break;
+      }
+      log((ev.type === 'monster_attack' ? '💀 ' : '  → ') + ev.text, type);
 
-    return order;
+      if (ev.type === 'defeated') {
+        $('#monster-group-list .monster-card[data-monster-id="' + ev.target + '"]').addClass('defeated');
+      }
+    });
   }
 
   function renderInitiativeTracker() {
-    const order = state.initiativeOrder;
-    if (!order.length) {
+    const fight = state.fight;
+    if (!fight || !fight.order.length) {
       $('#initiative-tracker').addClass('hidden');
       return;
     }
     let html = '';
-    order.forEach(function (entry, i) {
-      const isCurrent = i === state.initiativeTurn;
-      const isMon     = entry.type === 'monster';
-      // Find HP for monsters
-      let hpText = '';
-      if (isMon) {
-        const monster = state.combatQueue.find(function (m) { return m.name === entry.name; });
-        if (monster) hpText = 'HP ' + monster.currentHP + '/' + monster.hp;
-      } else {
-        const char = state.party[entry.idx];
-        if (char) hpText = 'HP ' + char.hp + '/' + char.max_hp;
-      }
+    fight.order.forEach(function (cb, i) {
+      const isCurrent = i === fight.turn;
+      const isMon     = cb.kind === 'monster';
       html += '<div class="initiative-entry ' + (isMon ? 'monster' : 'character') + (isCurrent ? ' current-turn' : '') + '">' +
-              '<span class="initiative-roll">' + entry.initiative + '</span>' +
-              '<span class="initiative-name">' + (isCurrent ? '▶ ' : '') + entry.name + '</span>' +
-              '<span class="initiative-hp">' + hpText + '</span>' +
+              '<span class="initiative-roll">' + cb.initiative + '</span>' +
+              '<span class="initiative-name">' + (isCurrent ? '▶ ' : '') + cb.name +
+                (cb.statuses || []).map(function (st) { return ' <span class="status-badge">' + st.name + ' ' + st.rounds + '</span>'; }).join('') +
+              '</span>' +
+              '<span class="initiative-hp">HP ' + cb.hp + '/' + cb.max_hp + '</span>' +
               '</div>';
     });
     $('#initiative-list').html(html);
     $('#initiative-tracker').removeClass('hidden');
   }
 
-  function processNextTurn() {
-    // Skip dead monsters (already removed from combatQueue) and dead party members
-    while (state.initiativeTurn < state.initiativeOrder.length) {
-      const entry = state.initiativeOrder[state.initiativeTurn];
-      if (entry.type === 'character') {
-        const char = state.party[entry.idx];
-        if (!char || char.hp <= 0) {
-          state.initiativeTurn++;
-          continue;
-        }
-        // It's a character's turn — highlight and wait for Roll Attack button
-        state.activePartyIdx = entry.idx;
-        state.character = char;
-        renderPartyBar();
-        renderStats();
-        renderInitiativeTracker();
-        log('→ ' + char.name + '\'s turn (initiative ' + entry.initiative + ').', 'warning');
-        return;
-      } else {
-        // Monster turn
-        const monster = state.combatQueue.find(function (m) { return m.name === entry.name && m.currentHP > 0; });
-        if (!monster) {
-          // Monster already defeated — skip turn
-          state.initiativeTurn++;
-          continue;
-        }
-        state.activeMonster = monster;
-        renderInitiativeTracker();
-        doMonsterAttack(monster);
-        return;
-      }
-    }
-
-    // End of round — start next round if combat still ongoing
-    if (state.combatQueue.length > 0) {
-      const aliveParty = state.party.filter(function (c) { return c.hp > 0; });
-      if (aliveParty.length === 0) {
-        onAllPlayersDefeated();
-        return;
-      }
-      log('--- New Round ---', 'warning');
-      state.initiativeTurn = 0;
-      renderInitiativeTracker();
-      processNextTurn();
-    } else {
-      onGroupDefeated();
-    }
-  }
-
-  function advanceTurn() {
-    state.initiativeTurn++;
-    // Wrap if past end
-    if (state.initiativeTurn >= state.initiativeOrder.length) {
-      const aliveMonsters = state.combatQueue.filter(function (m) { return m.currentHP > 0; });
-      if (aliveMonsters.length === 0) {
-        onGroupDefeated();
-        return;
-      }
-      const aliveParty = state.party.filter(function (c) { return c.hp > 0; });
-      if (aliveParty.length === 0) {
-        onAllPlayersDefeated();
-        return;
-      }
-      log('--- New Round ---', 'warning');
-      state.initiativeTurn = 0;
-    }
-    setTimeout(processNextTurn, 600);
-  }
-
-  function doMonsterAttack(monster) {
-    // Monster attacks the party member with lowest HP
-    const targets = state.party.filter(function (c) { return c.hp > 0; });
-    if (!targets.length) { onAllPlayersDefeated(); return; }
-
-    targets.sort(function (a, b) { return a.hp - b.hp; });
-    const target = targets[0];
-
-    const d20   = Math.floor(Math.random() * 20) + 1;
-    const total = d20 + monster.attack;
-    const hit   = total >= 10;
-    const isCrit = d20 === 20;
-
-    if (hit) {
-      const dmg = isCrit ? Math.max(2, monster.attack) : 1 + Math.floor(monster.attack / 3);
-      takeDamage(target, dmg);
-      log('💀 ' + monster.name + ' attacks ' + target.name + '! ' +
-          'd20:' + d20 + '+atk:' + monster.attack + '=' + total + ' → ' +
-          (isCrit ? '💥 CRIT!' : 'HIT') + ' ' + dmg + ' damage. ' +
-          target.name + ' has ' + target.hp + '/' + target.max_hp + ' HP.', 'combat');
-
-      if (target.hp <= 0) {
-        log('💀 ' + target.name + ' is down!', 'combat');
-        renderPartyBar();
-        // Check if all party dead
-        if (state.party.every(function (c) { return c.hp <= 0; })) {
-          onAllPlayersDefeated();
-          return;
-        }
-      }
-    } else {
-      log('✨ ' + monster.name + ' misses ' + target.name + '! (' + d20 + '+' + monster.attack + '=' + total + ' vs 10)', 'success');
-    }
-
-    renderStats();
-    renderPartyBar();
-    // Show combat panel for the monster we're currently facing
-    showCombatPanel(state.combatQueue.find(function (m) { return m.currentHP > 0; }) || state.combatQueue[0]);
-    advanceTurn();
-  }
-
-  // ── Player attack ─────────────────────────────────────────────────────
+  // ── Player actions ────────────────────────────────────────────────────
   function showCombatPanel(monster) {
     if (!monster) return;
     $('#combat-monster-name').text(monster.name);
-    $('#combat-monster-desc').text(monster.description || '');
+    const def = state.encounter && state.encounter.monster_group.monsters[parseInt(monster.id.slice(1), 10)];
+    $('#combat-monster-desc').text(def ? def.description || '' : '');
     updateMonsterHP(monster);
-    $('#combat-narration').text('');
     $('#combat-roll-btn').prop('disabled', false).text('Roll Attack');
     $('#combat-flee-btn').prop('disabled', false);
     $('#combat-section').removeClass('hidden');
 
     // Highlight active monster card
     $('#monster-group-list .monster-card').removeClass('active');
-    $('#monster-group-list .monster-card[data-name="' + monster.name + '"]').addClass('active');
+    $('#monster-group-list .monster-card[data-monster-id="' + monster.id + '"]').addClass('active');
     $('#combat-section')[0].scrollIntoView({ behavior: 'smooth', block: 'nearest' });
   }
 
   function hideCombatSection() {
+    stopNarration();
     $('#combat-section').addClass('hidden');
-    state.activeMonster = null;
+    $('#combat-narration').text('');
   }
 
   function updateMonsterHP(monster) {
-    const pct = Math.max(0, Math.round((monster.currentHP / monster.hp) * 100));
+    const pct = Math.max(0, Math.round((monster.hp / monster.max_hp) * 100));
     const cls = pct > 60 ? 'hp-high' : pct > 25 ? 'hp-mid' : 'hp-low';
     $('#combat-hp-bar').css('width', pct + '%').attr('class', 'hp-fill ' + cls);
-    $('#combat-hp-text').text(monster.currentHP + ' / ' + monster.hp);
-  }
-
-  function getCombatStat(char) {
-    if (!char) return { stat: 3, bonus: 0, critThreshold: 20 };
-    const stats = char.stats;
-    const cls   = char.class;
-    let stat = stats.strength, bonus = 0, critThreshold = 20;
-
-    switch (cls) {
-      case 'Gunslinger':
-        stat = stats.marksmanship;
-        if (state.firstStrike) { bonus = 2; state.firstStrike = false; }
-        break;
-      case 'Brawler':
-        stat = stats.strength;
-        critThreshold = 18;
-        break;
-      case 'Medic':
-        stat = stats.stamina;
-        break;
-      case 'Scavenger':
-      case 'Conspiracy Theorist':
-        stat = stats.scouting;
-        break;
-      case 'Wrench Witch':
-      case 'Street Pharmacist':
-        stat = stats.crafting;
-        break;
-      case 'Hoarder':
-        stat = stats.salvaging;
-        break;
-    }
-    return { stat: stat, bonus: bonus, critThreshold: critThreshold };
+    $('#combat-hp-text').text(monster.hp + ' / ' + monster.max_hp);
   }
 
   function doEncounterRoll() {
-    // The current initiative entry tells us which character acts
-    const entry = state.initiativeOrder[state.initiativeTurn];
-    const char  = entry && entry.type === 'character' ? state.party[entry.idx] : activeChar();
-    if (!char) return;
+    doFightAction('attack');
+  }
 
-    // Pick the first living monster to fight
-    const monster = state.combatQueue.find(function (m) { return m.currentHP > 0; });
-    if (!monster) { onGroupDefeated(); return; }
-    state.activeMonster = monster;
-    showCombatPanel(monster);
+  function fleeCombat() {
+    doFightAction('flee');
+  }
 
-    const { stat, bonus, critThreshold } = getCombatStat(char);
-    const charClass = char.class;
+  function doFightAction(action) {
+    const fight = state.fight;
+    if (!fight || fight.status !== 'active') return;
+    const cur = fight.order[fight.turn];
 
-    $('#combat-roll-btn').prop('disabled', true).text('Rolling…');
+    $('#combat-roll-btn').prop('disabled', true).text(action === 'attack' ? 'Rolling…' : 'Next…');
+    $('#combat-flee-btn').prop('disabled', true);
 
-    post(API.encounter, {
-      monster:         monster.name,
-      stat:            stat,
-      bonus:           bonus,
-      character_class: charClass,
-      crit_threshold:  critThreshold,
+    post(API.fight + '/' + fight.id + '/action', {
+      character_id: cur.character_id,
+      action:       action,
+      stream:       canStream(),
     }).done(function (data) {
-      const roll    = data.roll;
-      const hit     = data.hit;
-      const outcome = roll.outcome;
-
-      $('#combat-narration').text(data.narration);
-
-      log('[' + char.name + ' vs ' + monster.name + '] d20:' + roll.roll +
-          ' +stat:' + roll.stat_value + ' +bonus:' + roll.bonus +
-          ' = ' + roll.total + ' → ' + outcome.toUpperCase(), 'combat');
-      log(data.narration, hit ? 'success' : 'combat');
-
-      if (hit) {
-        const isCrit = outcome === 'crit_success';
-        const dmg    = isCrit ? rollPlayerDamage(true) : rollPlayerDamage(false);
-        monster.currentHP = Math.max(0, monster.currentHP - dmg);
-        updateMonsterHP(monster);
-        log('  → ' + char.name + ' deals ' + dmg + ' damage! ' + monster.name + ' has ' + monster.currentHP + ' HP left.', 'success');
-
-        if (monster.currentHP <= 0) {
-          log('  → ' + monster.name + ' is down! 💥', 'success');
-          $('#monster-group-list .monster-card[data-name="' + monster.name + '"]').addClass('defeated');
-          onMonsterDefeated(char, monster);
-          return;
-        }
-      } else {
-        // Miss = monster counters this turn (minor counter-attack)
-        const counterDmg = rollMonsterDamage(monster, outcome === 'crit_failure');
-        takeDamage(char, counterDmg);
-        log('  → Counter! ' + monster.name + ' hits ' + char.name + ' for ' + counterDmg + '. HP: ' + char.hp + '/' + char.max_hp, 'combat');
-        renderPartyBar();
-
-        if (char.hp <= 0) {
-          log('💀 ' + char.name + ' is down!', 'combat');
-          saveCharacter(char);
-          if (state.party.every(function (c) { return c.hp <= 0; })) {
-            onAllPlayersDefeated();
-            return;
-          }
-        }
+      const logType = data.events[0] && data.events[0].hit ? 'success' : 'combat';
+      if (data.narration) {
+        $('#combat-narration').text(data.narration);
+        log(data.narration, logType);
+      } else if (data.narration_stream) {
+        streamNarration(data.narration_stream, '#combat-narration', function (text) {
+          log(text, logType);
+        });
       }
-
-      renderStats();
+      applyFight(data.encounter, data.events);
+    }).fail(function () {
       $('#combat-roll-btn').prop('disabled', false).text('Roll Attack');
-      // Character has taken their turn — advance initiative
-      advanceTurn();
+      $('#combat-flee-btn').prop('disabled', false);
     });
   }
 
-  function rollPlayerDamage(isCrit) {
-    const d6 = function () { return Math.floor(Math.random() * 6) + 1; };
-    return isCrit ? d6() + d6() : d6();
-  }
-
-  function rollMonsterDamage(monster, isCritFail) {
-    return isCritFail ? Math.max(2, monster.attack) : 1 + Math.floor(monster.attack / 3);
-  }
-
-  function takeDamage(char, amount) {
-    if (!char) return;
-    char.hp = Math.max(0, char.hp - amount);
-    renderStats();
-    $('#hp-display').addClass('hp-damaged');
-    setTimeout(function () { $('#hp-display').removeClass('hp-damaged'); }, 600);
-  }
-
-  function awardXP(char, amount) {
-    if (!char) return;
-    char.xp = (char.xp || 0) + amount;
-    const xpNeeded = char.level * 100;
-    if (char.xp >= xpNeeded) {
-      char.xp    -= xpNeeded;
-      char.level++;
-      char.max_hp += 4;
-      char.hp      = Math.min(char.hp + 4, char.max_hp);
-      log('✨ ' + char.name + ' LEVEL UP! Now level ' + char.level + '. Max HP +4!', 'success');
-    }
-    saveCharacter(char);
-    renderStats();
-    renderPartyBar();
-  }
-
-  function onMonsterDefeated(char, monster) {
-    awardXP(char, monster.xp_reward);
-    log('  → +' + monster.xp_reward + ' XP to ' + char.name, 'success');
-
-    // Remove from combat queue
-    const idx = state.combatQueue.findIndex(function (m) { return m === monster; });
-    if (idx !== -1) state.combatQueue.splice(idx, 1);
-
-    // Also remove from initiative order
-    state.initiativeOrder = state.initiativeOrder.filter(function (e) {
-      return !(e.type === 'monster' && e.name === monster.name);
-    });
-    // Adjust turn index after removal
-    if (state.initiativeTurn >= state.initiativeOrder.length) {
-      state.initiativeTurn = 0;
-    }
+  function finishFight(fight) {
+    state.fight = null;
+    hideCombatSection();
+    $('#initiative-tracker').addClass('hidden');
+    refreshParty();
 
-    if (state.combatQueue.length === 0) {
-      onGroupDefeated();
-      return;
+    switch (fight.status) {
+      case 'victory': onGroupDefeated(fight); break;
+      case 'defeat':  onAllPlayersDefeated(); break;
+      case 'fled':
+        $('#fight-section').show().html('<div style="color:var(--accent2);font-size:12px">You fled. The building is still theirs.</div>');
+        break;
     }
-
-    $('#combat-roll-btn').prop('disabled', true).text('Next…');
-    setTimeout(function () { processNextTurn(); }, 1200);
   }
 
-  function onGroupDefeated() {
-    hideCombatSection();
-    $('#initiative-tracker').addClass('hidden');
-
+  // The server has already split the XP and packed the loot; this only
+  // reports it.
+  function onGroupDefeated(fight) {
     const enc = state.encounter;
     if (!enc) return;
 
-    const key = enc.tileID + '::' + enc.buildingIdx;
-    state.clearedBuildings.add(key);
-
     log('🏆 ' + enc.monster_group.name + ' defeated! ' + enc.building.name + ' is clear.', 'success');
-    $('#fight-section').show().html('<div class="cleared-victory">🏆 CLEARED — searching for supplies…</div>');
-
-    // Save all living party members
-    state.party.forEach(function (c) { if (c.hp > 0) saveCharacter(c); });
-
-    // Loot drop
-    const char  = activeChar();
-    const level = char ? char.stats.scouting : 3;
-    get(API.scavenge + '?level=' + level).done(function (data) {
-      const activeC = activeChar();
-      const max     = activeC ? activeC.class === 'Hoarder' ? 25 : 20 : 20;
-      $.each(data.found, function (_, item) {
-        if (activeC && activeC.inventory.length < max) {
-          activeC.inventory.push(item.name);
-          log('  → Looted: ' + item.name, 'success');
-        } else {
-          log('  → Inventory full. Left behind: ' + item.name, 'warning');
-        }
+    $('#fight-section').show().html('<div class="cleared-victory">🏆 CLEARED</div>');
+
+    const rewards = fight.rewards;
+    if (rewards && !rewards.forfeited) {
+      $.each(rewards.drops, function (_, drop) {
+        log('  → ' + drop.name + ' looted: ' + drop.item, 'success');
       });
-      if (activeC) {
-        saveCharacter(activeC);
-        renderInventoryGrid(activeC);
-      }
-    });
+      $.each(rewards.left_behind, function (_, item) {
+        log('  → No room in anyone\'s pack. Left behind: ' + item, 'warning');
+      });
+    }
 
-    renderFogMap();
+    // The server marked the building cleared; refresh the map.
+    get(API.world + '/' + state.world.id).done(setWorld);
   }
 
   function onAllPlayersDefeated() {
-    hideCombatSection();
-    $('#initiative-tracker').addClass('hidden');
-    log('💀 The whole party is down. You crawl away, barely alive.', 'combat');
-    // Set all downed characters to 1 HP
-    state.party.forEach(function (c) {
-      if (c.hp <= 0) {
-        c.hp = 1;
-        saveCharacter(c);
-      }
-    });
-    state.combatQueue   = [];
-    state.activeMonster = null;
-    renderStats();
-    renderPartyBar();
     $('#fight-section').show().html('<div style="color:var(--accent);font-size:12px">The party escaped — barely.</div>');
   }
 
-  function fleeCombat() {
-    const mName = state.activeMonster ? state.activeMonster.name : 'the enemy';
-    log('You flee from ' + mName + '. Not every fight is yours to win.', 'warning');
-    hideCombatSection();
-    $('#initiative-tracker').addClass('hidden');
-    state.combatQueue      = [];
-    state.activeMonster    = null;
-    state.firstStrike      = false;
-    state.initiativeOrder  = [];
-    state.initiativeTurn   = 0;
-    $('#fight-section').show().html('<div style="color:var(--accent2);font-size:12px">You fled. The building is still theirs.</div>');
-  }
-
   // ── Scavenge ──────────────────────────────────────────────────────────
   function doScavenge() {
-    const char  = activeChar();
-    const level = char ? char.stats.scouting : 3;
-    get(API.scavenge + '?level=' + level).done(function (data) {
-      log('Scavenge (scout ' + level + '): ' + data.description);
-      const max = char && char.class === 'Hoarder' ? 25 : 20;
-      $.each(data.found, function (_, item) {
-        if (char && char.inventory.length < max) {
-          char.inventory.push(item.name);
-          log('  → Found: ' + item.name, 'success');
-        } else {
-          log('  → Inventory full. Left behind: ' + item.name, 'warning');
-        }
+    const char = activeChar();
+    if (!char || !state.world) return;
+    scavengeWith(char, 'Found');
+  }
+
+  // scavengeWith rolls a server-side scavenge of the character's tile; the
+  // server keeps what fits. Each tile can be scavenged once.
+  function scavengeWith(char, verb) {
+    post(API.character + '/' + char.id + '/scavenge', { world_id: state.world.id }).done(function (data) {
+      syncCharacter(data.character);
+      log('Scavenge (scout ' + data.scavenge.scout_level + '): ' + data.scavenge.description);
+      $.each(data.added, function (_, name) {
+        log('  → ' + verb + ': ' + name, 'success');
       });
-      if (char) {
-        saveCharacter(char);
-        renderInventoryGrid(char);
-      }
+      $.each(data.left_behind, function (_, name) {
+        log('  → Inventory full. Left behind: ' + name, 'warning');
+      });
+      renderInventoryGrid(data.character);
+      get(API.world + '/' + state.world.id).done(setWorld);
     });
   }
 
-  // ── Riddle (Ollama AI) ────────────────────────────────────────────────
+  // ── Riddle (Ollama AI, answer checked server-side) ──────────────────
+  // Mid-fight against the Sphinx, the riddle settles the fight: solve it and
+  // the Sphinx falls, fail it and it hits back. Outside a fight it's practice.
   function doRiddle() {
+    const char = activeChar();
+    if (!char) return;
+    if (char.hp <= 0) { showError(char.name + ' is down. Heal before facing the Sphinx.'); return; }
+    const keeper = state.fight && state.fight.order.find(function (cb) { return cb.riddle && cb.hp > 0; });
+
     log('The Sphinx regards you with dramatic, ancient patience…', 'ai');
     $('#riddle-btn').prop('disabled', true).text('Asking the Sphinx…');
-    get(API.riddle)
-      .done(function (data) {
-        log('SPHINX: "' + data.riddle + '"', 'ai');
-        if (data.answer) { log('(Answer: ' + data.answer + ')', 'ai'); }
-        if (data.fallback) { log('(Ollama unavailable — fallback riddle used)', 'warning'); }
-      })
-      .always(function () {
-        $('#riddle-btn').prop('disabled', false).text('Ask the Sphinx 🤖');
-      });
+    post(API.riddle, {
+      character_id: char.id,
+      encounter_id: keeper ? state.fight.id : '',
+    }).done(function (session) {
+      state.riddle = session;
+      log('SPHINX: "' + session.riddle + '"', 'ai');
+      if (session.fallback) { log('(Ollama unavailable — fallback riddle used)', 'warning'); }
+      if (session.hint) { log('🔎 Insight (' + session.insight.total + ' vs ' + session.insight.target + '): ' + session.hint, 'success'); }
+      renderRiddle();
+    }).always(function () {
+      $('#riddle-btn').prop('disabled', false).text('Ask the Sphinx 🤖');
+    });
+  }
+
+  function renderRiddle() {
+    const session = state.riddle;
+    if (!session || session.status !== 'pending') {
+      $('#riddle-panel').addClass('hidden');
+      return;
+    }
+    $('#riddle-text').text('"' + session.riddle + '"');
+    $('#riddle-meta').text(session.attempts_left + ' attempt' + (session.attempts_left !== 1 ? 's' : '') + ' left' +
+                           (session.hint ? ' · ' + session.hint : ''));
+    $('#riddle-guess').val('');
+    $('#riddle-panel').removeClass('hidden');
+    $('#riddle-guess').trigger('focus');
+  }
+
+  function doRiddleGuess() {
+    const session = state.riddle;
+    const guess   = $.trim($('#riddle-guess').val());
+    if (!session || !guess) return;
+
+    $('#riddle-guess-btn').prop('disabled', true);
+    post(API.riddle + '/' + session.id + '/guess', { guess: guess }).done(function (data) {
+      state.riddle = data.riddle;
+      if (data.correct) {
+        log('SPHINX: "…Correct. How irritating."', 'success');
+      } else if (data.riddle.status === 'failed') {
+        log('SPHINX: "Wrong. The answer was ' + data.riddle.answer + '."', 'combat');
+      } else {
+        log('SPHINX: "No." (' + data.riddle.attempts_left + ' left)', 'warning');
+      }
+      if (data.encounter) applyFight(data.encounter, data.events);
+      renderRiddle();
+    }).always(function () {
+      $('#riddle-guess-btn').prop('disabled', false);
+    });
   }
 
   // ── Craft check (global) ──────────────────────────────────────────────
@@ -1380,6 +1350,42 @@
     $('#log-entries').prepend($row);
   }
 
+  // ── Streamed narration (GET /api/narration/stream, server-sent events) ──
+  function canStream() {
+    return typeof window.EventSource === 'function';
+  }
+
+  // Renders narration into $target as it arrives; onDone gets the full line.
+  // Only one stream is open at a time — a new one closes the last.
+  function streamNarration(url, target, onDone) {
+    stopNarration();
+    const $el = $(target).text('');
+    const es  = new EventSource(url);
+    state.narration = es;
+
+    es.addEventListener('chunk', function (ev) {
+      $el.text($el.text() + JSON.parse(ev.data).text);
+    });
+    es.addEventListener('done', function (ev) {
+      const text = JSON.parse(ev.data).text;
+      $el.text(text);
+      stopNarration();
+      if (onDone) onDone(text);
+    });
+    // The server closes the stream after "done"; anything else is an error.
+    // Either way, don't let EventSource reconnect and narrate twice.
+    es.onerror = function () {
+      if (state.narration === es) stopNarration();
+    };
+  }
+
+  function stopNarration() {
+    if (state.narration) {
+      state.narration.close();
+      state.narration = null;
+    }
+  }
+
   // ── UI helpers ────────────────────────────────────────────────────────
   function showError(msg) {
     $('#error-banner').text(msg).removeClass('hidden');
@@ -1406,19 +1412,6 @@
       .always(function () { showLoading(false); });
   }
 
-  function put(url, data) {
-    showLoading(true);
-    return $.ajax({
-      url:         url,
-      method:      'PUT',
-      contentType: 'application/json',
-      data:        JSON.stringify(data),
-      dataType:    'json',
-    }).fail(function () {
-      // PUT errors are non-fatal — log but don't show banner
-    }).always(function () { showLoading(false); });
-  }
-
   function handleAjaxError(xhr) {
     let msg = 'Server error';
     try {
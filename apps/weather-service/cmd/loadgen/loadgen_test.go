@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"weather-service/internal/queue"
+)
+
+func TestPlanner_DeterministicForSeed(t *testing.T) {
+	opts, err := parseFlags([]string{"-seed", "42", "-chaos", "0.3", "-mode", "mixed"})
+	if err != nil {
+		t.Fatalf("parseFlags failed: %v", err)
+	}
+
+	a, b := newPlanner(opts), newPlanner(opts)
+	for i := 0; i < 500; i++ {
+		if ra, rb := a.next(), b.next(); ra != rb {
+			t.Fatalf("Expected identical plans for the same seed, diverged at %d: %+v vs %+v", i, ra, rb)
+		}
+	}
+}
+
+func TestPlanner_MixedAlternatesPerRequest(t *testing.T) {
+	opts, err := parseFlags([]string{"-mode", "mixed", "-queue-batch", "3"})
+	if err != nil {
+		t.Fatalf("parseFlags failed: %v", err)
+	}
+
+	// Mirror run: every queue push draws batch-1 extra jobs.
+	p := newPlanner(opts)
+	var queued, sent int
+	for i := 0; i < 100; i++ {
+		r := p.next()
+		if !r.queue {
+			sent++
+			continue
+		}
+		queued++
+		for j := 1; j < opts.queueBatch; j++ {
+			p.job()
+		}
+	}
+	if queued != 50 || sent != 50 {
+		t.Errorf("Expected 50 queue pushes and 50 HTTP requests, got %d and %d", queued, sent)
+	}
+}
+
+func TestPlanner_ZipfFavoursHeadLocation(t *testing.T) {
+	opts, err := parseFlags([]string{"-locations", "hot,warm,cool,cold", "-zipf-s", "2"})
+	if err != nil {
+		t.Fatalf("parseFlags failed: %v", err)
+	}
+
+	counts := make(map[string]int)
+	p := newPlanner(opts)
+	for i := 0; i < 2000; i++ {
+		counts[p.next().location]++
+	}
+	if counts["hot"] <= counts["cold"] {
+		t.Errorf("Expected head location to dominate, got %v", counts)
+	}
+}
+
+func TestParseFlags_Validation(t *testing.T) {
+	cases := [][]string{
+		{"-mode", "carrier-pigeon"},
+		{"-rps", "0"},
+		{"-chaos", "1.5"},
+		{"-zipf-s", "1"},
+		{"-locations", " , "},
+	}
+	for _, args := range cases {
+		if _, err := parseFlags(args); err == nil {
+			t.Errorf("Expected %v to be rejected", args)
+		}
+	}
+}
+
+func TestOutcomeOf(t *testing.T) {
+	cases := []struct {
+		err  error
+		code int
+		want string
+	}{
+		{nil, 200, "ok"},
+		{nil, 0, "ok"},
+		{nil, 500, "http_500"},
+		{nil, 429, "http_429"},
+		{errors.New("dial tcp: connection refused"), 0, "connection_refused"},
+		{errors.New("Client.Timeout exceeded"), 0, "timeout"},
+		{errors.New("boom"), 0, "transport_error"},
+		{queue.ErrDraining, 0, "draining"},
+	}
+	for _, tc := range cases {
+		if got := outcomeOf(tc.err, tc.code); got != tc.want {
+			t.Errorf("outcomeOf(%v, %d) = %s, want %s", tc.err, tc.code, got, tc.want)
+		}
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	var samples []time.Duration
+	for i := 1; i <= 100; i++ {
+		samples = append(samples, time.Duration(i)*time.Millisecond)
+	}
+	if got := percentile(samples, 50); got != 50*time.Millisecond {
+		t.Errorf("Expected p50 50ms, got %s", got)
+	}
+	if got := percentile(samples, 99); got != 99*time.Millisecond {
+		t.Errorf("Expected p99 99ms, got %s", got)
+	}
+	if got := percentile(nil, 99); got != 0 {
+		t.Errorf("Expected 0 for no samples, got %s", got)
+	}
+}
+
+func TestReport_Print(t *testing.T) {
+	rep := newReport()
+	rep.record("http", 10*time.Millisecond, "ok", 1)
+	rep.record("http", 20*time.Millisecond, "http_500", 1)
+	rep.record("queue", time.Millisecond, "ok", 5)
+	rep.elapsed = time.Second
+
+	var buf bytes.Buffer
+	rep.Print(&buf)
+	out := buf.String()
+	for _, want := range []string{"[http] total=2", "[queue] total=5", "http_500", "p99="} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected report to contain %q, got:\n%s", want, out)
+		}
+	}
+}
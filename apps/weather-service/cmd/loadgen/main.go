@@ -0,0 +1,256 @@
+// Package main is a deterministic load generator for weather-service.
+//
+// It drives HTTP lookups and/or Redis queue jobs at a fixed rate with a
+// seeded, Zipf-distributed location mix and a configurable chaos share, then
+// prints latency percentiles and an error breakdown. Same flags + same seed
+// = same request sequence, so KEDA scaling scenarios can be replayed.
+//
+//	go run ./cmd/loadgen -mode mixed -rps 200 -duration 2m -chaos 0.2 -seed 42
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"time"
+
+	"weather-service/internal/queue"
+)
+
+type options struct {
+	target      string
+	mode        string
+	rps         float64
+	duration    time.Duration
+	concurrency int
+	locations   []string
+	zipfS       float64
+	zipfV       float64
+	chaos       float64
+	queueBatch  int
+	seed        int64
+	redis       queue.Options
+}
+
+func main() {
+	opts, err := parseFlags(os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "loadgen:", err)
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var q *queue.Client
+	if opts.mode != "http" {
+		q = queue.NewClient(opts.redis)
+		defer func() {
+			if err := q.Close(); err != nil {
+				slog.Error("redis client close failed", "error", err)
+			}
+		}()
+	}
+
+	fmt.Printf("loadgen: mode=%s rps=%g duration=%s chaos=%.0f%% seed=%d locations=%d zipf(s=%g,v=%g)\n",
+		opts.mode, opts.rps, opts.duration, opts.chaos*100, opts.seed, len(opts.locations), opts.zipfS, opts.zipfV)
+
+	report := run(ctx, opts, &http.Client{Timeout: 10 * time.Second}, q)
+	report.Print(os.Stdout)
+}
+
+func parseFlags(args []string) (*options, error) {
+	o := &options{}
+	var locations string
+	fs := flag.NewFlagSet("loadgen", flag.ContinueOnError)
+	fs.StringVar(&o.target, "target", "http://localhost:8080", "weather-service base URL (http mode)")
+	fs.StringVar(&o.mode, "mode", "http", "traffic type: http | queue | mixed")
+	fs.Float64Var(&o.rps, "rps", 50, "requests (or queue jobs) per second")
+	fs.DurationVar(&o.duration, "duration", 30*time.Second, "how long to generate load")
+	fs.IntVar(&o.concurrency, "concurrency", 32, "max in-flight HTTP requests")
+	fs.StringVar(&locations, "locations", "lubbock,austin,dallas,houston,amarillo,el-paso,san-antonio,midland", "comma-separated locations, most popular first")
+	fs.Float64Var(&o.zipfS, "zipf-s", 1.2, "Zipf skew (>1); higher = hotter head locations")
+	fs.Float64Var(&o.zipfV, "zipf-v", 1, "Zipf v parameter (>=1)")
+	fs.Float64Var(&o.chaos, "chaos", 0, "fraction of requests sent with chaos=true (0-1)")
+	fs.IntVar(&o.queueBatch, "queue-batch", 1, "jobs pushed per queue tick (queue/mixed mode)")
+	fs.Int64Var(&o.seed, "seed", 1, "random seed; reuse to replay the same sequence")
+	fs.StringVar(&o.redis.Addr, "redis-addr", "localhost:6379", "Redis host:port (queue/mixed mode)")
+	fs.StringVar(&o.redis.Password, "redis-password", os.Getenv("REDIS_PASSWORD"), "Redis password (default $REDIS_PASSWORD)")
+	fs.StringVar(&o.redis.QueueName, "redis-queue-name", "weather:jobs", "Redis queue name")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	for _, l := range strings.Split(locations, ",") {
+		if l = strings.TrimSpace(l); l != "" {
+			o.locations = append(o.locations, l)
+		}
+	}
+	o.redis.DialTimeout = 5 * time.Second
+	o.redis.ReadTimeout = 5 * time.Second
+	o.redis.WriteTimeout = 5 * time.Second
+
+	switch {
+	case o.mode != "http" && o.mode != "queue" && o.mode != "mixed":
+		return nil, fmt.Errorf("-mode must be http, queue, or mixed (got %q)", o.mode)
+	case o.rps <= 0:
+		return nil, fmt.Errorf("-rps must be positive")
+	case o.duration <= 0:
+		return nil, fmt.Errorf("-duration must be positive")
+	case o.concurrency < 1:
+		return nil, fmt.Errorf("-concurrency must be at least 1")
+	case len(o.locations) == 0:
+		return nil, fmt.Errorf("-locations must not be empty")
+	case o.zipfS <= 1 || o.zipfV < 1:
+		return nil, fmt.Errorf("-zipf-s must be > 1 and -zipf-v >= 1")
+	case o.chaos < 0 || o.chaos > 1:
+		return nil, fmt.Errorf("-chaos must be between 0 and 1")
+	case o.queueBatch < 1:
+		return nil, fmt.Errorf("-queue-batch must be at least 1")
+	}
+	return o, nil
+}
+
+// request is one planned unit of work. The plan is drawn from the seeded RNG
+// up front on the ticker goroutine so concurrency never changes the sequence.
+type request struct {
+	location string
+	chaos    bool
+	queue    bool
+}
+
+// planner draws locations from a Zipf distribution over opts.locations. In
+// mixed mode it alternates HTTP requests and queue pushes; a push carries
+// -queue-batch jobs but still counts as one request.
+type planner struct {
+	rng   *rand.Rand
+	zipf  *rand.Zipf
+	opts  *options
+	count int
+}
+
+func newPlanner(opts *options) *planner {
+	rng := rand.New(rand.NewSource(opts.seed))
+	return &planner{
+		rng:  rng,
+		zipf: rand.NewZipf(rng, opts.zipfS, opts.zipfV, uint64(len(opts.locations)-1)),
+		opts: opts,
+	}
+}
+
+func (p *planner) next() request {
+	p.count++
+	r := p.job()
+	switch p.opts.mode {
+	case "queue":
+		r.queue = true
+	case "mixed":
+		r.queue = p.count%2 == 0
+	}
+	return r
+}
+
+// job draws one location and chaos flag without advancing the mode
+// alternation, for the extra jobs in a queue batch.
+func (p *planner) job() request {
+	return request{
+		location: p.opts.locations[p.zipf.Uint64()],
+		chaos:    p.rng.Float64() < p.opts.chaos,
+	}
+}
+
+func run(ctx context.Context, opts *options, hc *http.Client, q *queue.Client) *report {
+	ctx, cancel := context.WithTimeout(ctx, opts.duration)
+	defer cancel()
+
+	rep := newReport()
+	plan := newPlanner(opts)
+	sem := make(chan struct{}, opts.concurrency)
+	var wg sync.WaitGroup
+
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / opts.rps))
+	defer ticker.Stop()
+
+	start := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			rep.elapsed = time.Since(start)
+			return rep
+		case <-ticker.C:
+		}
+
+		r := plan.next()
+		if r.queue {
+			jobs := make([]*queue.Job, opts.queueBatch)
+			jobs[0] = &queue.Job{Location: r.location, Chaos: r.chaos}
+			for i := 1; i < len(jobs); i++ {
+				n := plan.job()
+				jobs[i] = &queue.Job{Location: n.location, Chaos: n.chaos}
+			}
+			t0 := time.Now()
+			_, err := q.PushMany(ctx, jobs)
+			rep.record("queue", time.Since(t0), outcomeOf(err, 0), len(jobs))
+			continue
+		}
+
+		select {
+		case sem <- struct{}{}:
+		default:
+			rep.record("http", 0, "dropped_client_saturated", 1)
+			continue
+		}
+		wg.Add(1)
+		go func(r request) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			url := opts.target + "/weather/" + r.location
+			if r.chaos {
+				url += "?chaos=true"
+			}
+			t0 := time.Now()
+			code, err := get(ctx, hc, url)
+			rep.record("http", time.Since(t0), outcomeOf(err, code), 1)
+		}(r)
+	}
+}
+
+func get(ctx context.Context, hc *http.Client, url string) (int, error) {
+	req, err := http.NewRequestWithContext(context.WithoutCancel(ctx), http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	return resp.StatusCode, nil
+}
+
+// outcomeOf buckets a result for the error breakdown.
+func outcomeOf(err error, code int) string {
+	switch {
+	case errors.Is(err, queue.ErrDraining):
+		return "draining"
+	case err != nil && strings.Contains(err.Error(), "Client.Timeout"):
+		return "timeout"
+	case err != nil && strings.Contains(err.Error(), "connection refused"):
+		return "connection_refused"
+	case err != nil:
+		return "transport_error"
+	case code == 0 || (code >= 200 && code < 300):
+		return "ok"
+	default:
+		return fmt.Sprintf("http_%d", code)
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+// report collects per-kind latencies and outcome counts.
+type report struct {
+	mu        sync.Mutex
+	latencies map[string][]time.Duration
+	outcomes  map[string]map[string]int
+	elapsed   time.Duration
+}
+
+func newReport() *report {
+	return &report{
+		latencies: make(map[string][]time.Duration),
+		outcomes:  make(map[string]map[string]int),
+	}
+}
+
+// record adds n units of work of the given kind ("http" | "queue").
+// A zero latency (e.g. a request dropped before sending) is not sampled.
+func (r *report) record(kind string, latency time.Duration, outcome string, n int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if latency > 0 {
+		r.latencies[kind] = append(r.latencies[kind], latency)
+	}
+	if r.outcomes[kind] == nil {
+		r.outcomes[kind] = make(map[string]int)
+	}
+	r.outcomes[kind][outcome] += n
+}
+
+// percentile returns the nearest-rank p-th percentile of sorted samples.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(p/100*float64(len(sorted))+0.5) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	return sorted[idx]
+}
+
+// Print writes a human-readable summary.
+func (r *report) Print(w io.Writer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kinds := make([]string, 0, len(r.outcomes))
+	for k := range r.outcomes {
+		kinds = append(kinds, k)
+	}
+	sort.Strings(kinds)
+
+	fmt.Fprintf(w, "\nelapsed: %s\n", r.elapsed.Round(time.Millisecond))
+	for _, kind := range kinds {
+		total := 0
+		for _, n := range r.outcomes[kind] {
+			total += n
+		}
+		rate := 0.0
+		if r.elapsed > 0 {
+			rate = float64(total) / r.elapsed.Seconds()
+		}
+		fmt.Fprintf(w, "\n[%s] total=%d achieved=%.1f/s\n", kind, total, rate)
+
+		lat := append([]time.Duration(nil), r.latencies[kind]...)
+		sort.Slice(lat, func(i, j int) bool { return lat[i] < lat[j] })
+		if len(lat) > 0 {
+			fmt.Fprintf(w, "  latency p50=%s p90=%s p95=%s p99=%s max=%s\n",
+				percentile(lat, 50), percentile(lat, 90), percentile(lat, 95), percentile(lat, 99), lat[len(lat)-1])
+		}
+
+		names := make([]string, 0, len(r.outcomes[kind]))
+		for name := range r.outcomes[kind] {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			n := r.outcomes[kind][name]
+			fmt.Fprintf(w, "  %-26s %7d (%5.1f%%)\n", name, n, 100*float64(n)/float64(total))
+		}
+	}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
 	"os"
@@ -10,6 +11,7 @@ import (
 	"strings"
 	"time"
 
+	"weather-service/internal/config"
 	"weather-service/internal/obs"
 	"weather-service/internal/queue"
 	"weather-service/internal/weather"
@@ -26,21 +28,61 @@ func main() {
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
 	slog.SetDefault(logger)
 
+	cfg, err := config.Load(os.Args[1:])
+	if err != nil {
+		slog.Error("config load failed", "error", err)
+		os.Exit(1)
+	}
+	// LevelVar lets a config reload change verbosity without rebuilding the logger.
+	var logLevel slog.LevelVar
+	level, _ := config.ParseLogLevel(cfg.LogLevel) // validated by Load
+	logLevel.Set(level)
+	slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: &logLevel})))
+
 	slog.Info("!!! BUILD: METRICS + REDIS QUEUE ENABLED !!!")
+	slog.Info("config loaded", "file", cfg.File, "workers", cfg.WorkerCount, "cache_ttl", cfg.CacheTTL.String(), "redis", cfg.Redis.Addr)
 
 	wClient := weather.NewClient()
-	qClient := queue.NewClient()
+	wClient.SetCacheTTL(cfg.CacheTTL)
+	qClient := queue.NewClient(queue.Options{
+		Addr:         cfg.Redis.Addr,
+		Password:     cfg.Redis.Password,
+		DB:           cfg.Redis.DB,
+		QueueName:    cfg.Redis.QueueName,
+		DialTimeout:  cfg.Redis.DialTimeout,
+		ReadTimeout:  cfg.Redis.ReadTimeout,
+		WriteTimeout: cfg.Redis.WriteTimeout,
+	})
 	defer func() {
 		if err := qClient.Close(); err != nil {
 			slog.Error("redis client close failed", "error", err)
 		}
 	}()
 
-	// Start Redis queue worker (consumes jobs, drives KEDA scaling visibility)
-	go runQueueWorker(context.Background(), qClient, wClient)
+	var reloader *config.Reloader
+	requestTimeout := func() time.Duration { return reloader.Current().RequestTimeout }
+
+	// Redis queue workers (consume jobs, drive KEDA scaling visibility).
+	// Started below, once reloader is set, since they read requestTimeout.
+	workers := newWorkerPool(context.Background(), func(ctx context.Context, id int) {
+		runQueueWorker(ctx, id, qClient, wClient, requestTimeout)
+	})
+
+	limiter := newRateLimiter(cfg.RateLimitRPS, cfg.RateLimitBurst)
+
+	// Hot reload: SIGHUP or config file change applies the safe subset live.
+	reloader = config.NewReloader(cfg, os.Args[1:], func(next *config.Config) {
+		level, _ := config.ParseLogLevel(next.LogLevel)
+		logLevel.Set(level)
+		wClient.SetCacheTTL(next.CacheTTL)
+		workers.Resize(next.WorkerCount)
+		limiter.SetLimit(next.RateLimitRPS, next.RateLimitBurst)
+	})
+	workers.Resize(cfg.WorkerCount)
+	go reloader.Watch(context.Background(), 5*time.Second)
 
 	// Periodically update queue length metric for Prometheus/Grafana
-	go runQueueLengthUpdater(context.Background(), qClient)
+	go runQueueLengthUpdater(context.Background(), qClient, cfg.QueueStatsInterval)
 
 	// BUSINESS LOGIC
 	apiHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -52,21 +94,37 @@ func main() {
 			return
 		}
 
+		// Effective config: GET /config - secrets redacted
+		if r.URL.Path == "/config" && r.Method == http.MethodGet {
+			w.Header().Set("Content-Type", "application/json")
+			if err := json.NewEncoder(w).Encode(reloader.Current().Redacted()); err != nil {
+				slog.Error("config response encode failed", "error", err)
+			}
+			return
+		}
+
 		// Queue load endpoint: POST /queue/load?count=N&chaos=true|false - bulk-load jobs for chaos/KEDA
 		if r.URL.Path == "/queue/load" && r.Method == http.MethodPost {
 			handleQueueLoad(w, r, qClient)
 			return
 		}
 
-		// Queue stats: GET /queue/stats - current length for dashboards
+		// Queue stats: GET /queue/stats - length, state, and oldest job age for dashboards
 		if r.URL.Path == "/queue/stats" && r.Method == http.MethodGet {
 			handleQueueStats(w, r, qClient)
 			return
 		}
 
+		// Queue admin: peek, enqueue, purge, pause/resume, drain
+		if strings.HasPrefix(r.URL.Path, "/queue/") && handleQueueAdmin(w, r, qClient, reloader.Current().AdminToken) {
+			return
+		}
+
 		if strings.HasPrefix(r.URL.Path, "/weather/") {
 			location := strings.TrimPrefix(r.URL.Path, "/weather/")
-			data, err := wClient.GetWeather(r.Context(), location)
+			ctx, cancel := context.WithTimeout(r.Context(), requestTimeout())
+			defer cancel()
+			data, err := wClient.GetWeather(ctx, location)
 			if err != nil {
 				slog.Error("API Handler: Returning 500", "error", err)
 				http.Error(w, err.Error(), 500)
@@ -84,26 +142,45 @@ func main() {
 	})
 
 	// METRICS & SRE MIDDLEWARE
-	sreHandler := sreMiddleware(apiHandler)
+	sreHandler := sreMiddleware(rateLimitMiddleware(limiter, apiHandler))
 
 	rootMux := http.NewServeMux()
 	rootMux.Handle("/metrics", promhttp.Handler())
 	rootMux.Handle("/", sreHandler)
 
-	slog.Info("Server starting on :8080")
-	if err := http.ListenAndServe(":8080", rootMux); err != nil {
+	srv := &http.Server{
+		Addr:         ":" + cfg.Port,
+		Handler:      rootMux,
+		ReadTimeout:  cfg.ReadTimeout,
+		WriteTimeout: cfg.WriteTimeout,
+	}
+	slog.Info("Server starting", "addr", srv.Addr)
+	if err := srv.ListenAndServe(); err != nil {
 		slog.Error("server failed", "error", err)
 	}
 }
 
-func runQueueWorker(ctx context.Context, q *queue.Client, w *weather.Client) {
+func runQueueWorker(ctx context.Context, id int, q *queue.Client, w *weather.Client, timeout func() time.Duration) {
+	busy := obs.WorkerBusy.WithLabelValues(strconv.Itoa(id))
+	busy.Set(0)
+	// Drop the series when the pool shrinks so dashboards count live workers only.
+	defer obs.WorkerBusy.DeleteLabelValues(strconv.Itoa(id))
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		default:
+			// Pause is a cluster-wide Redis flag, so every replica idles together.
+			if state, err := q.State(ctx); err == nil && state == queue.StatePaused {
+				sleepCtx(ctx, time.Second)
+				continue
+			}
+
 			job, err := q.Pop(ctx)
 			if err != nil {
+				if ctx.Err() != nil {
+					return // worker stopped (pool resized or shutdown)
+				}
 				slog.Error("queue worker: pop failed", "error", err)
 				time.Sleep(2 * time.Second)
 				continue
@@ -111,26 +188,46 @@ func runQueueWorker(ctx context.Context, q *queue.Client, w *weather.Client) {
 			if job == nil {
 				continue
 			}
+			// Paused while blocked in BRPOP: hand the job back untouched.
+			if state, err := q.State(ctx); err == nil && state == queue.StatePaused {
+				if err := q.Requeue(context.WithoutCancel(ctx), job); err != nil {
+					slog.Error("queue worker: requeue on pause failed", "location", job.Location, "error", err)
+				}
+				continue
+			}
+
+			start := time.Now()
+			if !job.EnqueuedAt.IsZero() {
+				obs.JobWaitDuration.Observe(start.Sub(job.EnqueuedAt).Seconds())
+			}
+			busy.Set(1)
 
-			// Build context with chaos flag for weather client
-			jCtx := weather.WithChaosTrigger(ctx, "false")
+			// Build context with chaos flag for weather client. The job outlives
+			// a worker stop so an already-popped job is never dropped.
+			baseCtx := context.WithoutCancel(ctx)
+			jCtx := weather.WithChaosTrigger(baseCtx, "false")
 			if job.Chaos {
-				jCtx = weather.WithChaosTrigger(ctx, "true")
+				jCtx = weather.WithChaosTrigger(baseCtx, "true")
 			}
 
+			jCtx, cancel := context.WithTimeout(jCtx, timeout())
 			_, err = w.GetWeather(jCtx, job.Location)
+			cancel()
+			outcome := "success"
 			if err != nil {
-				obs.JobsProcessedTotal.WithLabelValues("error").Inc()
+				outcome = "error"
 				slog.Warn("queue worker: job failed", "location", job.Location, "chaos", job.Chaos, "error", err)
-			} else {
-				obs.JobsProcessedTotal.WithLabelValues("success").Inc()
 			}
+			obs.JobsProcessedTotal.WithLabelValues(outcome).Inc()
+			obs.JobProcessingDuration.WithLabelValues(outcome).Observe(time.Since(start).Seconds())
+			obs.JobsByLocationTotal.WithLabelValues(obs.LocationLabel(job.Location), outcome).Inc()
+			busy.Set(0)
 		}
 	}
 }
 
-func runQueueLengthUpdater(ctx context.Context, q *queue.Client) {
-	ticker := time.NewTicker(2 * time.Second)
+func runQueueLengthUpdater(ctx context.Context, q *queue.Client, interval time.Duration) {
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 	for {
 		select {
@@ -143,10 +240,40 @@ func runQueueLengthUpdater(ctx context.Context, q *queue.Client) {
 				continue
 			}
 			obs.QueueLength.Set(float64(n))
+
+			age, ok, err := q.OldestAge(ctx)
+			if err != nil {
+				obs.QueueOldestJobAge.Set(-1)
+			} else if ok {
+				obs.QueueOldestJobAge.Set(age.Seconds())
+			} else {
+				obs.QueueOldestJobAge.Set(0)
+			}
+			if state, err := q.State(ctx); err == nil {
+				obs.QueuePaused.Set(boolGauge(state == queue.StatePaused))
+				obs.QueueDraining.Set(boolGauge(state == queue.StateDraining))
+			}
 		}
 	}
 }
 
+// sleepCtx waits for d or until ctx is done, whichever comes first.
+func sleepCtx(ctx context.Context, d time.Duration) {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+	case <-t.C:
+	}
+}
+
+func boolGauge(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
 func handleQueueLoad(w http.ResponseWriter, r *http.Request, q *queue.Client) {
 	countStr := r.URL.Query().Get("count")
 	if countStr == "" {
@@ -167,6 +294,10 @@ func handleQueueLoad(w http.ResponseWriter, r *http.Request, q *queue.Client) {
 	}
 
 	n, err := q.PushMany(r.Context(), jobs)
+	if errors.Is(err, queue.ErrDraining) {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -176,7 +307,7 @@ func handleQueueLoad(w http.ResponseWriter, r *http.Request, q *queue.Client) {
 	if err := json.NewEncoder(w).Encode(map[string]interface{}{
 		"loaded": n,
 		"chaos":  chaos,
-		"queue":  "weather:jobs",
+		"queue":  q.Name(),
 	}); err != nil {
 		http.Error(w, "failed to encode response", http.StatusInternalServerError)
 	}
@@ -188,10 +319,22 @@ func handleQueueStats(w http.ResponseWriter, r *http.Request, q *queue.Client) {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	state, err := q.State(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	age, _, err := q.OldestAge(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(map[string]interface{}{
-		"length": n,
-		"queue":  "weather:jobs",
+		"length":                 n,
+		"queue":                  q.Name(),
+		"state":                  state,
+		"oldest_job_age_seconds": age.Seconds(),
 	}); err != nil {
 		http.Error(w, "failed to encode response", http.StatusInternalServerError)
 	}
@@ -219,13 +362,7 @@ func sreMiddleware(next http.Handler) http.Handler {
 		duration := time.Since(start).Seconds()
 
 		// RECORD METRICS
-		path := r.URL.Path
-		if strings.HasPrefix(path, "/weather/") {
-			path = "/weather/:location"
-		} else if strings.HasPrefix(path, "/queue/") {
-			path = "/queue/:action"
-		}
-
+		path := metricPath(r.URL.Path)
 		obs.HTTPRequestsTotal.WithLabelValues(path, r.Method, strconv.Itoa(rw.statusCode), http.StatusText(rw.statusCode)).Inc()
 		obs.HTTPRequestDuration.WithLabelValues(path, r.Method).Observe(duration)
 
@@ -233,6 +370,30 @@ func sreMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// queueRoutes keep their own path label; any other /queue/ path is
+// "/queue/:action" so unknown paths can't blow up label cardinality.
+var queueRoutes = map[string]bool{
+	"/queue/load":    true,
+	"/queue/stats":   true,
+	"/queue/peek":    true,
+	"/queue/enqueue": true,
+	"/queue/purge":   true,
+	"/queue/pause":   true,
+	"/queue/resume":  true,
+	"/queue/drain":   true,
+}
+
+// metricPath is the path label for request metrics.
+func metricPath(path string) string {
+	switch {
+	case strings.HasPrefix(path, "/weather/"):
+		return "/weather/:location"
+	case strings.HasPrefix(path, "/queue/") && !queueRoutes[path]:
+		return "/queue/:action"
+	}
+	return path
+}
+
 // statusRecorder captures the status code for metrics
 type statusRecorder struct {
 	http.ResponseWriter
@@ -143,6 +143,21 @@ func TestSREMiddleware_PathNormalization(t *testing.T) {
 	}
 }
 
+func TestMetricPath(t *testing.T) {
+	for path, want := range map[string]string{
+		"/weather/lubbock": "/weather/:location",
+		"/queue/load":      "/queue/load",
+		"/queue/stats":     "/queue/stats",
+		"/queue/purge":     "/queue/purge",
+		"/queue/bogus":     "/queue/:action",
+		"/health":          "/health",
+	} {
+		if got := metricPath(path); got != want {
+			t.Errorf("metricPath(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
 func TestSREMiddleware_StatusCodeCapture(t *testing.T) {
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusNotFound)
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"weather-service/internal/queue"
+)
+
+const (
+	defaultPeekCount = 10
+	maxPeekCount     = 100
+	maxEnqueueJobs   = 10000
+)
+
+// enqueueRequest is the body of POST /queue/enqueue.
+type enqueueRequest struct {
+	Locations []string `json:"locations"`
+	Chaos     bool     `json:"chaos"`
+}
+
+// queueAdminRoutes are the POST endpoints that change the queue; they need
+// the admin token.
+var queueAdminRoutes = map[string]bool{
+	"/queue/enqueue": true,
+	"/queue/purge":   true,
+	"/queue/pause":   true,
+	"/queue/resume":  true,
+	"/queue/drain":   true,
+}
+
+// handleQueueAdmin serves the queue administration endpoints:
+//
+//	GET  /queue/peek?n=N   first N jobs in processing order (default 10, max 100)
+//	POST /queue/enqueue    {"locations": [...], "chaos": bool}
+//	POST /queue/purge      delete every queued job
+//	POST /queue/pause      workers stop popping (cluster-wide)
+//	POST /queue/resume     back to normal
+//	POST /queue/drain      workers keep popping, new enqueues rejected with 503
+//
+// Everything but peek requires adminToken (see requireAdmin). It reports
+// false if the path is not an admin endpoint.
+func handleQueueAdmin(w http.ResponseWriter, r *http.Request, q *queue.Client, adminToken string) bool {
+	switch {
+	case r.URL.Path == "/queue/peek" && r.Method == http.MethodGet:
+		handleQueuePeek(w, r, q)
+		return true
+	case !queueAdminRoutes[r.URL.Path] || r.Method != http.MethodPost:
+		return false
+	case !requireAdmin(w, r, adminToken):
+		return true
+	}
+	switch r.URL.Path {
+	case "/queue/enqueue":
+		handleQueueEnqueue(w, r, q)
+	case "/queue/purge":
+		handleQueuePurge(w, r, q)
+	case "/queue/pause":
+		handleQueueSetState(w, r, q, queue.StatePaused)
+	case "/queue/resume":
+		handleQueueSetState(w, r, q, queue.StateRunning)
+	case "/queue/drain":
+		handleQueueSetState(w, r, q, queue.StateDraining)
+	}
+	return true
+}
+
+// requireAdmin checks the admin token from X-Admin-Token or
+// "Authorization: Bearer". With no token configured, admin actions are
+// disabled. On failure it writes the error response and returns false.
+func requireAdmin(w http.ResponseWriter, r *http.Request, adminToken string) bool {
+	if adminToken == "" {
+		http.Error(w, "queue admin actions are disabled; set ADMIN_TOKEN to enable them", http.StatusForbidden)
+		return false
+	}
+	got := r.Header.Get("X-Admin-Token")
+	if got == "" {
+		got = strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	}
+	if subtle.ConstantTimeCompare([]byte(got), []byte(adminToken)) != 1 {
+		http.Error(w, "admin token required (send X-Admin-Token)", http.StatusUnauthorized)
+		return false
+	}
+	return true
+}
+
+func handleQueuePeek(w http.ResponseWriter, r *http.Request, q *queue.Client) {
+	n, err := parsePeekCount(r.URL.Query().Get("n"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	jobs, err := q.Peek(r.Context(), int64(n))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if jobs == nil {
+		jobs = []*queue.Job{}
+	}
+	writeQueueJSON(w, map[string]interface{}{
+		"queue": q.Name(),
+		"jobs":  jobs,
+	})
+}
+
+func handleQueueEnqueue(w http.ResponseWriter, r *http.Request, q *queue.Client) {
+	req, err := parseEnqueueRequest(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	jobs := make([]*queue.Job, len(req.Locations))
+	for i, loc := range req.Locations {
+		jobs[i] = &queue.Job{Location: loc, Chaos: req.Chaos}
+	}
+	n, err := q.PushMany(r.Context(), jobs)
+	if errors.Is(err, queue.ErrDraining) {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeQueueJSON(w, map[string]interface{}{
+		"enqueued": n,
+		"chaos":    req.Chaos,
+		"queue":    q.Name(),
+	})
+}
+
+func handleQueuePurge(w http.ResponseWriter, r *http.Request, q *queue.Client) {
+	n, err := q.Purge(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	slog.Warn("queue purged", "queue", q.Name(), "removed", n)
+	writeQueueJSON(w, map[string]interface{}{
+		"purged": n,
+		"queue":  q.Name(),
+	})
+}
+
+func handleQueueSetState(w http.ResponseWriter, r *http.Request, q *queue.Client, s queue.State) {
+	if err := q.SetState(r.Context(), s); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	slog.Info("queue state changed", "queue", q.Name(), "state", s)
+	writeQueueJSON(w, map[string]interface{}{
+		"state": s,
+		"queue": q.Name(),
+	})
+}
+
+func writeQueueJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// parsePeekCount reads the n query parameter; empty means the default.
+func parsePeekCount(s string) (int, error) {
+	if s == "" {
+		return defaultPeekCount, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 || n > maxPeekCount {
+		return 0, fmt.Errorf("n must be an integer between 1 and %d", maxPeekCount)
+	}
+	return n, nil
+}
+
+// parseEnqueueRequest decodes and validates an enqueue body. Locations are
+// trimmed; anything that could not appear in /weather/:location is rejected.
+func parseEnqueueRequest(body io.Reader) (*enqueueRequest, error) {
+	var req enqueueRequest
+	dec := json.NewDecoder(io.LimitReader(body, 1<<20))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		return nil, fmt.Errorf("invalid JSON body: %w", err)
+	}
+	if len(req.Locations) == 0 {
+		return nil, errors.New("locations must not be empty")
+	}
+	if len(req.Locations) > maxEnqueueJobs {
+		return nil, fmt.Errorf("at most %d locations per request", maxEnqueueJobs)
+	}
+	for i, loc := range req.Locations {
+		loc = strings.TrimSpace(loc)
+		if loc == "" || strings.Contains(loc, "/") {
+			return nil, fmt.Errorf("locations[%d]: %q is not a valid location", i, req.Locations[i])
+		}
+		req.Locations[i] = loc
+	}
+	return &req, nil
+}
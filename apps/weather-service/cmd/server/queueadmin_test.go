@@ -0,0 +1,98 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestParsePeekCount(t *testing.T) {
+	if n, err := parsePeekCount(""); err != nil || n != defaultPeekCount {
+		t.Errorf("Expected default %d, got %d (err %v)", defaultPeekCount, n, err)
+	}
+	if n, err := parsePeekCount("25"); err != nil || n != 25 {
+		t.Errorf("Expected 25, got %d (err %v)", n, err)
+	}
+	for _, bad := range []string{"0", "-3", "abc", "101"} {
+		if _, err := parsePeekCount(bad); err == nil {
+			t.Errorf("Expected error for n=%q, got nil", bad)
+		}
+	}
+}
+
+func TestParseEnqueueRequest(t *testing.T) {
+	req, err := parseEnqueueRequest(strings.NewReader(`{"locations":[" austin ","lubbock"],"chaos":true}`))
+	if err != nil {
+		t.Fatalf("Expected valid request, got: %v", err)
+	}
+	if len(req.Locations) != 2 || req.Locations[0] != "austin" || req.Locations[1] != "lubbock" {
+		t.Errorf("Expected trimmed [austin lubbock], got %v", req.Locations)
+	}
+	if !req.Chaos {
+		t.Error("Expected chaos true")
+	}
+}
+
+func TestParseEnqueueRequest_Invalid(t *testing.T) {
+	cases := map[string]string{
+		"empty list":    `{"locations":[]}`,
+		"blank entry":   `{"locations":["austin","  "]}`,
+		"slash":         `{"locations":["a/b"]}`,
+		"unknown field": `{"locations":["austin"],"priority":1}`,
+		"not json":      `locations=austin`,
+	}
+	for name, body := range cases {
+		if _, err := parseEnqueueRequest(strings.NewReader(body)); err == nil {
+			t.Errorf("%s: expected error, got nil", name)
+		}
+	}
+}
+
+func TestHandleQueueAdmin_UnknownRoute(t *testing.T) {
+	// Non-admin routes and wrong methods fall through without touching Redis.
+	for _, tc := range []struct{ method, path string }{
+		{http.MethodGet, "/queue/stats"},
+		{http.MethodGet, "/queue/purge"},
+		{http.MethodPost, "/queue/peek"},
+	} {
+		rr := httptest.NewRecorder()
+		if handleQueueAdmin(rr, httptest.NewRequest(tc.method, tc.path, nil), nil, "secret") {
+			t.Errorf("Expected %s %s not to be handled", tc.method, tc.path)
+		}
+	}
+}
+
+func TestHandleQueueAdmin_RequiresToken(t *testing.T) {
+	// Rejected before touching Redis, so a nil client is fine.
+	for _, tc := range []struct {
+		name, token, header string
+		want                int
+	}{
+		{"disabled", "", "secret", http.StatusForbidden},
+		{"missing", "secret", "", http.StatusUnauthorized},
+		{"wrong", "secret", "guess", http.StatusUnauthorized},
+	} {
+		for path := range queueAdminRoutes {
+			req := httptest.NewRequest(http.MethodPost, path, nil)
+			if tc.header != "" {
+				req.Header.Set("X-Admin-Token", tc.header)
+			}
+			rr := httptest.NewRecorder()
+			if !handleQueueAdmin(rr, req, nil, tc.token) {
+				t.Fatalf("%s: expected %s to be handled", tc.name, path)
+			}
+			if rr.Code != tc.want {
+				t.Errorf("%s: expected %d for %s, got %d", tc.name, tc.want, path, rr.Code)
+			}
+		}
+	}
+}
+
+func TestRequireAdmin_BearerToken(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/queue/purge", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	if !requireAdmin(httptest.NewRecorder(), req, "secret") {
+		t.Error("Expected a matching bearer token to be accepted")
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"weather-service/internal/obs"
+)
+
+// rateLimiter is a token bucket whose rate can be changed on config reload.
+// A rate of 0 disables limiting.
+type rateLimiter struct {
+	mu     sync.Mutex
+	rps    float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+func newRateLimiter(rps float64, burst int) *rateLimiter {
+	l := &rateLimiter{now: time.Now}
+	l.SetLimit(rps, burst)
+	return l
+}
+
+// SetLimit updates the rate and burst. The bucket starts full after a change.
+func (l *rateLimiter) SetLimit(rps float64, burst int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rps = rps
+	l.burst = float64(burst)
+	l.tokens = l.burst
+	l.last = l.now()
+}
+
+// Allow takes one token if available.
+func (l *rateLimiter) Allow() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.rps <= 0 {
+		return true
+	}
+	now := l.now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rps
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
+
+// rateLimitMiddleware answers 429 when the limiter is exhausted.
+// Health checks are never limited so probes keep working under load.
+func rateLimitMiddleware(l *rateLimiter, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" && !l.Allow() {
+			obs.RateLimitedTotal.Inc()
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
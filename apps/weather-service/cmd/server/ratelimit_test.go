@@ -0,0 +1,65 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_Disabled(t *testing.T) {
+	l := newRateLimiter(0, 1)
+	for i := 0; i < 100; i++ {
+		if !l.Allow() {
+			t.Fatalf("Expected unlimited limiter to allow request %d", i)
+		}
+	}
+}
+
+func TestRateLimiter_BurstThenRefill(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := &rateLimiter{now: func() time.Time { return now }}
+	l.SetLimit(2, 3)
+
+	for i := 0; i < 3; i++ {
+		if !l.Allow() {
+			t.Fatalf("Expected burst request %d to be allowed", i)
+		}
+	}
+	if l.Allow() {
+		t.Fatal("Expected request beyond burst to be rejected")
+	}
+
+	// 2 rps → one token every 500ms.
+	now = now.Add(500 * time.Millisecond)
+	if !l.Allow() {
+		t.Error("Expected a refilled token after 500ms")
+	}
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	l := newRateLimiter(1, 1)
+	middleware := rateLimitMiddleware(l, handler)
+
+	rr := httptest.NewRecorder()
+	middleware.ServeHTTP(rr, httptest.NewRequest("GET", "/weather/lubbock", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected first request 200, got %d", rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	middleware.ServeHTTP(rr, httptest.NewRequest("GET", "/weather/lubbock", nil))
+	if rr.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected second request 429, got %d", rr.Code)
+	}
+
+	// Health checks bypass the limiter.
+	rr = httptest.NewRecorder()
+	middleware.ServeHTTP(rr, httptest.NewRequest("GET", "/health", nil))
+	if rr.Code != http.StatusOK {
+		t.Errorf("Expected /health to bypass limiter, got %d", rr.Code)
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"sync"
+)
+
+// workerPool runs a resizable set of identical queue workers so WORKER_COUNT
+// can change on config reload without restarting the pod.
+type workerPool struct {
+	mu      sync.Mutex
+	ctx     context.Context
+	run     func(ctx context.Context, id int)
+	cancels []context.CancelFunc
+	nextID  int
+}
+
+// newWorkerPool returns an empty pool. Each worker gets an id for per-worker
+// metrics that is never reused: a worker stopped by a shrink may still be
+// finishing its job, and must not share a series with its replacement.
+// Shrinking stops the newest workers first.
+func newWorkerPool(ctx context.Context, run func(ctx context.Context, id int)) *workerPool {
+	return &workerPool{ctx: ctx, run: run}
+}
+
+// Resize starts or stops workers until exactly n are running.
+// Stopped workers finish their current job before exiting.
+func (p *workerPool) Resize(n int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for len(p.cancels) < n {
+		ctx, cancel := context.WithCancel(p.ctx)
+		id := p.nextID
+		p.nextID++
+		p.cancels = append(p.cancels, cancel)
+		go p.run(ctx, id)
+	}
+	for len(p.cancels) > n {
+		last := len(p.cancels) - 1
+		p.cancels[last]()
+		p.cancels = p.cancels[:last]
+	}
+}
+
+// Size returns the number of running workers.
+func (p *workerPool) Size() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.cancels)
+}
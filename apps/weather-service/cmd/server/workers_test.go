@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWorkerPool_Resize(t *testing.T) {
+	var running atomic.Int32
+	pool := newWorkerPool(context.Background(), func(ctx context.Context, _ int) {
+		running.Add(1)
+		<-ctx.Done()
+		running.Add(-1)
+	})
+
+	waitFor := func(want int32) {
+		t.Helper()
+		deadline := time.Now().Add(time.Second)
+		for running.Load() != want {
+			if time.Now().After(deadline) {
+				t.Fatalf("Expected %d running workers, got %d", want, running.Load())
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	pool.Resize(3)
+	waitFor(3)
+	if pool.Size() != 3 {
+		t.Errorf("Expected Size 3, got %d", pool.Size())
+	}
+
+	pool.Resize(1)
+	waitFor(1)
+	if pool.Size() != 1 {
+		t.Errorf("Expected Size 1, got %d", pool.Size())
+	}
+
+	pool.Resize(0)
+	waitFor(0)
+}
+
+func TestWorkerPool_IDsNotReused(t *testing.T) {
+	var mu sync.Mutex
+	ids := map[int]bool{}
+	pool := newWorkerPool(context.Background(), func(ctx context.Context, id int) {
+		mu.Lock()
+		ids[id] = true
+		mu.Unlock()
+		<-ctx.Done()
+	})
+	defer pool.Resize(0)
+
+	pool.Resize(3)
+	pool.Resize(1)
+	pool.Resize(2) // adds id 3; ids 1 and 2 may still be finishing a job
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		mu.Lock()
+		n := len(ids)
+		mu.Unlock()
+		if n == 4 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	for _, id := range []int{0, 1, 2, 3} {
+		if !ids[id] {
+			t.Errorf("Expected worker id %d to have run, got %v", id, ids)
+		}
+	}
+	if len(ids) != 4 {
+		t.Errorf("Expected 4 distinct worker ids, got %v", ids)
+	}
+}
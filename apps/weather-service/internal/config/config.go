@@ -1,32 +1,325 @@
+// Package config loads typed runtime configuration for weather-service.
+//
+// Sources are layered, later ones winning:
+//
+//	defaults → YAML file (-config / CONFIG_FILE) → environment → command-line flags
+//
+// Load validates the merged result so a bad value fails the process at startup
+// instead of surfacing later as a confusing runtime error.
 package config
 
 import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
 	"os"
+	"strconv"
+	"strings"
 	"time"
+
+	"go.yaml.in/yaml/v3"
 )
 
+const redacted = "[REDACTED]"
+
+// RedisConfig holds the queue backend connection settings.
+type RedisConfig struct {
+	Addr         string        `yaml:"addr"`
+	Password     string        `yaml:"password"`
+	DB           int           `yaml:"db"`
+	QueueName    string        `yaml:"queue_name"`
+	DialTimeout  time.Duration `yaml:"dial_timeout"`
+	ReadTimeout  time.Duration `yaml:"read_timeout"`
+	WriteTimeout time.Duration `yaml:"write_timeout"`
+}
+
+// Config holds all runtime configuration for weather-service.
 type Config struct {
-	Port           string
-	WeatherAPIKey  string
-	LogLevel       string
-	CacheTTL       time.Duration
-	RequestTimeout time.Duration
+	Port               string        `yaml:"port"`
+	LogLevel           string        `yaml:"log_level"`
+	WeatherAPIKey      string        `yaml:"weather_api_key"`
+	CacheTTL           time.Duration `yaml:"cache_ttl"`
+	RequestTimeout     time.Duration `yaml:"request_timeout"`
+	ReadTimeout        time.Duration `yaml:"read_timeout"`
+	WriteTimeout       time.Duration `yaml:"write_timeout"`
+	WorkerCount        int           `yaml:"worker_count"`
+	QueueStatsInterval time.Duration `yaml:"queue_stats_interval"`
+	RateLimitRPS       float64       `yaml:"rate_limit_rps"`   // 0 disables HTTP rate limiting
+	RateLimitBurst     int           `yaml:"rate_limit_burst"` // tokens available for bursts
+	AdminToken         string        `yaml:"admin_token"`      // unlocks queue enqueue/purge/pause/resume/drain; "" disables them
+	Redis              RedisConfig   `yaml:"redis"`
+
+	// File is the YAML file the config was read from ("" if none).
+	File string `yaml:"-"`
 }
 
-// Load populates the configuration from environment variables with sensible defaults
-func Load() *Config {
+// Default returns the built-in configuration used when no source overrides a value.
+func Default() *Config {
 	return &Config{
-		Port:           getEnv("PORT", "8080"),
-		WeatherAPIKey:  getEnv("WEATHER_API_KEY", "mock-key"), //
-		LogLevel:       getEnv("LOG_LEVEL", "info"),
-		CacheTTL:       5 * time.Minute,
-		RequestTimeout: 10 * time.Second,
+		Port:               "8080",
+		LogLevel:           "info",
+		WeatherAPIKey:      "mock-key",
+		CacheTTL:           5 * time.Minute,
+		RequestTimeout:     10 * time.Second,
+		ReadTimeout:        15 * time.Second,
+		WriteTimeout:       30 * time.Second,
+		WorkerCount:        1,
+		QueueStatsInterval: 2 * time.Second,
+		RateLimitRPS:       0,
+		RateLimitBurst:     50,
+		Redis: RedisConfig{
+			Addr:         "localhost:6379",
+			QueueName:    "weather:jobs",
+			DialTimeout:  5 * time.Second,
+			ReadTimeout:  10 * time.Second,
+			WriteTimeout: 5 * time.Second,
+		},
 	}
 }
 
-func getEnv(key, fallback string) string {
-	if value, ok := os.LookupEnv(key); ok {
-		return value
+// Load builds the configuration from all sources and validates it.
+// args are the command-line arguments without the program name (os.Args[1:]).
+func Load(args []string) (*Config, error) {
+	// First pass only discovers -config; flag values are re-applied on top
+	// of file and env below so they keep the highest precedence.
+	path := os.Getenv("CONFIG_FILE")
+	if err := newFlagSet(Default(), &path).Parse(args); err != nil {
+		return nil, err
+	}
+
+	cfg := Default()
+	if path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+	if err := cfg.applyEnv(); err != nil {
+		return nil, err
+	}
+	if err := newFlagSet(cfg, &path).Parse(args); err != nil {
+		return nil, err
+	}
+	cfg.File = path
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// newFlagSet binds every knob to cfg, using the current values as defaults.
+func newFlagSet(cfg *Config, path *string) *flag.FlagSet {
+	fs := flag.NewFlagSet("weather-service", flag.ContinueOnError)
+	fs.StringVar(path, "config", *path, "path to a YAML config file")
+	fs.StringVar(&cfg.Port, "port", cfg.Port, "HTTP listen port")
+	fs.StringVar(&cfg.LogLevel, "log-level", cfg.LogLevel, "log level: debug|info|warn|error")
+	fs.StringVar(&cfg.WeatherAPIKey, "weather-api-key", cfg.WeatherAPIKey, "upstream weather API key")
+	fs.DurationVar(&cfg.CacheTTL, "cache-ttl", cfg.CacheTTL, "weather cache entry lifetime")
+	fs.DurationVar(&cfg.RequestTimeout, "request-timeout", cfg.RequestTimeout, "per-lookup timeout")
+	fs.DurationVar(&cfg.ReadTimeout, "read-timeout", cfg.ReadTimeout, "HTTP server read timeout")
+	fs.DurationVar(&cfg.WriteTimeout, "write-timeout", cfg.WriteTimeout, "HTTP server write timeout")
+	fs.IntVar(&cfg.WorkerCount, "worker-count", cfg.WorkerCount, "number of queue worker goroutines")
+	fs.DurationVar(&cfg.QueueStatsInterval, "queue-stats-interval", cfg.QueueStatsInterval, "queue length metric refresh interval")
+	fs.Float64Var(&cfg.RateLimitRPS, "rate-limit-rps", cfg.RateLimitRPS, "HTTP requests per second allowed (0 = unlimited)")
+	fs.IntVar(&cfg.RateLimitBurst, "rate-limit-burst", cfg.RateLimitBurst, "HTTP rate limiter burst size")
+	fs.StringVar(&cfg.AdminToken, "admin-token", cfg.AdminToken, "token for queue admin actions (empty disables them)")
+	fs.StringVar(&cfg.Redis.Addr, "redis-addr", cfg.Redis.Addr, "Redis host:port")
+	fs.StringVar(&cfg.Redis.Password, "redis-password", cfg.Redis.Password, "Redis password")
+	fs.IntVar(&cfg.Redis.DB, "redis-db", cfg.Redis.DB, "Redis logical database")
+	fs.StringVar(&cfg.Redis.QueueName, "redis-queue-name", cfg.Redis.QueueName, "Redis list used as the job queue")
+	fs.DurationVar(&cfg.Redis.DialTimeout, "redis-dial-timeout", cfg.Redis.DialTimeout, "Redis dial timeout")
+	fs.DurationVar(&cfg.Redis.ReadTimeout, "redis-read-timeout", cfg.Redis.ReadTimeout, "Redis read timeout")
+	fs.DurationVar(&cfg.Redis.WriteTimeout, "redis-write-timeout", cfg.Redis.WriteTimeout, "Redis write timeout")
+	return fs
+}
+
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config file: %w", err)
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("parse config file %s: %w", path, err)
+	}
+	return nil
+}
+
+func (c *Config) applyEnv() error {
+	var errs []error
+	envString("PORT", &c.Port)
+	envString("LOG_LEVEL", &c.LogLevel)
+	envString("WEATHER_API_KEY", &c.WeatherAPIKey)
+	envString("ADMIN_TOKEN", &c.AdminToken)
+	errs = append(errs,
+		envDuration("CACHE_TTL", &c.CacheTTL),
+		envDuration("REQUEST_TIMEOUT", &c.RequestTimeout),
+		envDuration("READ_TIMEOUT", &c.ReadTimeout),
+		envDuration("WRITE_TIMEOUT", &c.WriteTimeout),
+		envInt("WORKER_COUNT", &c.WorkerCount),
+		envDuration("QUEUE_STATS_INTERVAL", &c.QueueStatsInterval),
+		envFloat("RATE_LIMIT_RPS", &c.RateLimitRPS),
+		envInt("RATE_LIMIT_BURST", &c.RateLimitBurst),
+	)
+	envString("REDIS_ADDR", &c.Redis.Addr)
+	envString("REDIS_PASSWORD", &c.Redis.Password)
+	envString("REDIS_QUEUE_NAME", &c.Redis.QueueName)
+	errs = append(errs,
+		envInt("REDIS_DB", &c.Redis.DB),
+		envDuration("REDIS_DIAL_TIMEOUT", &c.Redis.DialTimeout),
+		envDuration("REDIS_READ_TIMEOUT", &c.Redis.ReadTimeout),
+		envDuration("REDIS_WRITE_TIMEOUT", &c.Redis.WriteTimeout),
+	)
+	return errors.Join(errs...)
+}
+
+// Validate reports every invalid field at once, joined into a single error.
+func (c *Config) Validate() error {
+	var errs []error
+	if p, err := strconv.Atoi(c.Port); err != nil || p < 1 || p > 65535 {
+		errs = append(errs, fmt.Errorf("port: %q is not a valid TCP port", c.Port))
+	}
+	if _, err := ParseLogLevel(c.LogLevel); err != nil {
+		errs = append(errs, fmt.Errorf("log_level: %w", err))
+	}
+	errs = append(errs,
+		positive("cache_ttl", c.CacheTTL),
+		positive("request_timeout", c.RequestTimeout),
+		positive("read_timeout", c.ReadTimeout),
+		positive("write_timeout", c.WriteTimeout),
+		positive("queue_stats_interval", c.QueueStatsInterval),
+		positive("redis.dial_timeout", c.Redis.DialTimeout),
+		positive("redis.read_timeout", c.Redis.ReadTimeout),
+		positive("redis.write_timeout", c.Redis.WriteTimeout),
+	)
+	if c.WorkerCount < 1 || c.WorkerCount > 64 {
+		errs = append(errs, fmt.Errorf("worker_count: %d is outside 1-64", c.WorkerCount))
+	}
+	if c.RateLimitRPS < 0 {
+		errs = append(errs, fmt.Errorf("rate_limit_rps: %g must not be negative", c.RateLimitRPS))
+	}
+	if c.RateLimitRPS > 0 && c.RateLimitBurst < 1 {
+		errs = append(errs, fmt.Errorf("rate_limit_burst: %d must be at least 1 when rate limiting is on", c.RateLimitBurst))
+	}
+	if _, _, err := net.SplitHostPort(c.Redis.Addr); err != nil {
+		errs = append(errs, fmt.Errorf("redis.addr: %q is not host:port", c.Redis.Addr))
+	}
+	if c.Redis.DB < 0 {
+		errs = append(errs, fmt.Errorf("redis.db: %d must not be negative", c.Redis.DB))
+	}
+	if strings.TrimSpace(c.Redis.QueueName) == "" {
+		errs = append(errs, errors.New("redis.queue_name: must not be empty"))
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+	return nil
+}
+
+// Redacted returns a JSON-friendly view of the config with secrets masked,
+// suitable for the /config endpoint.
+func (c *Config) Redacted() map[string]interface{} {
+	return map[string]interface{}{
+		"file":                 c.File,
+		"port":                 c.Port,
+		"log_level":            c.LogLevel,
+		"weather_api_key":      mask(c.WeatherAPIKey),
+		"cache_ttl":            c.CacheTTL.String(),
+		"request_timeout":      c.RequestTimeout.String(),
+		"read_timeout":         c.ReadTimeout.String(),
+		"write_timeout":        c.WriteTimeout.String(),
+		"worker_count":         c.WorkerCount,
+		"queue_stats_interval": c.QueueStatsInterval.String(),
+		"rate_limit_rps":       c.RateLimitRPS,
+		"rate_limit_burst":     c.RateLimitBurst,
+		"admin_token":          mask(c.AdminToken),
+		"redis": map[string]interface{}{
+			"addr":          c.Redis.Addr,
+			"password":      mask(c.Redis.Password),
+			"db":            c.Redis.DB,
+			"queue_name":    c.Redis.QueueName,
+			"dial_timeout":  c.Redis.DialTimeout.String(),
+			"read_timeout":  c.Redis.ReadTimeout.String(),
+			"write_timeout": c.Redis.WriteTimeout.String(),
+		},
+	}
+}
+
+// ParseLogLevel maps a config log level onto slog.
+func ParseLogLevel(s string) (slog.Level, error) {
+	switch strings.ToLower(s) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info", "":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return slog.LevelInfo, fmt.Errorf("unknown level %q (want debug|info|warn|error)", s)
+}
+
+func mask(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return redacted
+}
+
+func positive(name string, d time.Duration) error {
+	if d <= 0 {
+		return fmt.Errorf("%s: %s must be positive", name, d)
+	}
+	return nil
+}
+
+func envString(key string, dst *string) {
+	if v, ok := os.LookupEnv(key); ok && v != "" {
+		*dst = v
+	}
+}
+
+func envInt(key string, dst *int) error {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return fmt.Errorf("%s: %q is not an integer", key, v)
+	}
+	*dst = n
+	return nil
+}
+
+func envFloat(key string, dst *float64) error {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return nil
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return fmt.Errorf("%s: %q is not a number", key, v)
+	}
+	*dst = f
+	return nil
+}
+
+func envDuration(key string, dst *time.Duration) error {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return fmt.Errorf("%s: %q is not a duration (e.g. 30s, 5m)", key, v)
 	}
-	return fallback
+	*dst = d
+	return nil
 }
@@ -0,0 +1,138 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfigFile(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatalf("write config file: %v", err)
+	}
+	return path
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	cfg, err := Load(nil)
+	if err != nil {
+		t.Fatalf("Expected defaults to be valid, got: %v", err)
+	}
+	if cfg.Port != "8080" {
+		t.Errorf("Expected default port 8080, got '%s'", cfg.Port)
+	}
+	if cfg.Redis.QueueName != "weather:jobs" {
+		t.Errorf("Expected default queue 'weather:jobs', got '%s'", cfg.Redis.QueueName)
+	}
+	if cfg.CacheTTL != 5*time.Minute {
+		t.Errorf("Expected default cache TTL 5m, got %s", cfg.CacheTTL)
+	}
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	path := writeConfigFile(t, `
+port: "9000"
+worker_count: 4
+cache_ttl: 1m
+redis:
+  addr: file-redis:6379
+  queue_name: file:jobs
+`)
+	t.Setenv("WORKER_COUNT", "6")
+	t.Setenv("REDIS_ADDR", "env-redis:6379")
+
+	cfg, err := Load([]string{"-config", path, "-redis-addr", "flag-redis:6379"})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	// File beats defaults.
+	if cfg.Port != "9000" || cfg.CacheTTL != time.Minute || cfg.Redis.QueueName != "file:jobs" {
+		t.Errorf("Expected file values, got port=%s ttl=%s queue=%s", cfg.Port, cfg.CacheTTL, cfg.Redis.QueueName)
+	}
+	// Env beats file.
+	if cfg.WorkerCount != 6 {
+		t.Errorf("Expected env worker_count 6, got %d", cfg.WorkerCount)
+	}
+	// Flags beat env.
+	if cfg.Redis.Addr != "flag-redis:6379" {
+		t.Errorf("Expected flag redis addr, got '%s'", cfg.Redis.Addr)
+	}
+	if cfg.File != path {
+		t.Errorf("Expected File '%s', got '%s'", path, cfg.File)
+	}
+}
+
+func TestLoad_ConfigFileFromEnv(t *testing.T) {
+	path := writeConfigFile(t, "log_level: debug\n")
+	t.Setenv("CONFIG_FILE", path)
+
+	cfg, err := Load(nil)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.LogLevel != "debug" {
+		t.Errorf("Expected log_level 'debug' from CONFIG_FILE, got '%s'", cfg.LogLevel)
+	}
+}
+
+func TestLoad_UnknownFileField(t *testing.T) {
+	path := writeConfigFile(t, "wokrer_count: 3\n")
+
+	if _, err := Load([]string{"-config", path}); err == nil {
+		t.Fatal("Expected error for misspelled config key, got nil")
+	}
+}
+
+func TestLoad_BadEnvValue(t *testing.T) {
+	t.Setenv("CACHE_TTL", "five minutes")
+
+	_, err := Load(nil)
+	if err == nil || !strings.Contains(err.Error(), "CACHE_TTL") {
+		t.Fatalf("Expected CACHE_TTL parse error, got: %v", err)
+	}
+}
+
+func TestValidate_ReportsAllErrors(t *testing.T) {
+	cfg := Default()
+	cfg.Port = "70000"
+	cfg.LogLevel = "loud"
+	cfg.WorkerCount = 0
+	cfg.CacheTTL = 0
+	cfg.Redis.Addr = "no-port"
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("Expected validation error, got nil")
+	}
+	for _, field := range []string{"port", "log_level", "worker_count", "cache_ttl", "redis.addr"} {
+		if !strings.Contains(err.Error(), field) {
+			t.Errorf("Expected error to mention '%s', got: %v", field, err)
+		}
+	}
+}
+
+func TestRedacted_MasksSecrets(t *testing.T) {
+	cfg := Default()
+	cfg.WeatherAPIKey = "super-secret"
+	cfg.Redis.Password = "hunter2"
+
+	view := cfg.Redacted()
+	if view["weather_api_key"] != redacted {
+		t.Errorf("Expected weather_api_key to be redacted, got '%v'", view["weather_api_key"])
+	}
+	redisView, ok := view["redis"].(map[string]interface{})
+	if !ok {
+		t.Fatal("Expected redis section in redacted view")
+	}
+	if redisView["password"] != redacted {
+		t.Errorf("Expected redis password to be redacted, got '%v'", redisView["password"])
+	}
+	if view["cache_ttl"] != "5m0s" {
+		t.Errorf("Expected cache_ttl rendered as duration string, got '%v'", view["cache_ttl"])
+	}
+}
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckReload_SafeChangesAllowed(t *testing.T) {
+	cur := Default()
+	next := Default()
+	next.CacheTTL = time.Minute
+	next.WorkerCount = 8
+	next.RateLimitRPS = 100
+	next.LogLevel = "debug"
+
+	if err := cur.CheckReload(next); err != nil {
+		t.Errorf("Expected safe changes to be accepted, got: %v", err)
+	}
+}
+
+func TestCheckReload_UnsafeChangesRejected(t *testing.T) {
+	cur := Default()
+	next := Default()
+	next.Port = "9090"
+	next.Redis.Addr = "other:6379"
+
+	err := cur.CheckReload(next)
+	if err == nil {
+		t.Fatal("Expected unsafe changes to be rejected, got nil")
+	}
+	for _, field := range []string{"port", "redis"} {
+		if !strings.Contains(err.Error(), field) {
+			t.Errorf("Expected error to mention '%s', got: %v", field, err)
+		}
+	}
+}
+
+func TestReloader_AppliesFileChange(t *testing.T) {
+	path := writeConfigFile(t, "worker_count: 2\n")
+	args := []string{"-config", path}
+	cfg, err := Load(args)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	var applied *Config
+	r := NewReloader(cfg, args, func(next *Config) { applied = next })
+
+	if err := os.WriteFile(path, []byte("worker_count: 5\ncache_ttl: 30s\n"), 0o600); err != nil {
+		t.Fatalf("rewrite config: %v", err)
+	}
+	if err := r.Reload(); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if applied == nil || applied.WorkerCount != 5 || applied.CacheTTL != 30*time.Second {
+		t.Fatalf("Expected apply to receive new values, got %+v", applied)
+	}
+	if r.Current() != applied {
+		t.Error("Expected Current to return the reloaded config")
+	}
+}
+
+func TestReloader_KeepsOldConfigOnRejection(t *testing.T) {
+	path := writeConfigFile(t, "port: \"8080\"\n")
+	args := []string{"-config", path}
+	cfg, err := Load(args)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	called := false
+	r := NewReloader(cfg, args, func(*Config) { called = true })
+
+	if err := os.WriteFile(path, []byte("port: \"9999\"\n"), 0o600); err != nil {
+		t.Fatalf("rewrite config: %v", err)
+	}
+	if err := r.Reload(); err == nil {
+		t.Fatal("Expected port change to be rejected, got nil")
+	}
+	if called {
+		t.Error("Expected apply not to run for a rejected reload")
+	}
+	if r.Current().Port != "8080" {
+		t.Errorf("Expected old port to be kept, got '%s'", r.Current().Port)
+	}
+}
+
+func TestReloader_InvalidConfigFails(t *testing.T) {
+	path := writeConfigFile(t, "worker_count: 2\n")
+	args := []string{"-config", path}
+	cfg, err := Load(args)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	r := NewReloader(cfg, args, nil)
+
+	if err := os.WriteFile(path, []byte("worker_count: 0\n"), 0o600); err != nil {
+		t.Fatalf("rewrite config: %v", err)
+	}
+	if err := r.Reload(); err == nil {
+		t.Fatal("Expected invalid worker_count to fail reload, got nil")
+	}
+	if r.Current().WorkerCount != 2 {
+		t.Errorf("Expected old worker_count to be kept, got %d", r.Current().WorkerCount)
+	}
+}
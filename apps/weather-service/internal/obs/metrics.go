@@ -1,6 +1,8 @@
 package obs
 
 import (
+	"sync"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
@@ -58,4 +60,91 @@ var (
 		},
 		[]string{"outcome"}, // "success" or "error"
 	)
+	JobWaitDuration = promauto.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "weather_queue_job_wait_seconds",
+			Help:    "Time a job spent in the queue between enqueue and a worker popping it.",
+			Buckets: []float64{.01, .05, .1, .5, 1, 2.5, 5, 10, 30, 60, 120, 300, 600},
+		},
+	)
+	JobProcessingDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "weather_queue_job_processing_seconds",
+			Help:    "Time a worker spent processing a job after popping it.",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"outcome"},
+	)
+	JobsByLocationTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "weather_queue_jobs_by_location_total",
+			Help: "Jobs processed by location and outcome (locations beyond the first 100 are folded into \"other\").",
+		},
+		[]string{"location", "outcome"},
+	)
+	WorkerBusy = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "weather_queue_worker_busy",
+			Help: "1 while the worker is processing a job, 0 while idle (waiting on BRPOP or paused).",
+		},
+		[]string{"worker"},
+	)
+	QueueOldestJobAge = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "weather_queue_oldest_job_age_seconds",
+			Help: "Age of the next job to be processed (0 when empty, -1 on Redis error). Lag signal for alerts.",
+		},
+	)
+	QueuePaused = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "weather_queue_paused",
+			Help: "1 while queue workers are paused cluster-wide via POST /queue/pause.",
+		},
+	)
+	QueueDraining = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "weather_queue_draining",
+			Help: "1 while the queue is draining (new enqueues rejected) via POST /queue/drain.",
+		},
+	)
+
+	// Config Metrics (hot reload visibility)
+	ConfigReloadsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "weather_service_config_reloads_total",
+			Help: "Total config reload attempts by result.",
+		},
+		[]string{"result"}, // "success", "failure" (invalid config), or "rejected" (restart-only change)
+	)
+
+	RateLimitedTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "weather_service_rate_limited_total",
+			Help: "Total HTTP requests rejected with 429 by the rate limiter.",
+		},
+	)
 )
+
+// maxLocationLabels bounds the cardinality of the location label; jobs can be
+// enqueued with arbitrary locations via /queue/enqueue.
+const maxLocationLabels = 100
+
+var locationLabels = struct {
+	sync.Mutex
+	seen map[string]bool
+}{seen: map[string]bool{}}
+
+// LocationLabel returns loc for the first maxLocationLabels distinct locations
+// and "other" afterwards.
+func LocationLabel(loc string) string {
+	locationLabels.Lock()
+	defer locationLabels.Unlock()
+	if locationLabels.seen[loc] {
+		return loc
+	}
+	if len(locationLabels.seen) >= maxLocationLabels {
+		return "other"
+	}
+	locationLabels.seen[loc] = true
+	return loc
+}
@@ -3,8 +3,9 @@ package queue
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log/slog"
-	"os"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -14,60 +15,115 @@ const defaultQueueName = "weather:jobs"
 
 // Job represents a weather lookup request in the queue.
 type Job struct {
-	Location string `json:"location"`
-	Chaos    bool   `json:"chaos"`
+	Location   string    `json:"location"`
+	Chaos      bool      `json:"chaos"`
+	EnqueuedAt time.Time `json:"enqueued_at,omitempty"` // stamped by Push/PushMany
 }
 
+// State is the cluster-wide queue mode, stored in Redis so every replica sees it.
+type State string
+
+const (
+	StateRunning  State = "running"  // workers pop, enqueues accepted
+	StatePaused   State = "paused"   // workers idle, enqueues accepted
+	StateDraining State = "draining" // workers pop, enqueues rejected
+)
+
+// ErrDraining is returned by Push/PushMany while the queue is draining.
+var ErrDraining = errors.New("queue is draining; new jobs are rejected")
+
 // Client wraps Redis operations for the weather job queue.
 type Client struct {
 	rdb  *redis.Client
 	name string
 }
 
-// NewClient creates a Redis queue client. Uses REDIS_ADDR and REDIS_QUEUE_NAME from env.
-func NewClient() *Client {
-	addr := getEnv("REDIS_ADDR", "localhost:6379")
-	name := getEnv("REDIS_QUEUE_NAME", defaultQueueName)
+// Options configures the Redis connection and queue name.
+type Options struct {
+	Addr         string
+	Password     string
+	DB           int
+	QueueName    string
+	DialTimeout  time.Duration
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+}
+
+// NewClient creates a Redis queue client from the given options.
+func NewClient(opts Options) *Client {
+	name := opts.QueueName
+	if name == "" {
+		name = defaultQueueName
+	}
 
 	rdb := redis.NewClient(&redis.Options{
-		Addr:         addr,
-		DialTimeout:  5 * time.Second,
-		ReadTimeout:  10 * time.Second,
-		WriteTimeout: 5 * time.Second,
+		Addr:         opts.Addr,
+		Password:     opts.Password,
+		DB:           opts.DB,
+		DialTimeout:  opts.DialTimeout,
+		ReadTimeout:  opts.ReadTimeout,
+		WriteTimeout: opts.WriteTimeout,
 	})
 
 	return &Client{rdb: rdb, name: name}
 }
 
+// Name returns the Redis list key backing this queue.
+func (c *Client) Name() string {
+	return c.name
+}
+
+func (c *Client) stateKey() string {
+	return c.name + ":state"
+}
+
 // Push adds a job to the queue (left push for FIFO with BRPOP).
 func (c *Client) Push(ctx context.Context, job *Job) error {
-	data, err := json.Marshal(job)
-	if err != nil {
-		return err
-	}
-	return c.rdb.LPush(ctx, c.name, data).Err()
+	_, err := c.PushMany(ctx, []*Job{job})
+	return err
 }
 
 // PushMany bulk-pushes jobs to the queue for fast chaos loading.
+// Jobs without an EnqueuedAt are stamped with the current time.
 func (c *Client) PushMany(ctx context.Context, jobs []*Job) (int, error) {
 	if len(jobs) == 0 {
 		return 0, nil
 	}
+	state, err := c.State(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if state == StateDraining {
+		return 0, ErrDraining
+	}
+	now := time.Now().UTC()
 	args := make([]interface{}, len(jobs))
 	for i, job := range jobs {
+		if job.EnqueuedAt.IsZero() {
+			job.EnqueuedAt = now
+		}
 		data, err := json.Marshal(job)
 		if err != nil {
 			return i, err
 		}
 		args[i] = data
 	}
-	err := c.rdb.LPush(ctx, c.name, args...).Err()
-	if err != nil {
+	if err := c.rdb.LPush(ctx, c.name, args...).Err(); err != nil {
 		return 0, err
 	}
 	return len(jobs), nil
 }
 
+// Requeue puts a popped job back at the front of the queue (next to be popped),
+// keeping its original EnqueuedAt. Used when a worker pops just as the queue pauses.
+func (c *Client) Requeue(ctx context.Context, job *Job) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	return c.rdb.RPush(ctx, c.name, data).Err()
+}
+
 // Pop blocks until a job is available, or context is cancelled.
 func (c *Client) Pop(ctx context.Context) (*Job, error) {
 	res, err := c.rdb.BRPop(ctx, 0, c.name).Result()
@@ -90,14 +146,82 @@ func (c *Client) Len(ctx context.Context) (int64, error) {
 	return c.rdb.LLen(ctx, c.name).Result()
 }
 
-// Close closes the Redis connection.
-func (c *Client) Close() error {
-	return c.rdb.Close()
+// Peek returns up to n jobs in the order they will be processed, without removing them.
+func (c *Client) Peek(ctx context.Context, n int64) ([]*Job, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+	// LPUSH + BRPOP: the tail of the list is the head of the queue.
+	raw, err := c.rdb.LRange(ctx, c.name, -n, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	jobs := make([]*Job, 0, len(raw))
+	for i := len(raw) - 1; i >= 0; i-- {
+		var job Job
+		if err := json.Unmarshal([]byte(raw[i]), &job); err != nil {
+			return nil, fmt.Errorf("decode queued job: %w", err)
+		}
+		jobs = append(jobs, &job)
+	}
+	return jobs, nil
+}
+
+// Purge deletes every queued job and returns how many were removed.
+func (c *Client) Purge(ctx context.Context) (int64, error) {
+	var length *redis.IntCmd
+	_, err := c.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		length = pipe.LLen(ctx, c.name)
+		pipe.Del(ctx, c.name)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return length.Val(), nil
+}
+
+// OldestAge returns how long the next job to be processed has been waiting.
+// ok is false when the queue is empty or the job predates enqueue stamping.
+func (c *Client) OldestAge(ctx context.Context) (age time.Duration, ok bool, err error) {
+	raw, err := c.rdb.LIndex(ctx, c.name, -1).Result()
+	if errors.Is(err, redis.Nil) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	var job Job
+	if err := json.Unmarshal([]byte(raw), &job); err != nil {
+		return 0, false, fmt.Errorf("decode oldest job: %w", err)
+	}
+	if job.EnqueuedAt.IsZero() {
+		return 0, false, nil
+	}
+	return time.Since(job.EnqueuedAt), true, nil
 }
 
-func getEnv(key, fallback string) string {
-	if v := os.Getenv(key); v != "" {
-		return v
+// State returns the cluster-wide queue mode.
+func (c *Client) State(ctx context.Context) (State, error) {
+	v, err := c.rdb.Get(ctx, c.stateKey()).Result()
+	if errors.Is(err, redis.Nil) || v == "" {
+		return StateRunning, nil
+	}
+	if err != nil {
+		return "", err
 	}
-	return fallback
+	return State(v), nil
+}
+
+// SetState switches the cluster-wide queue mode. Running clears the flag.
+func (c *Client) SetState(ctx context.Context, s State) error {
+	if s == StateRunning {
+		return c.rdb.Del(ctx, c.stateKey()).Err()
+	}
+	return c.rdb.Set(ctx, c.stateKey(), string(s), 0).Err()
+}
+
+// Close closes the Redis connection.
+func (c *Client) Close() error {
+	return c.rdb.Close()
 }
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 type contextKey string
@@ -16,13 +18,25 @@ type WeatherData struct {
 }
 
 type Client struct {
-	cache sync.Map
+	cache    sync.Map
+	cachedAt sync.Map     // location -> time.Time the entry was stored
+	ttl      atomic.Int64 // cache TTL in nanoseconds; 0 = never expire
 }
 
 func NewClient() *Client {
 	return &Client{}
 }
 
+// SetCacheTTL changes how long cached entries stay fresh. Safe to call while serving.
+func (c *Client) SetCacheTTL(ttl time.Duration) {
+	c.ttl.Store(int64(ttl))
+}
+
+// CacheTTL returns the current cache TTL (0 = never expire).
+func (c *Client) CacheTTL() time.Duration {
+	return time.Duration(c.ttl.Load())
+}
+
 func WithChaosTrigger(ctx context.Context, value string) context.Context {
 	return context.WithValue(ctx, chaosTriggerContextKey, value)
 }
@@ -41,7 +55,7 @@ func (c *Client) GetWeather(ctx context.Context, location string) (*WeatherData,
 	}
 
 	// Normal Cache Logic
-	if val, ok := c.cache.Load(location); ok {
+	if val, ok := c.cache.Load(location); ok && !c.expired(location) {
 		data, typeOK := val.(WeatherData)
 		if !typeOK {
 			return nil, fmt.Errorf("invalid cached weather data type")
@@ -51,5 +65,21 @@ func (c *Client) GetWeather(ctx context.Context, location string) (*WeatherData,
 
 	data := WeatherData{Temperature: 72.0, Conditions: "Sunny"}
 	c.cache.Store(location, data)
+	c.cachedAt.Store(location, time.Now())
 	return &data, nil
 }
+
+// expired reports whether the cached entry for location is older than the TTL.
+// Entries without a timestamp (e.g. primed directly) never expire.
+func (c *Client) expired(location string) bool {
+	ttl := c.CacheTTL()
+	if ttl <= 0 {
+		return false
+	}
+	at, ok := c.cachedAt.Load(location)
+	if !ok {
+		return false
+	}
+	storedAt, typeOK := at.(time.Time)
+	return typeOK && time.Since(storedAt) > ttl
+}
@@ -3,6 +3,7 @@ package weather
 import (
 	"context"
 	"testing"
+	"time"
 )
 
 func TestGetWeather_CacheHit(t *testing.T) {
@@ -127,3 +128,29 @@ func TestGetWeather_ChaosFalse(t *testing.T) {
 		t.Fatal("Expected data, got nil")
 	}
 }
+
+func TestGetWeather_CacheTTLExpiry(t *testing.T) {
+	client := NewClient()
+	client.SetCacheTTL(time.Minute)
+	location := "amarillo"
+	ctx := context.Background()
+
+	if _, err := client.GetWeather(ctx, location); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if client.expired(location) {
+		t.Fatal("Expected fresh entry not to be expired")
+	}
+
+	// Backdate the entry past the TTL.
+	client.cachedAt.Store(location, time.Now().Add(-2*time.Minute))
+	if !client.expired(location) {
+		t.Error("Expected entry older than TTL to be expired")
+	}
+
+	// Disabling the TTL makes every entry fresh again.
+	client.SetCacheTTL(0)
+	if client.expired(location) {
+		t.Error("Expected TTL 0 to disable expiry")
+	}
+}